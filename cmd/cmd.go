@@ -3,6 +3,7 @@ package cmd
 import (
 	"os"
 	"os/exec"
+	"strings"
 
 	"github.com/kballard/go-shellquote"
 )
@@ -13,6 +14,7 @@ type Cmd interface {
 	WithArgs(args ...string) Cmd
 	CombinedOutput() (string, error)
 	Spawn() error
+	SpawnWithInput(input string) error
 }
 
 type BasicCmd struct {
@@ -67,3 +69,13 @@ func (cmd *BasicCmd) Spawn() error {
 	c.Stderr = cmd.Stderr
 	return c.Run()
 }
+
+// SpawnWithInput runs the command like Spawn, reading input as the standard
+// input.
+func (cmd *BasicCmd) SpawnWithInput(input string) error {
+	c := exec.Command(cmd.Name, cmd.Args...)
+	c.Stdin = strings.NewReader(input)
+	c.Stdout = cmd.Stdout
+	c.Stderr = cmd.Stderr
+	return c.Run()
+}
@@ -48,3 +48,7 @@ func (cmd *MockCmd) CombinedOutput() (string, error) {
 func (cmd *MockCmd) Spawn() error {
 	return nil
 }
+
+func (cmd *MockCmd) SpawnWithInput(input string) error {
+	return nil
+}
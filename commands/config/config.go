@@ -2,6 +2,7 @@ package config
 
 import (
 	"bytes"
+	"fmt"
 
 	flags "github.com/jessevdk/go-flags"
 	"github.com/lighttiger2505/lab/internal/config"
@@ -15,7 +16,8 @@ const (
 )
 
 type Option struct {
-	List bool `short:"l" long:"list" description:"Show config."`
+	List         bool   `short:"l" long:"list" description:"Show config."`
+	MigrateToken string `long:"migrate-token" value-name:"<store>" choice:"keyring" choice:"git" description:"Move the tokens in the config file to the Secret Service keyring or the git credential helpers."`
 }
 
 func newOptionParser(opt *Option) *flags.Parser {
@@ -27,7 +29,10 @@ Synopsis:
   lab config
 
   # Show config
-  lab config -l`
+  lab config -l
+
+  # Move the plain text tokens to the keyring
+  lab config --migrate-token=keyring`
 	return parser
 }
 
@@ -57,6 +62,10 @@ func (c *ConfigCommand) Run(args []string) int {
 		return ExitCodeError
 	}
 
+	if opt.MigrateToken != "" {
+		return c.migrateToken(opt.MigrateToken)
+	}
+
 	if opt.List {
 		cfgStr, err := c.Config.Read()
 		if err != nil {
@@ -70,3 +79,25 @@ func (c *ConfigCommand) Run(args []string) int {
 
 	return ExitCodeOK
 }
+
+func (c *ConfigCommand) migrateToken(store string) int {
+	migrated, err := c.Config.MigrateTokens(store)
+	// The tokens moved before an error are saved too
+	if len(migrated) > 0 {
+		if err := c.Config.Save(); err != nil {
+			c.UI.Error(err.Error())
+			return ExitCodeError
+		}
+	}
+	for _, name := range migrated {
+		c.UI.Message(fmt.Sprintf("Moved the token of %s to %s.", name, store))
+	}
+	if err != nil {
+		c.UI.Error(err.Error())
+		return ExitCodeError
+	}
+	if len(migrated) == 0 {
+		c.UI.Message("No token in the config file.")
+	}
+	return ExitCodeOK
+}
@@ -4,6 +4,12 @@ const (
 	ExitCodeOK        int = iota //0
 	ExitCodeError     int = iota //1
 	ExitCodeFileError int = iota //2
+	// ExitCodeJobFailed is returned when the job followed by the log
+	// option failed
+	ExitCodeJobFailed int = iota //3
+	// ExitCodeJobCanceled is returned when the job followed by the log
+	// option was canceled
+	ExitCodeJobCanceled int = iota //4
 )
 const IssueTemplateDir = ".gitlab/issue_templates"
 const MergeRequestTemplateDir = ".gitlab/merge_request_templates"
@@ -0,0 +1,170 @@
+package internal
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/lighttiger2505/lab/internal/ui"
+)
+
+// progressStep is the number of bytes between the progress lines when the
+// size of the download is unknown.
+const progressStep = 1 << 20
+
+// DownloadArtifacts downloads an artifacts archive by download and extracts
+// it into dir, and returns the paths of the extracted files. The archive is
+// verified against size, unless size is zero, and the CRC-32 of every file.
+func DownloadArtifacts(u ui.UI, download func(io.Writer) error, size int64, dir string) ([]string, error) {
+	archive, err := ioutil.TempFile("", "lab-artifacts")
+	if err != nil {
+		return nil, fmt.Errorf("Failed create temporary file. %s", err.Error())
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	written, err := downloadTo(u, archive, download, size)
+	if err != nil {
+		return nil, err
+	}
+	if size > 0 && written != size {
+		return nil, fmt.Errorf("Failed verify artifacts. Downloaded %d bytes, want %d bytes", written, size)
+	}
+
+	return ExtractArtifacts(archive.Name(), dir)
+}
+
+// DownloadArtifactFile downloads a single file of artifacts by download
+// into dir, and returns the path of the file.
+func DownloadArtifactFile(u ui.UI, download func(io.Writer) error, artifactPath string, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	filePath := filepath.Join(dir, path.Base(artifactPath))
+	file, err := os.Create(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if _, err := downloadTo(u, file, download, 0); err != nil {
+		os.Remove(filePath)
+		return "", err
+	}
+	return filePath, nil
+}
+
+// downloadTo writes the download to w printing the progress and the SHA-256
+// of the download, and returns the number of the written bytes.
+func downloadTo(u ui.UI, w io.Writer, download func(io.Writer) error, size int64) (int64, error) {
+	hash := sha256.New()
+	progress := &progressWriter{ui: u, size: size}
+	if err := download(io.MultiWriter(w, hash, progress)); err != nil {
+		return 0, err
+	}
+	u.Error(fmt.Sprintf("Downloaded %s, SHA-256 %x", formatBytes(progress.written), hash.Sum(nil)))
+	return progress.written, nil
+}
+
+// ExtractArtifacts extracts an artifacts archive into dir, and returns the
+// paths of the extracted files.
+func ExtractArtifacts(archive string, dir string) ([]string, error) {
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return nil, fmt.Errorf("Failed open artifacts. %s", err.Error())
+	}
+	defer r.Close()
+
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var extracted []string
+	for _, f := range r.File {
+		target := filepath.Join(dir, f.Name)
+		abs, err := filepath.Abs(target)
+		if err != nil {
+			return nil, err
+		}
+		if abs != root && !strings.HasPrefix(abs, root+string(filepath.Separator)) {
+			return nil, fmt.Errorf("Failed extract artifacts. %s is outside of %s", f.Name, dir)
+		}
+
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := extractFile(f, target); err != nil {
+			return nil, err
+		}
+		extracted = append(extracted, target)
+	}
+	return extracted, nil
+}
+
+func extractFile(f *zip.File, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("Failed extract artifacts. %s", err.Error())
+	}
+	defer rc.Close()
+
+	file, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode().Perm()|0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// The reader of the zip file fails when the CRC-32 of the file differs
+	if _, err := io.Copy(file, rc); err != nil {
+		return fmt.Errorf("Failed verify artifacts. %s: %s", f.Name, err.Error())
+	}
+	return nil
+}
+
+// progressWriter prints the progress of a download every ten percent, or
+// every progressStep bytes when the size is unknown.
+type progressWriter struct {
+	ui      ui.UI
+	size    int64
+	written int64
+	printed int64
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.written += int64(len(p))
+	if w.size > 0 {
+		percent := w.written * 100 / w.size
+		if percent/10 > w.printed/10 && percent < 100 {
+			w.printed = percent
+			w.ui.Error(fmt.Sprintf("Downloading %d%% (%s / %s)", percent, formatBytes(w.written), formatBytes(w.size)))
+		}
+	} else if w.written/progressStep > w.printed/progressStep {
+		w.printed = w.written
+		w.ui.Error(fmt.Sprintf("Downloading %s", formatBytes(w.written)))
+	}
+	return len(p), nil
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
@@ -0,0 +1,114 @@
+package internal
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/lighttiger2505/lab/internal/ui"
+)
+
+func makeArchive(t *testing.T, files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	w := zip.NewWriter(buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func Test_DownloadArtifacts(t *testing.T) {
+	archive := makeArchive(t, map[string]string{"bin/app": "app"})
+	tests := []struct {
+		name    string
+		files   []byte
+		size    int64
+		want    []string
+		wantErr string
+	}{
+		{
+			name:  "extract",
+			files: archive,
+			size:  int64(len(archive)),
+			want:  []string{filepath.Join("bin", "app")},
+		},
+		{
+			name:  "unknown size",
+			files: archive,
+			want:  []string{filepath.Join("bin", "app")},
+		},
+		{
+			name:    "size mismatch",
+			files:   archive[:len(archive)-1],
+			size:    int64(len(archive)),
+			wantErr: "Failed verify artifacts",
+		},
+		{
+			name:    "outside of dir",
+			files:   makeArchive(t, map[string]string{"../app": "app"}),
+			wantErr: "is outside of",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "lab-test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			got, err := DownloadArtifacts(
+				ui.NewMockUi(),
+				func(w io.Writer) error {
+					_, err := w.Write(tt.files)
+					return err
+				},
+				tt.size,
+				dir,
+			)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("DownloadArtifacts() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DownloadArtifacts() error = %v", err)
+			}
+			for i := range got {
+				got[i], _ = filepath.Rel(dir, got[i])
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("DownloadArtifacts() (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_progressWriter(t *testing.T) {
+	mockUI := ui.NewMockUi()
+	w := &progressWriter{ui: mockUI, size: 2048}
+	for i := 0; i < 4; i++ {
+		w.Write(make([]byte, 512))
+	}
+	got := mockUI.ErrorWriter.String()
+	want := "Downloading 25% (512 B / 2.0 KiB)\nDownloading 50% (1.0 KiB / 2.0 KiB)\nDownloading 75% (1.5 KiB / 2.0 KiB)\n"
+	if got != want {
+		t.Errorf("progressWriter printed %q, want %q", got, want)
+	}
+}
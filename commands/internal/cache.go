@@ -0,0 +1,27 @@
+package internal
+
+import (
+	"errors"
+
+	"github.com/lighttiger2505/lab/internal/api"
+	"github.com/lighttiger2505/lab/internal/cache"
+)
+
+// CacheOption reads the issues, merge requests and pipelines kept by
+// "lab sync" instead of GitLab.
+type CacheOption struct {
+	Offline bool `long:"offline" description:"Read the issues, merge requests and pipelines from the local cache instead of GitLab. Run \"lab sync\" to refresh the cache."`
+}
+
+// CacheClientFactory returns the factory keeping what the clients read in the
+// store of the profile, or reading only the store with --offline. The factory
+// is returned as it is when there is no store.
+func CacheClientFactory(factory api.APIClientFactory, store *cache.Store, profile string, opt *CacheOption) (api.APIClientFactory, error) {
+	if store == nil {
+		if opt.Offline {
+			return nil, errors.New("Cannot work offline without the cache")
+		}
+		return factory, nil
+	}
+	return cache.NewClientFactory(factory, store, profile, opt.Offline), nil
+}
@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lighttiger2505/lab/git"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+const commentInstruction = `<!-- Write the comment in Markdown above. The comments like this are removed. -->`
+
+// CommentOption is the options writing the comments of issues and merge
+// requests. The message option of each command gives the body.
+type CommentOption struct {
+	Comment    bool `long:"comment" description:"Add a comment. Start the editor unless the message option is given."`
+	EditNote   int  `long:"edit-note" value-name:"<note id>" description:"Edit the comment on editor, or replace it with the message option."`
+	DeleteNote int  `long:"delete-note" value-name:"<note id>" description:"Delete the comment."`
+	Quote      int  `long:"quote" value-name:"<note id>" description:"Add a comment quoting the given comment."`
+}
+
+func (o *CommentOption) HasComment() bool {
+	if o.Comment || o.EditNote != 0 || o.DeleteNote != 0 || o.Quote != 0 {
+		return true
+	}
+	return false
+}
+
+// QuoteNote returns the body of the note as a Markdown quote.
+func QuoteNote(note *gitlab.Note) string {
+	lines := strings.Split(strings.TrimSpace(note.Body), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("> "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// NewComment returns the body of a new comment. The editor is started with
+// the quote when the message is empty.
+func NewComment(quote, message string, editFunc func(program, file string) error) (string, error) {
+	if message == "" {
+		return EditComment(quote, editFunc)
+	}
+	if quote == "" {
+		return message, nil
+	}
+	return quote + "\n\n" + message, nil
+}
+
+// EditComment starts the editor with the content, and returns the edited
+// comment without the instructions.
+func EditComment(content string, editFunc func(program, file string) error) (string, error) {
+	template := commentInstruction
+	if content != "" {
+		template = fmt.Sprintf("%s\n\n%s", content, commentInstruction)
+	}
+
+	editor, err := git.NewEditor("COMMENT", "comment", template, editFunc)
+	if err != nil {
+		return "", err
+	}
+	defer editor.DeleteFile()
+
+	edited, err := editor.EditContent()
+	if err != nil {
+		return "", err
+	}
+	body := strings.TrimSpace(SweepMarkdownComment(edited))
+	if body == "" {
+		return "", fmt.Errorf("Aborting the comment due to empty message")
+	}
+	return body, nil
+}
@@ -0,0 +1,20 @@
+package internal
+
+// ExitError is an error with the exit code of the command, for failures that
+// scripts need to tell apart.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+// ExitCode returns the exit code of an ExitError, or code for other errors.
+func ExitCode(err error, code int) int {
+	if e, ok := err.(*ExitError); ok {
+		return e.Code
+	}
+	return code
+}
@@ -1,5 +1,7 @@
 package internal
 
+import "github.com/lighttiger2505/lab/internal/ui"
+
 type ProjectProfileOption struct {
 	Project string `long:"project" value-name:"<group>/<name>" description:"Specify the project to be processed"`
 	Profile string `long:"profile" value-name:"<profile>" description:"Specify the profile defined in the config file"`
@@ -17,3 +19,19 @@ func (b *BrowseOption) HasBrowse() bool {
 	}
 	return false
 }
+
+type OutputOption struct {
+	Output string `long:"output" value-name:"<format>" default:"table" default-mask:"table" choice:"table" choice:"json" choice:"yaml" choice:"tsv" description:"Output format. \"table\", \"json\", \"yaml\" or \"tsv\""`
+	Format string `long:"format" value-name:"<template>" description:"Pretty-print each result using a Go template. Functions: join, color, truncate, timeago"`
+	// ui receives the pages of a list as they arrive, see Stream
+	ui ui.UI
+}
+
+// Limit returns the number of items to read for the --num and --all options.
+// Zero means every item.
+func Limit(num int, all bool) int {
+	if all {
+		return 0
+	}
+	return num
+}
@@ -35,7 +35,7 @@ func (m *BrowseMethod) Process() (string, error) {
 
 	if m.Opt.Copy {
 		if err := clipboard.WriteAll(url); err != nil {
-			return "", fmt.Errorf(fmt.Sprintf("Error copying %s to clipboard:\n%s\n", url, err))
+			return "", fmt.Errorf("Error copying %s to clipboard:\n%s\n", url, err)
 		}
 	}
 
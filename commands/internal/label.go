@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"strings"
+
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// SplitLabels returns the labels given by the repeated or comma separated
+// label options, e.g. "--label bug,ui --label doing".
+func SplitLabels(values []string) []string {
+	var labels []string
+	for _, value := range values {
+		for _, label := range strings.Split(value, ",") {
+			label = strings.TrimSpace(label)
+			if label != "" && !containsLabel(labels, label) {
+				labels = append(labels, label)
+			}
+		}
+	}
+	return labels
+}
+
+// ChangeLabels returns the labels with the added labels and without the
+// removed labels. The order of the labels is kept.
+func ChangeLabels(labels, add, remove []string) []string {
+	changed := []string{}
+	for _, values := range [][]string{labels, add} {
+		for _, label := range values {
+			if !containsLabel(remove, label) && !containsLabel(changed, label) {
+				changed = append(changed, label)
+			}
+		}
+	}
+	return changed
+}
+
+// MakeLabels returns the labels to update an issue or a merge request with.
+// GitLab removes every label by an empty label.
+func MakeLabels(labels []string) gitlab.Labels {
+	if len(labels) == 0 {
+		return gitlab.Labels{""}
+	}
+	return gitlab.Labels(labels)
+}
+
+func containsLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
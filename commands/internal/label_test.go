@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_SplitLabels(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   []string
+	}{
+		{
+			name:   "repeated",
+			values: []string{"bug", "doing"},
+			want:   []string{"bug", "doing"},
+		},
+		{
+			name:   "comma separated",
+			values: []string{"bug, ui", "doing,bug"},
+			want:   []string{"bug", "ui", "doing"},
+		},
+		{
+			name:   "empty",
+			values: []string{""},
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitLabels(tt.values)
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("SplitLabels() (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_ChangeLabels(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels []string
+		add    []string
+		remove []string
+		want   []string
+	}{
+		{
+			name:   "add and remove",
+			labels: []string{"bug", "doing"},
+			add:    []string{"done", "bug"},
+			remove: []string{"doing"},
+			want:   []string{"bug", "done"},
+		},
+		{
+			name:   "remove all",
+			labels: []string{"bug"},
+			remove: []string{"bug"},
+			want:   []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ChangeLabels(tt.labels, tt.add, tt.remove)
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("ChangeLabels() (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
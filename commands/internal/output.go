@@ -0,0 +1,237 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/lighttiger2505/lab/internal/ui"
+	"github.com/ryanuber/columnize"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+	OutputTSV   = "tsv"
+)
+
+// columnDelim separates the cells of a table row. It is a control character
+// so that cells containing "|" do not break the columns.
+const columnDelim = "\x1f"
+
+func (o *OutputOption) format() string {
+	if o == nil || o.Output == "" {
+		return OutputTable
+	}
+	return o.Output
+}
+
+func (o *OutputOption) template() string {
+	if o == nil {
+		return ""
+	}
+	return o.Format
+}
+
+// IsMachine reports whether the result is intended to be read by a program.
+func (o *OutputOption) IsMachine() bool {
+	return o.template() != "" || o.format() != OutputTable
+}
+
+// FormatList renders a list of GitLab objects. The rows function builds the
+// cells of the table and tsv outputs, json and yaml are marshaled from v.
+// A template given by --format is executed for each element of v.
+func (o *OutputOption) FormatList(v interface{}, rows func() [][]string) (string, error) {
+	if o.template() != "" {
+		return ExecuteTemplateEach(o.template(), v)
+	}
+	return o.render(v, func() string { return Columnize(rows()) }, rows)
+}
+
+// FormatDetail renders a single GitLab object. The text function builds the
+// human readable view that is printed as the table output.
+func (o *OutputOption) FormatDetail(v interface{}, text func() string, rows func() [][]string) (string, error) {
+	if o.template() != "" {
+		return ExecuteTemplate(o.template(), v)
+	}
+	return o.render(v, text, rows)
+}
+
+func (o *OutputOption) render(v interface{}, text func() string, rows func() [][]string) (string, error) {
+	switch o.format() {
+	case OutputJSON:
+		return marshalJSON(v)
+	case OutputYAML:
+		return marshalYAML(v)
+	case OutputTSV:
+		// Escape sequences are noise to the programs reading tsv
+		noColor := color.NoColor
+		color.NoColor = true
+		defer func() { color.NoColor = noColor }()
+		return TSV(rows()), nil
+	case OutputTable:
+		return text(), nil
+	}
+	return "", fmt.Errorf("unknown output format: %s", o.format())
+}
+
+// Write writes the rendered result to the UI. Machine readable results are
+// passed to UI.Machine to keep them apart from the messages for humans.
+func (o *OutputOption) Write(u ui.UI, res string) {
+	if res == "" {
+		return
+	}
+	if o.IsMachine() {
+		u.Machine(o.format(), res)
+		return
+	}
+	u.Message(res)
+}
+
+// Stream makes the list writers print each page to u as soon as it arrives,
+// instead of returning the whole list once the last page is read.
+func (o *OutputOption) Stream(u ui.UI) {
+	if o != nil {
+		o.ui = u
+	}
+}
+
+// ListWriter renders a list that is read page by page. Tsv and template
+// outputs are rendered for every page. Tables wait for the last page to align
+// the columns of every row, and json and yaml wait for it because a document
+// can not be split.
+type ListWriter struct {
+	opt   *OutputOption
+	items reflect.Value
+	rows  [][]string
+	pages []string
+}
+
+// NewListWriter returns a writer rendering the pages of a list.
+func (o *OutputOption) NewListWriter() *ListWriter {
+	return &ListWriter{opt: o}
+}
+
+func (w *ListWriter) isDocument() bool {
+	format := w.opt.format()
+	return w.opt.template() == "" && (format == OutputJSON || format == OutputYAML)
+}
+
+func (w *ListWriter) isTable() bool {
+	return w.opt.template() == "" && w.opt.format() == OutputTable
+}
+
+// WritePage renders a page of the list like FormatList. The page is printed
+// right away when the option streams to a UI, except for tables.
+func (w *ListWriter) WritePage(v interface{}, rows func() [][]string) error {
+	if w.isTable() {
+		w.rows = append(w.rows, rows()...)
+		return nil
+	}
+	if w.isDocument() {
+		page := reflect.ValueOf(v)
+		if !w.items.IsValid() {
+			w.items = reflect.MakeSlice(page.Type(), 0, page.Len())
+		}
+		w.items = reflect.AppendSlice(w.items, page)
+		return nil
+	}
+
+	res, err := w.opt.FormatList(v, rows)
+	if err != nil {
+		return err
+	}
+	if res == "" {
+		return nil
+	}
+	if w.opt != nil && w.opt.ui != nil {
+		w.opt.Write(w.opt.ui, res)
+		return nil
+	}
+	w.pages = append(w.pages, res)
+	return nil
+}
+
+// Flush returns the part of the list that has not been printed yet.
+func (w *ListWriter) Flush() (string, error) {
+	if w.isTable() {
+		return Columnize(w.rows), nil
+	}
+	if w.isDocument() {
+		if !w.items.IsValid() {
+			return w.opt.FormatList([]interface{}{}, nil)
+		}
+		return w.opt.FormatList(w.items.Interface(), nil)
+	}
+	return strings.Join(w.pages, "\n"), nil
+}
+
+// Columnize aligns the rows in columns like columnize.SimpleFormat.
+func Columnize(rows [][]string) string {
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		lines[i] = strings.Join(row, columnDelim)
+	}
+	config := columnize.DefaultConfig()
+	config.Delim = columnDelim
+	return columnize.Format(lines, config)
+}
+
+// TSV joins the rows with tabs. Tabs and line breaks inside a cell are
+// escaped so that every row stays on a single line.
+func TSV(rows [][]string) string {
+	replacer := strings.NewReplacer(
+		"\\", "\\\\",
+		"\t", "\\t",
+		"\n", "\\n",
+		"\r", "\\r",
+	)
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		cells := make([]string, len(row))
+		for j, cell := range row {
+			cells[j] = replacer.Replace(cell)
+		}
+		lines[i] = strings.Join(cells, "\t")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func marshalJSON(v interface{}) (string, error) {
+	b, err := json.MarshalIndent(emptyIfNil(v), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("Failed marshal json. %s", err)
+	}
+	return string(b), nil
+}
+
+func marshalYAML(v interface{}) (string, error) {
+	// Marshal through json so that the keys follow the json tags of go-gitlab
+	b, err := json.Marshal(emptyIfNil(v))
+	if err != nil {
+		return "", fmt.Errorf("Failed marshal yaml. %s", err)
+	}
+	var data interface{}
+	if err := yaml.Unmarshal(b, &data); err != nil {
+		return "", fmt.Errorf("Failed marshal yaml. %s", err)
+	}
+	out, err := yaml.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("Failed marshal yaml. %s", err)
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+// emptyIfNil replaces a nil slice by an empty one, an empty list is printed
+// as "[]" instead of "null".
+func emptyIfNil(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice && rv.IsNil() {
+		return reflect.MakeSlice(rv.Type(), 0, 0).Interface()
+	}
+	return v
+}
@@ -0,0 +1,184 @@
+package internal
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/lighttiger2505/lab/internal/ui"
+)
+
+type testItem struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+func Test_OutputOption_FormatList(t *testing.T) {
+	items := []*testItem{
+		&testItem{ID: 1, Title: "title|1"},
+		&testItem{ID: 2, Title: "title\t2"},
+	}
+	rows := func() [][]string {
+		var rows [][]string
+		for _, item := range items {
+			rows = append(rows, []string{
+				"#" + strconv.Itoa(item.ID),
+				item.Title,
+			})
+		}
+		return rows
+	}
+
+	tests := []struct {
+		name    string
+		opt     *OutputOption
+		value   interface{}
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "default is table",
+			opt:   nil,
+			value: items,
+			want:  "#1  title|1\n#2  title\t2",
+		},
+		{
+			name:  "table",
+			opt:   &OutputOption{Output: "table"},
+			value: items,
+			want:  "#1  title|1\n#2  title\t2",
+		},
+		{
+			name:  "json",
+			opt:   &OutputOption{Output: "json"},
+			value: items,
+			want: `[
+  {
+    "id": 1,
+    "title": "title|1"
+  },
+  {
+    "id": 2,
+    "title": "title\t2"
+  }
+]`,
+		},
+		{
+			name:  "json empty list",
+			opt:   &OutputOption{Output: "json"},
+			value: []*testItem(nil),
+			want:  "[]",
+		},
+		{
+			name:  "yaml",
+			opt:   &OutputOption{Output: "yaml"},
+			value: items,
+			want: `- id: 1
+  title: title|1
+- id: 2
+  title: "title\t2"`,
+		},
+		{
+			name:  "tsv",
+			opt:   &OutputOption{Output: "tsv"},
+			value: items,
+			want:  "#1\ttitle|1\n#2\ttitle\\t2",
+		},
+		{
+			name:    "unknown format",
+			opt:     &OutputOption{Output: "xml"},
+			value:   items,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.opt.FormatList(tt.value, rows)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("OutputOption.FormatList() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("OutputOption.FormatList() = \ngot: %#v\nwant:%#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ListWriter(t *testing.T) {
+	pages := [][]*testItem{
+		{&testItem{ID: 1, Title: "title1"}},
+		{&testItem{ID: 22, Title: "title22"}},
+	}
+
+	tests := []struct {
+		name       string
+		opt        *OutputOption
+		stream     bool
+		want       string
+		wantStream string
+	}{
+		{
+			name: "pages are joined",
+			opt:  nil,
+			want: "1   title1\n22  title22",
+		},
+		{
+			name:   "table is aligned across pages",
+			opt:    &OutputOption{Output: "table"},
+			stream: true,
+			want:   "1   title1\n22  title22",
+		},
+		{
+			name: "json is a single document",
+			opt:  &OutputOption{Output: "json"},
+			want: "[\n  {\n    \"id\": 1,\n    \"title\": \"title1\"\n  },\n  {\n    \"id\": 22,\n    \"title\": \"title22\"\n  }\n]",
+		},
+		{
+			name:       "pages are streamed",
+			opt:        &OutputOption{Output: "tsv"},
+			stream:     true,
+			want:       "",
+			wantStream: "1\ttitle1\n22\ttitle22\n",
+		},
+		{
+			name:       "template is streamed",
+			opt:        &OutputOption{Output: "json", Format: "{{.ID}}"},
+			stream:     true,
+			want:       "",
+			wantStream: "1\n22\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUI := ui.NewMockUi()
+			if tt.stream {
+				tt.opt.Stream(mockUI)
+			}
+
+			w := tt.opt.NewListWriter()
+			for _, page := range pages {
+				page := page
+				err := w.WritePage(page, func() [][]string {
+					var rows [][]string
+					for _, item := range page {
+						rows = append(rows, []string{strconv.Itoa(item.ID), item.Title})
+					}
+					return rows
+				})
+				if err != nil {
+					t.Fatalf("ListWriter.WritePage() error = %v", err)
+				}
+			}
+			got, err := w.Flush()
+			if err != nil {
+				t.Fatalf("ListWriter.Flush() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ListWriter.Flush() = \ngot: %#v\nwant:%#v", got, tt.want)
+			}
+			if gotStream := mockUI.Writer.String(); gotStream != tt.wantStream {
+				t.Errorf("streamed output = \ngot: %#v\nwant:%#v", gotStream, tt.wantStream)
+			}
+		})
+	}
+}
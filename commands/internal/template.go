@@ -0,0 +1,138 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+var colorAttributes = map[string]color.Attribute{
+	"black":   color.FgBlack,
+	"red":     color.FgRed,
+	"green":   color.FgGreen,
+	"yellow":  color.FgYellow,
+	"blue":    color.FgBlue,
+	"magenta": color.FgMagenta,
+	"cyan":    color.FgCyan,
+	"white":   color.FgWhite,
+}
+
+// For timeago test
+var now = time.Now
+
+// TemplateFuncs are the functions available in the --format template.
+var TemplateFuncs = template.FuncMap{
+	"join":     strings.Join,
+	"color":    colorize,
+	"truncate": truncate,
+	"timeago":  timeago,
+}
+
+// ParseTemplate parses a --format template. The escape sequences "\t" and
+// "\n" are accepted as written on the command line.
+func ParseTemplate(format string) (*template.Template, error) {
+	format = strings.NewReplacer(`\t`, "\t", `\n`, "\n").Replace(format)
+	tmpl, err := template.New("format").Funcs(TemplateFuncs).Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid format template. %s", err)
+	}
+	return tmpl, nil
+}
+
+// ExecuteTemplate renders v with the --format template.
+func ExecuteTemplate(format string, v interface{}) (string, error) {
+	tmpl, err := ParseTemplate(format)
+	if err != nil {
+		return "", err
+	}
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, v); err != nil {
+		return "", fmt.Errorf("Failed execute format template. %s", err)
+	}
+	return buf.String(), nil
+}
+
+// ExecuteTemplateEach renders every element of the slice v with the --format
+// template, one element per line.
+func ExecuteTemplateEach(format string, v interface{}) (string, error) {
+	tmpl, err := ParseTemplate(format)
+	if err != nil {
+		return "", err
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return ExecuteTemplate(format, v)
+	}
+
+	lines := make([]string, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		buf := &bytes.Buffer{}
+		if err := tmpl.Execute(buf, rv.Index(i).Interface()); err != nil {
+			return "", fmt.Errorf("Failed execute format template. %s", err)
+		}
+		lines[i] = buf.String()
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func colorize(name string, v interface{}) (string, error) {
+	attr, ok := colorAttributes[name]
+	if !ok {
+		return "", fmt.Errorf("unknown color: %s", name)
+	}
+	return color.New(attr).Sprint(v), nil
+}
+
+func truncate(length int, s string) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+	if length <= 3 {
+		return string(runes[:length])
+	}
+	return string(runes[:length-3]) + "..."
+}
+
+func timeago(v interface{}) string {
+	var t time.Time
+	switch tv := v.(type) {
+	case time.Time:
+		t = tv
+	case *time.Time:
+		if tv == nil {
+			return ""
+		}
+		t = *tv
+	default:
+		return fmt.Sprint(v)
+	}
+
+	d := now().Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return ago(int(d/time.Minute), "minute")
+	case d < 24*time.Hour:
+		return ago(int(d/time.Hour), "hour")
+	case d < 30*24*time.Hour:
+		return ago(int(d/(24*time.Hour)), "day")
+	case d < 365*24*time.Hour:
+		return ago(int(d/(30*24*time.Hour)), "month")
+	}
+	return ago(int(d/(365*24*time.Hour)), "year")
+}
+
+func ago(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s ago", unit)
+	}
+	return fmt.Sprintf("%d %ss ago", n, unit)
+}
@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_ExecuteTemplateEach(t *testing.T) {
+	type item struct {
+		IID    int
+		Labels []string
+		Author struct {
+			Username string
+		}
+	}
+	items := []*item{
+		&item{IID: 1, Labels: []string{"bug", "ui"}},
+		&item{IID: 2, Labels: []string{}},
+	}
+	items[0].Author.Username = "alice"
+	items[1].Author.Username = "bob"
+
+	tests := []struct {
+		name    string
+		format  string
+		value   interface{}
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "fields and join",
+			format: `{{.IID}} {{.Author.Username}} {{join .Labels ","}}`,
+			value:  items,
+			want:   "1 alice bug,ui\n2 bob ",
+		},
+		{
+			name:   "escaped tab",
+			format: `{{.IID}}\t{{.Author.Username}}`,
+			value:  items,
+			want:   "1\talice\n2\tbob",
+		},
+		{
+			name:   "single value",
+			format: `{{.IID}}`,
+			value:  items[0],
+			want:   "1",
+		},
+		{
+			name:    "invalid template",
+			format:  `{{.IID`,
+			value:   items,
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			format:  `{{.Unknown}}`,
+			value:   items,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExecuteTemplateEach(tt.format, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExecuteTemplateEach() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ExecuteTemplateEach() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_truncate(t *testing.T) {
+	tests := []struct {
+		length int
+		s      string
+		want   string
+	}{
+		{length: 10, s: "short", want: "short"},
+		{length: 8, s: "long long title", want: "long ..."},
+		{length: 2, s: "long", want: "lo"},
+		{length: 4, s: "日本語の題名", want: "日..."},
+	}
+	for _, tt := range tests {
+		if got := truncate(tt.length, tt.s); got != tt.want {
+			t.Errorf("truncate(%d, %q) = %q, want %q", tt.length, tt.s, got, tt.want)
+		}
+	}
+}
+
+func Test_timeago(t *testing.T) {
+	base := time.Date(2018, 3, 14, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return base }
+	defer func() { now = time.Now }()
+
+	oneHourAgo := base.Add(-time.Hour)
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{value: base.Add(-10 * time.Second), want: "just now"},
+		{value: base.Add(-5 * time.Minute), want: "5 minutes ago"},
+		{value: &oneHourAgo, want: "1 hour ago"},
+		{value: base.Add(-3 * 24 * time.Hour), want: "3 days ago"},
+		{value: base.Add(-65 * 24 * time.Hour), want: "2 months ago"},
+		{value: base.Add(-800 * 24 * time.Hour), want: "2 years ago"},
+		{value: (*time.Time)(nil), want: ""},
+	}
+	for _, tt := range tests {
+		if got := timeago(tt.value); got != tt.want {
+			t.Errorf("timeago(%v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
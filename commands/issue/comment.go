@@ -0,0 +1,92 @@
+package issue
+
+import (
+	"strconv"
+
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+type commentMethod struct {
+	client   api.Note
+	opt      *internal.CommentOption
+	message  string
+	project  string
+	id       int
+	editFunc func(program, file string) error
+}
+
+func (m *commentMethod) Process() (string, error) {
+	quote := ""
+	if m.opt.Quote != 0 {
+		note, err := m.client.GetIssueNote(m.project, m.id, m.opt.Quote)
+		if err != nil {
+			return "", err
+		}
+		quote = internal.QuoteNote(note)
+	}
+
+	body, err := internal.NewComment(quote, m.message, m.editFunc)
+	if err != nil {
+		return "", err
+	}
+
+	note, err := m.client.CreateIssueNote(
+		m.project,
+		m.id,
+		&gitlab.CreateIssueNoteOptions{Body: gitlab.String(body)},
+	)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(note.ID), nil
+}
+
+type editNoteMethod struct {
+	client   api.Note
+	opt      *internal.CommentOption
+	message  string
+	project  string
+	id       int
+	editFunc func(program, file string) error
+}
+
+func (m *editNoteMethod) Process() (string, error) {
+	body := m.message
+	if body == "" {
+		note, err := m.client.GetIssueNote(m.project, m.id, m.opt.EditNote)
+		if err != nil {
+			return "", err
+		}
+		body, err = internal.EditComment(note.Body, m.editFunc)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	_, err := m.client.UpdateIssueNote(
+		m.project,
+		m.id,
+		m.opt.EditNote,
+		&gitlab.UpdateIssueNoteOptions{Body: gitlab.String(body)},
+	)
+	if err != nil {
+		return "", err
+	}
+	return "", nil
+}
+
+type deleteNoteMethod struct {
+	client  api.Note
+	opt     *internal.CommentOption
+	project string
+	id      int
+}
+
+func (m *deleteNoteMethod) Process() (string, error) {
+	if err := m.client.DeleteIssueNote(m.project, m.id, m.opt.DeleteNote); err != nil {
+		return "", err
+	}
+	return "", nil
+}
@@ -0,0 +1,108 @@
+package issue
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func Test_commentMethod_Process(t *testing.T) {
+	tests := []struct {
+		name     string
+		opt      *internal.CommentOption
+		message  string
+		editFunc func(program, file string) error
+		want     string
+		wantBody string
+		wantErr  bool
+	}{
+		{
+			name:     "message",
+			opt:      &internal.CommentOption{Comment: true},
+			message:  "LGTM",
+			want:     "3",
+			wantBody: "LGTM",
+		},
+		{
+			name:     "quote with message",
+			opt:      &internal.CommentOption{Quote: 1},
+			message:  "Agreed",
+			want:     "3",
+			wantBody: "> first line\n>\n> second line\n\nAgreed",
+		},
+		{
+			name: "editor",
+			opt:  &internal.CommentOption{Comment: true},
+			editFunc: func(program, file string) error {
+				return ioutil.WriteFile(file, []byte("Written on editor\n\n<!-- instruction -->\n"), 0644)
+			},
+			want:     "3",
+			wantBody: "Written on editor",
+		},
+		{
+			name: "empty on editor",
+			opt:  &internal.CommentOption{Comment: true},
+			editFunc: func(program, file string) error {
+				return nil
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotBody string
+			m := &commentMethod{
+				client: &api.MockNoteClient{
+					MockGetIssueNote: func(repositoryName string, iid, noteID int) (*gitlab.Note, error) {
+						return &gitlab.Note{ID: noteID, Body: "first line\n\nsecond line"}, nil
+					},
+					MockCreateIssueNote: func(repositoryName string, iid int, opt *gitlab.CreateIssueNoteOptions) (*gitlab.Note, error) {
+						gotBody = *opt.Body
+						return &gitlab.Note{ID: 3}, nil
+					},
+				},
+				opt:      tt.opt,
+				message:  tt.message,
+				project:  "group/repo",
+				id:       12,
+				editFunc: tt.editFunc,
+			}
+			got, err := m.Process()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("commentMethod.Process() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("commentMethod.Process() = %v, want %v", got, tt.want)
+			}
+			if gotBody != tt.wantBody {
+				t.Errorf("commentMethod.Process() body = %q, want %q", gotBody, tt.wantBody)
+			}
+		})
+	}
+}
+
+func Test_editNoteMethod_Process(t *testing.T) {
+	var gotNoteID int
+	var gotBody string
+	m := &editNoteMethod{
+		client: &api.MockNoteClient{
+			MockUpdateIssueNote: func(repositoryName string, iid, noteID int, opt *gitlab.UpdateIssueNoteOptions) (*gitlab.Note, error) {
+				gotNoteID, gotBody = noteID, *opt.Body
+				return &gitlab.Note{ID: noteID}, nil
+			},
+		},
+		opt:     &internal.CommentOption{EditNote: 2},
+		message: "Fixed typo",
+		project: "group/repo",
+		id:      12,
+	}
+	if _, err := m.Process(); err != nil {
+		t.Fatalf("editNoteMethod.Process() error = %v", err)
+	}
+	if gotNoteID != 2 || gotBody != "Fixed typo" {
+		t.Errorf("editNoteMethod.Process() updated note %d with %q", gotNoteID, gotBody)
+	}
+}
@@ -18,6 +18,9 @@ func makeCreateIssueOptions(opt *CreateUpdateOption, title, description string)
 	if opt.MilestoneID != 0 {
 		createIssueOption.MilestoneID = gitlab.Int(opt.MilestoneID)
 	}
+	if labels := opt.getLabels(nil); len(labels) > 0 {
+		createIssueOption.Labels = gitlab.Labels(labels)
+	}
 	return createIssueOption
 }
 
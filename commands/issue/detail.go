@@ -2,6 +2,7 @@ package issue
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/fatih/color"
@@ -16,6 +17,13 @@ type detailMethod struct {
 	id          int
 	project     string
 	opt         *ShowOption
+	output      *internal.OutputOption
+}
+
+// issueDetail is the issue with its comments, rendered by json and yaml output.
+type issueDetail struct {
+	*gitlab.Issue
+	Notes []*gitlab.Note `json:"notes,omitempty"`
 }
 
 func (m *detailMethod) Process() (string, error) {
@@ -23,18 +31,26 @@ func (m *detailMethod) Process() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	res := issueDetailOutput(issue)
 
-	if m.opt.NoComment {
-		return res, nil
+	var notes []*gitlab.Note
+	if !m.opt.NoComment {
+		notes, err = m.noteClient.GetIssueNotes(m.project, m.id, makeListIssueNotesOptions())
+		if err != nil {
+			return "", err
+		}
 	}
 
-	notes, err := m.noteClient.GetIssueNotes(m.project, m.id, makeListIssueNotesOptions())
-	if err != nil {
-		return "", err
-	}
+	return m.output.FormatDetail(
+		&issueDetail{Issue: issue, Notes: notes},
+		func() string { return issueDetailWithNotesOutput(issue, notes) },
+		func() [][]string { return issueDetailRow(issue) },
+	)
+}
+
+func issueDetailWithNotesOutput(issue *gitlab.Issue, notes []*gitlab.Note) string {
+	res := issueDetailOutput(issue)
 	if len(notes) == 0 {
-		return res, nil
+		return res
 	}
 
 	noteOutputs := make([]string, len(notes))
@@ -42,15 +58,12 @@ func (m *detailMethod) Process() (string, error) {
 		noteOutputs[i] = noteOutput(note)
 	}
 	noteOutput := strings.Join(noteOutputs, "\n")
-	res = strings.Join([]string{res, noteOutput}, "\n")
-
-	return res, nil
+	return strings.Join([]string{res, noteOutput}, "\n")
 }
 
 func makeListIssueNotesOptions() *gitlab.ListIssueNotesOptions {
 	lopt := gitlab.ListOptions{
-		Page:    1,
-		PerPage: 20,
+		Page: 1,
 	}
 	return &gitlab.ListIssueNotesOptions{
 		ListOptions: lopt,
@@ -94,6 +107,24 @@ Labels: %s
 	return detial
 }
 
+func issueDetailRow(issue *gitlab.Issue) [][]string {
+	milestone := ""
+	if issue.Milestone != nil {
+		milestone = issue.Milestone.Title
+	}
+	return [][]string{
+		{
+			strconv.Itoa(issue.IID),
+			issue.State,
+			issue.Author.Username,
+			issue.Assignee.Username,
+			milestone,
+			strings.Join(issue.Labels, ","),
+			issue.Title,
+		},
+	}
+}
+
 func noteOutput(note *gitlab.Note) string {
 	base := `
 %s (created by @%s, %s)
@@ -24,6 +24,9 @@ func (c *IssueMethodFactory) CreateMethod(opt Option, pInfo *gitutil.GitLabProje
 	}
 
 	if iid > 0 {
+		if opt.CommentOption.HasComment() {
+			return newCommentMethod(opt, pInfo, iid, factory.GetNoteClient())
+		}
 		if opt.CreateUpdateOption.hasEdit() {
 			return &updateOnEditorMethod{
 				client:   factory.GetIssueClient(),
@@ -45,6 +48,7 @@ func (c *IssueMethodFactory) CreateMethod(opt Option, pInfo *gitutil.GitLabProje
 			issueClient: factory.GetIssueClient(),
 			noteClient:  factory.GetNoteClient(),
 			opt:         opt.ShowOption,
+			output:      opt.OutputOption,
 			project:     pInfo.Project,
 			id:          iid,
 		}
@@ -71,16 +75,48 @@ func (c *IssueMethodFactory) CreateMethod(opt Option, pInfo *gitutil.GitLabProje
 		return &listAllMethod{
 			client: factory.GetIssueClient(),
 			opt:    opt.ListOption,
+			output: opt.OutputOption,
 		}
 	}
 
 	return &listMethod{
 		client:  factory.GetIssueClient(),
 		opt:     opt.ListOption,
+		output:  opt.OutputOption,
 		project: pInfo.Project,
 	}
 }
 
+func newCommentMethod(opt Option, pInfo *gitutil.GitLabProjectInfo, iid int, client api.Note) internal.Method {
+	commentOption := opt.CommentOption
+	if commentOption.DeleteNote != 0 {
+		return &deleteNoteMethod{
+			client:  client,
+			opt:     commentOption,
+			project: pInfo.Project,
+			id:      iid,
+		}
+	}
+	if commentOption.EditNote != 0 {
+		return &editNoteMethod{
+			client:   client,
+			opt:      commentOption,
+			message:  opt.CreateUpdateOption.Message,
+			project:  pInfo.Project,
+			id:       iid,
+			editFunc: nil,
+		}
+	}
+	return &commentMethod{
+		client:   client,
+		opt:      commentOption,
+		message:  opt.CreateUpdateOption.Message,
+		project:  pInfo.Project,
+		id:       iid,
+		editFunc: nil,
+	}
+}
+
 type MockMethodFactory struct{}
 
 func (c *MockMethodFactory) CreateMethod(opt Option, pInfo *gitutil.GitLabProjectInfo, iid int, factory api.APIClientFactory) internal.Method {
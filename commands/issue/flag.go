@@ -3,6 +3,8 @@ package issue
 import (
 	flags "github.com/jessevdk/go-flags"
 	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
 )
 
 type Option struct {
@@ -10,17 +12,23 @@ type Option struct {
 	CreateUpdateOption   *CreateUpdateOption            `group:"Create, Update Options"`
 	ListOption           *ListOption                    `group:"List Options"`
 	ShowOption           *ShowOption                    `group:"Show Options"`
+	CommentOption        *internal.CommentOption        `group:"Comment Options"`
 	BrowseOption         *internal.BrowseOption         `group:"Browse Options"`
+	OutputOption         *internal.OutputOption         `group:"Output Options"`
+	CacheOption          *internal.CacheOption          `group:"Cache Options"`
 }
 
 type CreateUpdateOption struct {
-	Edit        bool   `short:"e" long:"edit" description:"Edit the issue on editor. Start the editor with the contents in the given title and message options."`
-	Title       string `short:"i" long:"title" value-name:"<title>" description:"The title of an issue"`
-	Message     string `short:"m" long:"message" value-name:"<message>" description:"The message of an issue"`
-	Template    string `short:"p" long:"template" value-name:"<issue template>" description:"Start the editor with file using issue template"`
-	StateEvent  string `long:"state-event" value-name:"<state>" description:"Change the status. \"close\", \"reopen\""`
-	AssigneeID  int    `long:"cu-assignee-id" value-name:"<assignee id>" description:"The ID of the user to assign the issue to."`
-	MilestoneID int    `long:"cu-milestone-id" value-name:"<milestone id>" description:"The global ID of a milestone to assign the issue to. "`
+	Edit         bool     `short:"e" long:"edit" description:"Edit the issue on editor. Start the editor with the contents in the given title and message options."`
+	Title        string   `short:"i" long:"title" value-name:"<title>" description:"The title of an issue"`
+	Message      string   `short:"m" long:"message" value-name:"<message>" description:"The message of an issue"`
+	Template     string   `short:"p" long:"template" value-name:"<issue template>" description:"Start the editor with file using issue template"`
+	StateEvent   string   `long:"state-event" value-name:"<state>" description:"Change the status. \"close\", \"reopen\""`
+	AssigneeID   int      `long:"cu-assignee-id" value-name:"<assignee id>" description:"The ID of the user to assign the issue to."`
+	MilestoneID  int      `long:"cu-milestone-id" value-name:"<milestone id>" description:"The global ID of a milestone to assign the issue to. "`
+	Labels       []string `long:"cu-label" value-name:"<label>" description:"Set the labels of the issue. Repeat or separate by comma to give multiple labels."`
+	AddLabels    []string `long:"add-label" value-name:"<label>" description:"Add the labels to the issue."`
+	RemoveLabels []string `long:"remove-label" value-name:"<label>" description:"Remove the labels from the issue."`
 }
 
 func (o *CreateUpdateOption) hasEdit() bool {
@@ -33,7 +41,9 @@ func (o *CreateUpdateOption) hasEdit() bool {
 func (o *CreateUpdateOption) hasCreate() bool {
 	if o.Title != "" ||
 		o.AssigneeID != 0 ||
-		o.MilestoneID != 0 {
+		o.MilestoneID != 0 ||
+		len(o.Labels) > 0 ||
+		len(o.AddLabels) > 0 {
 		return true
 	}
 	return false
@@ -44,27 +54,48 @@ func (o *CreateUpdateOption) hasUpdate() bool {
 		o.Message != "" ||
 		o.StateEvent != "" ||
 		o.AssigneeID != 0 ||
-		o.MilestoneID != 0 {
+		o.MilestoneID != 0 ||
+		o.hasLabel() {
 		return true
 	}
 	return false
 }
 
+func (o *CreateUpdateOption) hasLabel() bool {
+	if len(o.Labels) > 0 ||
+		len(o.AddLabels) > 0 ||
+		len(o.RemoveLabels) > 0 {
+		return true
+	}
+	return false
+}
+
+// getLabels returns the labels of the issue changed by the label options.
+func (o *CreateUpdateOption) getLabels(labels []string) []string {
+	if len(o.Labels) > 0 {
+		labels = internal.SplitLabels(o.Labels)
+	}
+	return internal.ChangeLabels(labels, internal.SplitLabels(o.AddLabels), internal.SplitLabels(o.RemoveLabels))
+}
+
 type ListOption struct {
-	Num        int    `short:"n" long:"num" value-name:"<num>" default:"20" default-mask:"20" description:"Limit the number of issue to output."`
-	State      string `long:"state" value-name:"<state>" default:"all" default-mask:"all" description:"Print only issue of the state just those that are \"opened\", \"closed\" or \"all\""`
-	Scope      string `long:"scope" value-name:"<scope>" default:"all" default-mask:"all" description:"Print only given scope. \"created-by-me\", \"assigned-to-me\" or \"all\"."`
-	OrderBy    string `long:"orderby" value-name:"<orderby>" default:"updated_at" default-mask:"updated_at" description:"Print issue ordered by \"created_at\" or \"updated_at\" fields."`
-	Sort       string `long:"sort"  value-name:"<sort>" default:"desc" default-mask:"desc" description:"Print issue ordered in \"asc\" or \"desc\" order."`
-	Search     string `short:"s" long:"search"  value-name:"<search word>" description:"Search issues against their title and description."`
-	Milestone  string `long:"milestone"  value-name:"<milestone>" description:"Print issues for a specific milestone. "`
-	AuthorID   int    `long:"author-id"  value-name:"<auther id>" description:"Print issues created by the given user id"`
-	AssigneeID int    `long:"assignee-id"  value-name:"<assignee id>" description:"Print issues assigned to the given user id."`
-	Opened     bool   `short:"O" long:"opened" description:"Shorthand of the state option for \"--state=opened\"."`
-	Closed     bool   `short:"C" long:"closed" description:"Shorthand of the state option for \"--state=closed\"."`
-	CreatedMe  bool   `short:"r" long:"created-me" description:"Shorthand of the scope option for \"--scope=created-by-me\"."`
-	AssignedMe bool   `short:"a" long:"assigned-me" description:"Shorthand of the scope option for \"--scope=assigned-by-me\"."`
-	AllProject bool   `short:"A" long:"all-project" description:"Print the issue of all projects"`
+	Num        int      `short:"n" long:"num" value-name:"<num>" default:"20" default-mask:"20" description:"Limit the number of issue to output."`
+	All        bool     `long:"all" description:"Print all issues, ignore the num option."`
+	State      string   `long:"state" value-name:"<state>" default:"all" default-mask:"all" description:"Print only issue of the state just those that are \"opened\", \"closed\" or \"all\""`
+	Scope      string   `long:"scope" value-name:"<scope>" default:"all" default-mask:"all" description:"Print only given scope. \"created-by-me\", \"assigned-to-me\" or \"all\"."`
+	OrderBy    string   `long:"orderby" value-name:"<orderby>" default:"updated_at" default-mask:"updated_at" description:"Print issue ordered by \"created_at\" or \"updated_at\" fields."`
+	Sort       string   `long:"sort"  value-name:"<sort>" default:"desc" default-mask:"desc" description:"Print issue ordered in \"asc\" or \"desc\" order."`
+	Search     string   `short:"s" long:"search"  value-name:"<search word>" description:"Search issues against their title and description."`
+	Milestone  string   `long:"milestone"  value-name:"<milestone>" description:"Print issues for a specific milestone. "`
+	AuthorID   int      `long:"author-id"  value-name:"<auther id>" description:"Print issues created by the given user id"`
+	AssigneeID int      `long:"assignee-id"  value-name:"<assignee id>" description:"Print issues assigned to the given user id."`
+	Labels     []string `long:"label" value-name:"<label>" description:"Print issues having all of the labels."`
+	NotLabels  []string `long:"not-label" value-name:"<label>" description:"Print issues having none of the labels."`
+	Opened     bool     `short:"O" long:"opened" description:"Shorthand of the state option for \"--state=opened\"."`
+	Closed     bool     `short:"C" long:"closed" description:"Shorthand of the state option for \"--state=closed\"."`
+	CreatedMe  bool     `short:"r" long:"created-me" description:"Shorthand of the scope option for \"--scope=created-by-me\"."`
+	AssignedMe bool     `short:"a" long:"assigned-me" description:"Shorthand of the scope option for \"--scope=assigned-by-me\"."`
+	AllProject bool     `short:"A" long:"all-project" description:"Print the issue of all projects"`
 }
 
 func (l *ListOption) getState() string {
@@ -87,6 +118,16 @@ func (l *ListOption) getScope() string {
 	return l.Scope
 }
 
+// listOptionFuncs returns the filters which the list options of go-gitlab
+// can not give.
+func (l *ListOption) listOptionFuncs() []gitlab.OptionFunc {
+	var options []gitlab.OptionFunc
+	if labels := internal.SplitLabels(l.NotLabels); len(labels) > 0 {
+		options = append(options, api.WithNotLabels(labels))
+	}
+	return options
+}
+
 type ShowOption struct {
 	NoComment bool `long:"no-comment" description:"Not print a list of comments for a spcific issue."`
 }
@@ -96,7 +137,10 @@ func newOptionParser(opt *Option) *flags.Parser {
 	opt.CreateUpdateOption = &CreateUpdateOption{}
 	opt.ListOption = &ListOption{}
 	opt.ShowOption = &ShowOption{}
+	opt.CommentOption = &internal.CommentOption{}
 	opt.BrowseOption = &internal.BrowseOption{}
+	opt.OutputOption = &internal.OutputOption{}
+	opt.CacheOption = &internal.CacheOption{}
 	parser := flags.NewParser(opt, flags.HelpFlag|flags.PassDoubleDash)
 	parser.Usage = `issue - Create and Edit, List, Browse a issue
 
@@ -104,19 +148,27 @@ Synopsis:
   # List issue
   lab issue [-n <num>] [--state=<state> | -o | -c] [--scope=<scope> | -r | -a] [-s <search word>]
             [--milestone=<milestone>] [--author-id=<author id>] [--assignee-id=<assignee id>]
-            [--orderby=<orderby>] [--sort=<sort>] [-A]
+            [--label=<label>...] [--not-label=<label>...]
+            [--orderby=<orderby>] [--sort=<sort>] [-A] [--output=<format>]
 
   # Create issue
   lab issue -e | -i <title> [-m <message>]
             [--cu-assignee-id=<assignee id>] [--cu-milestone-id=<milestone id>]
+            [--cu-label=<label>...]
 
   # Update issue
   lab issue <issue id> [-e] [-i <title>] [-m <message>]
                        [--state-event=<state>]
                        [--cu-assignee-id=<assignee id>] [--cu-milestone-id=<milestone id>]
+                       [--cu-label=<label>...] [--add-label=<label>...] [--remove-label=<label>...]
 
   # Show issue
-  lab issue <issue id> [--no-comment]
+  lab issue <issue id> [--no-comment] [--output=<format>]
+
+  # Add, edit or delete comment of issue
+  lab issue <issue id> --comment [-e | -m <message>] [--quote=<note id>]
+  lab issue <issue id> --edit-note=<note id> [-m <message>]
+  lab issue <issue id> --delete-note=<note id>
 
   # Browse issue
   lab issue -b [<issue id>]`
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/lighttiger2505/lab/commands/internal"
 	"github.com/lighttiger2505/lab/git"
 	"github.com/lighttiger2505/lab/internal/api"
+	"github.com/lighttiger2505/lab/internal/cache"
 	"github.com/lighttiger2505/lab/internal/gitutil"
 	"github.com/lighttiger2505/lab/internal/ui"
 )
@@ -21,6 +23,8 @@ type IssueCommand struct {
 	UI              ui.UI
 	RemoteCollecter gitutil.Collecter
 	MethodFactory   MethodFactory
+	// Cache keeps what is read from GitLab for --offline, nil to keep nothing
+	Cache *cache.Store
 }
 
 func (c *IssueCommand) Synopsis() string {
@@ -59,22 +63,26 @@ func (c *IssueCommand) Run(args []string) int {
 		return ExitCodeError
 	}
 
-	clientFacotry, err := api.NewGitlabClientFactory(pInfo.ApiUrl(), pInfo.Token)
+	clientFacotry, err := api.NewGitlabClientFactory(pInfo.ApiUrl(), pInfo.Token, pInfo.TLS)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return ExitCodeError
+	}
+	clientFacotry, err = internal.CacheClientFactory(clientFacotry, c.Cache, pInfo.Domain, opt.CacheOption)
 	if err != nil {
 		c.UI.Error(err.Error())
 		return ExitCodeError
 	}
 
 	method := c.MethodFactory.CreateMethod(opt, pInfo, iid, clientFacotry)
+	opt.OutputOption.Stream(c.UI)
 	res, err := method.Process()
 	if err != nil {
 		c.UI.Error(err.Error())
 		return ExitCodeError
 	}
 
-	if res != "" {
-		c.UI.Message(res)
-	}
+	opt.OutputOption.Write(c.UI, res)
 
 	return ExitCodeOK
 }
@@ -1,55 +1,65 @@
 package issue
 
 import (
-	"strings"
-
 	"github.com/fatih/color"
 	"github.com/lighttiger2505/lab/commands/internal"
 	"github.com/lighttiger2505/lab/internal/api"
-	"github.com/ryanuber/columnize"
 	gitlab "github.com/xanzy/go-gitlab"
 )
 
 type listMethod struct {
 	client  api.Issue
 	opt     *ListOption
+	output  *internal.OutputOption
 	project string
 }
 
 func (m *listMethod) Process() (string, error) {
-	issues, err := m.client.GetProjectIssues(
+	w := m.output.NewListWriter()
+	err := m.client.GetProjectIssues(
 		makeProjectIssueOption(m.opt),
 		m.project,
+		internal.Limit(m.opt.Num, m.opt.All),
+		func(issues []*gitlab.Issue) error {
+			return w.WritePage(issues, func() [][]string {
+				return listOutput(issues)
+			})
+		},
+		m.opt.listOptionFuncs()...,
 	)
 	if err != nil {
 		return "", err
 	}
-
-	output := listOutput(issues)
-	result := columnize.SimpleFormat(output)
-	return result, nil
+	return w.Flush()
 }
 
 type listAllMethod struct {
 	client api.Issue
 	opt    *ListOption
+	output *internal.OutputOption
 }
 
 func (m *listAllMethod) Process() (string, error) {
-	issues, err := m.client.GetAllProjectIssues(makeAllProjectIssueOption(m.opt))
+	w := m.output.NewListWriter()
+	err := m.client.GetAllProjectIssues(
+		makeAllProjectIssueOption(m.opt),
+		internal.Limit(m.opt.Num, m.opt.All),
+		func(issues []*gitlab.Issue) error {
+			return w.WritePage(issues, func() [][]string {
+				return listAllOutput(issues)
+			})
+		},
+		m.opt.listOptionFuncs()...,
+	)
 	if err != nil {
 		return "", err
 	}
-
-	output := listAllOutput(issues)
-	result := columnize.SimpleFormat(output)
-	return result, nil
+	return w.Flush()
 }
 
 func makeProjectIssueOption(issueListOption *ListOption) *gitlab.ListProjectIssuesOptions {
 	listOption := &gitlab.ListOptions{
-		Page:    1,
-		PerPage: issueListOption.Num,
+		Page: 1,
 	}
 	listProjectIssuesOptions := &gitlab.ListProjectIssuesOptions{
 		State:       gitlab.String(issueListOption.getState()),
@@ -69,13 +79,15 @@ func makeProjectIssueOption(issueListOption *ListOption) *gitlab.ListProjectIssu
 	if issueListOption.AssigneeID != 0 {
 		listProjectIssuesOptions.AssigneeID = gitlab.Int(issueListOption.AssigneeID)
 	}
+	if labels := internal.SplitLabels(issueListOption.Labels); len(labels) > 0 {
+		listProjectIssuesOptions.Labels = gitlab.Labels(labels)
+	}
 	return listProjectIssuesOptions
 }
 
 func makeAllProjectIssueOption(issueListOption *ListOption) *gitlab.ListIssuesOptions {
 	listOption := &gitlab.ListOptions{
-		Page:    1,
-		PerPage: issueListOption.Num,
+		Page: 1,
 	}
 	listIssuesOptions := &gitlab.ListIssuesOptions{
 		State:       gitlab.String(issueListOption.getState()),
@@ -95,32 +107,35 @@ func makeAllProjectIssueOption(issueListOption *ListOption) *gitlab.ListIssuesOp
 	if issueListOption.AssigneeID != 0 {
 		listIssuesOptions.AssigneeID = gitlab.Int(issueListOption.AssigneeID)
 	}
+	if labels := internal.SplitLabels(issueListOption.Labels); len(labels) > 0 {
+		listIssuesOptions.Labels = gitlab.Labels(labels)
+	}
 	return listIssuesOptions
 }
 
-func listOutput(issues []*gitlab.Issue) []string {
+func listOutput(issues []*gitlab.Issue) [][]string {
 	yellow := color.New(color.FgYellow).SprintFunc()
-	var datas []string
+	var datas [][]string
 	for _, issue := range issues {
-		data := strings.Join([]string{
+		data := []string{
 			yellow(issue.IID),
 			issue.Title,
-		}, "|")
+		}
 		datas = append(datas, data)
 	}
 	return datas
 }
 
-func listAllOutput(issues []*gitlab.Issue) []string {
+func listAllOutput(issues []*gitlab.Issue) [][]string {
 	cyan := color.New(color.FgCyan).SprintFunc()
 	yellow := color.New(color.FgYellow).SprintFunc()
-	var datas []string
+	var datas [][]string
 	for _, issue := range issues {
-		data := strings.Join([]string{
+		data := []string{
 			cyan(internal.ParceRepositoryFullName(issue.WebURL)),
 			yellow(issue.IID),
 			issue.Title,
-		}, "|")
+		}
 		datas = append(datas, data)
 	}
 	return datas
@@ -6,7 +6,9 @@ import (
 	gitlab "github.com/xanzy/go-gitlab"
 )
 
-func makeUpdateIssueOption(opt *CreateUpdateOption, title, description string) *gitlab.UpdateIssueOptions {
+// makeUpdateIssueOption returns the options updating the issue. labels are
+// the current labels of the issue, which the label options change.
+func makeUpdateIssueOption(opt *CreateUpdateOption, labels []string, title, description string) *gitlab.UpdateIssueOptions {
 	updateIssueOption := &gitlab.UpdateIssueOptions{
 		Title:       gitlab.String(title),
 		Description: gitlab.String(description),
@@ -20,6 +22,9 @@ func makeUpdateIssueOption(opt *CreateUpdateOption, title, description string) *
 	if opt.MilestoneID != 0 {
 		updateIssueOption.MilestoneID = gitlab.Int(opt.MilestoneID)
 	}
+	if opt.hasLabel() {
+		updateIssueOption.Labels = internal.MakeLabels(opt.getLabels(labels))
+	}
 	return updateIssueOption
 }
 
@@ -42,7 +47,7 @@ func (m *updateMethod) Process() (string, error) {
 
 	// Do update issue
 	_, err = m.client.UpdateIssue(
-		makeUpdateIssueOption(m.opt, updatedTitle, updatedMessage),
+		makeUpdateIssueOption(m.opt, issue.Labels, updatedTitle, updatedMessage),
 		m.id,
 		m.project,
 	)
@@ -82,7 +87,7 @@ func (m *updateOnEditorMethod) Process() (string, error) {
 
 	// Do update issue
 	_, err = m.client.UpdateIssue(
-		makeUpdateIssueOption(m.opt, title, message),
+		makeUpdateIssueOption(m.opt, issue.Labels, title, message),
 		m.id,
 		m.project,
 	)
@@ -25,6 +25,7 @@ func Test_updateMethod_Process(t *testing.T) {
 			ID: 24,
 		},
 		Description: "desc",
+		Labels:      []string{"bug", "doing"},
 	}
 
 	tests := []struct {
@@ -192,6 +193,65 @@ func Test_updateMethod_Process(t *testing.T) {
 			want:    "",
 			wantErr: false,
 		},
+		{
+			name: "add and remove labels",
+			method: &updateMethod{
+				client: &api.MockLabIssueClient{
+					MockGetIssue: func(pid int, repositoryName string) (*gitlab.Issue, error) {
+						return issue, nil
+					},
+					MockUpdateIssue: func(opt *gitlab.UpdateIssueOptions, pid int, repositoryName string) (*gitlab.Issue, error) {
+						got := opt
+						want := &gitlab.UpdateIssueOptions{
+							Title:       gitlab.String("title"),
+							Description: gitlab.String("desc"),
+							Labels:      gitlab.Labels{"bug", "done"},
+						}
+						if diff := cmp.Diff(got, want); diff != "" {
+							t.Errorf("invalide arg (-got +want)\n%s", diff)
+						}
+						return issue, nil
+					},
+				},
+				opt: &CreateUpdateOption{
+					AddLabels:    []string{"done"},
+					RemoveLabels: []string{"doing"},
+				},
+				project: "group/project",
+				id:      12,
+			},
+			want:    "",
+			wantErr: false,
+		},
+		{
+			name: "remove all labels",
+			method: &updateMethod{
+				client: &api.MockLabIssueClient{
+					MockGetIssue: func(pid int, repositoryName string) (*gitlab.Issue, error) {
+						return issue, nil
+					},
+					MockUpdateIssue: func(opt *gitlab.UpdateIssueOptions, pid int, repositoryName string) (*gitlab.Issue, error) {
+						got := opt
+						want := &gitlab.UpdateIssueOptions{
+							Title:       gitlab.String("title"),
+							Description: gitlab.String("desc"),
+							Labels:      gitlab.Labels{""},
+						}
+						if diff := cmp.Diff(got, want); diff != "" {
+							t.Errorf("invalide arg (-got +want)\n%s", diff)
+						}
+						return issue, nil
+					},
+				},
+				opt: &CreateUpdateOption{
+					RemoveLabels: []string{"bug,doing"},
+				},
+				project: "group/project",
+				id:      12,
+			},
+			want:    "",
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
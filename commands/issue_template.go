@@ -2,23 +2,23 @@ package commands
 
 import (
 	"bytes"
-	"strings"
 
 	flags "github.com/jessevdk/go-flags"
 	"github.com/lighttiger2505/lab/commands/internal"
 	"github.com/lighttiger2505/lab/internal/api"
 	"github.com/lighttiger2505/lab/internal/gitutil"
 	"github.com/lighttiger2505/lab/internal/ui"
-	"github.com/ryanuber/columnize"
 	gitlab "github.com/xanzy/go-gitlab"
 )
 
 type IssueTemplateCommnadOption struct {
 	ProjectProfileOption *internal.ProjectProfileOption `group:"Project, Profile Options"`
+	OutputOption         *internal.OutputOption         `group:"Output Options"`
 }
 
 func newIssueTemplateCommandParser(opt *IssueTemplateCommnadOption) *flags.Parser {
 	opt.ProjectProfileOption = &internal.ProjectProfileOption{}
+	opt.OutputOption = &internal.OutputOption{}
 	parser := flags.NewParser(opt, flags.HelpFlag|flags.PassDoubleDash)
 	parser.Usage = "issue-template [options]"
 	return parser
@@ -60,7 +60,7 @@ func (c *IssueTemplateCommand) Run(args []string) int {
 		return ExitCodeError
 	}
 
-	if err := c.ClientFactory.Init(pInfo.ApiUrl(), pInfo.Token); err != nil {
+	if err := c.ClientFactory.Init(pInfo.ApiUrl(), pInfo.Token, pInfo.TLS); err != nil {
 		c.UI.Error(err.Error())
 		return ExitCodeError
 	}
@@ -87,8 +87,14 @@ func (c *IssueTemplateCommand) Run(args []string) int {
 			c.UI.Error(err.Error())
 			return ExitCodeError
 		}
-		result := columnize.SimpleFormat(issueTemplateOutput(treeNode))
-		c.UI.Message(result)
+		result, err := opt.OutputOption.FormatList(treeNode, func() [][]string {
+			return issueTemplateOutput(treeNode)
+		})
+		if err != nil {
+			c.UI.Error(err.Error())
+			return ExitCodeError
+		}
+		opt.OutputOption.Write(c.UI, result)
 	}
 
 	return ExitCodeOK
@@ -109,12 +115,12 @@ func makeShowIssueTemplateOption() *gitlab.GetRawFileOptions {
 	return opt
 }
 
-func issueTemplateOutput(treeNode []*gitlab.TreeNode) []string {
-	var outputs []string
+func issueTemplateOutput(treeNode []*gitlab.TreeNode) [][]string {
+	var outputs [][]string
 	for _, node := range treeNode {
-		output := strings.Join([]string{
+		output := []string{
 			node.Name,
-		}, "|")
+		}
 		outputs = append(outputs, output)
 	}
 	return outputs
@@ -5,39 +5,69 @@ import (
 	"fmt"
 	"io/ioutil"
 	"strconv"
-	"strings"
+	"time"
 
 	flags "github.com/jessevdk/go-flags"
 	"github.com/lighttiger2505/lab/commands/internal"
 	"github.com/lighttiger2505/lab/internal/api"
 	"github.com/lighttiger2505/lab/internal/gitutil"
 	"github.com/lighttiger2505/lab/internal/ui"
-	"github.com/ryanuber/columnize"
 	gitlab "github.com/xanzy/go-gitlab"
 )
 
 type JobCommandOption struct {
 	ProjectProfileOption *internal.ProjectProfileOption `group:"Project, Profile Options"`
 	ListOption           *ListJobOption                 `group:"List Options"`
+	ActionOption         *ActionJobOption               `group:"Action Options"`
+	ArtifactsOption      *ArtifactsJobOption            `group:"Artifacts Options"`
+	OutputOption         *internal.OutputOption         `group:"Output Options"`
 }
 
 func newJobOptionParser(opt *JobCommandOption) *flags.Parser {
 	opt.ProjectProfileOption = &internal.ProjectProfileOption{}
 	opt.ListOption = newListJobOption()
+	opt.ActionOption = &ActionJobOption{}
+	opt.ArtifactsOption = &ArtifactsJobOption{}
+	opt.OutputOption = &internal.OutputOption{}
 	parser := flags.NewParser(opt, flags.HelpFlag|flags.PassDoubleDash)
 	parser.Usage = "job [options]"
 	parser.Usage = `issue - list a job
 
 Synopsis:
   # List job
-  lab issue [-n <num>] [--search=<search word>] [-A]`
+  lab issue [-n <num>] [--search=<search word>] [-A]
+
+  # Print trace of job
+  lab job <job id> --log [--follow]
+
+  # Play, retry, cancel or erase job
+  lab job <job id> --play | --retry | --cancel | --erase
+
+  # Download artifacts of job
+  lab job <job id> --artifacts [-o <dir>]
+  lab job <job id> --artifact-path=<path> [-o <dir>]`
 	return parser
 }
 
 type ListJobOption struct {
-	Num int  `short:"n" long:"num" value-name:"<num>" default:"20" default-mask:"20" description:"Limit the number of search to output."`
-	Log bool `short:"t" long:"log" description:"Get a trace of a specific job of a project."`
-	// Scope string `long:"scope" value-name:"<scope>" default:"all" default-mask:"all" description:"Print only given scope. created, pending, running, failed, success, canceled, skipped, manual"`
+	Num    int    `short:"n" long:"num" value-name:"<num>" default:"20" default-mask:"20" description:"Limit the number of search to output."`
+	All    bool   `long:"all" description:"Print all jobs, ignore the num option."`
+	Log    bool   `short:"t" long:"log" description:"Get a trace of a specific job of a project."`
+	Follow bool   `short:"f" long:"follow" description:"Print the trace of a running job as it is written, and exit with the status of the job."`
+	Scope  string `long:"scope" value-name:"<scope>" description:"Print only given scope. created, pending, running, failed, success, canceled, skipped, manual"`
+}
+
+type ActionJobOption struct {
+	Play   bool `long:"play" description:"Start the manual job."`
+	Retry  bool `long:"retry" description:"Retry the job, and print the id of the new job."`
+	Cancel bool `long:"cancel" description:"Cancel the job."`
+	Erase  bool `long:"erase" description:"Erase the trace and the artifacts of the job."`
+}
+
+type ArtifactsJobOption struct {
+	Artifacts    bool   `long:"artifacts" description:"Download and extract the artifacts archive of the job."`
+	ArtifactPath string `long:"artifact-path" value-name:"<path>" description:"Download a single file of the artifacts of the job."`
+	Dir          string `short:"o" long:"output-dir" value-name:"<dir>" default:"." default-mask:"." description:"The directory to download the artifacts into."`
 }
 
 func newListJobOption() *ListJobOption {
@@ -81,7 +111,7 @@ func (c *JobCommand) Run(args []string) int {
 		return ExitCodeError
 	}
 
-	if err := c.ClientFactory.Init(pInfo.ApiUrl(), pInfo.Token); err != nil {
+	if err := c.ClientFactory.Init(pInfo.ApiUrl(), pInfo.Token, pInfo.TLS); err != nil {
 		c.UI.Error(err.Error())
 		return ExitCodeError
 	}
@@ -93,6 +123,36 @@ func (c *JobCommand) Run(args []string) int {
 		jid, err := strconv.Atoi(parseArgs[0])
 		if err != nil {
 			c.UI.Error(fmt.Sprintf("Invalid job id. value: %s, error: %s", parseArgs[0], err))
+			return ExitCodeError
+		}
+
+		if method := newJobActionMethod(client, opt.ActionOption, pInfo.Project, jid); method != nil {
+			result, err := method.Process()
+			if err != nil {
+				c.UI.Error(err.Error())
+				return ExitCodeError
+			}
+			c.UI.Message(result)
+			return ExitCodeOK
+		}
+
+		if method := newJobArtifactsMethod(client, c.UI, opt.ArtifactsOption, pInfo.Project, jid); method != nil {
+			result, err := method.Process()
+			if err != nil {
+				c.UI.Error(err.Error())
+				return ExitCodeError
+			}
+			c.UI.Message(result)
+			return ExitCodeOK
+		}
+
+		if listOpt.Follow {
+			job, err := followJobLog(client, pInfo.Project, jid, newTraceWriter(c.UI), time.Sleep)
+			if err != nil {
+				c.UI.Error(err.Error())
+				return ExitCodeError
+			}
+			return jobExitCode(job.Status)
 		}
 
 		if listOpt.Log {
@@ -108,7 +168,9 @@ func (c *JobCommand) Run(args []string) int {
 				return ExitCodeError
 			}
 
-			c.UI.Message(string(b))
+			w := newTraceWriter(c.UI)
+			w.Write(string(b))
+			w.Flush()
 			return ExitCodeOK
 		}
 
@@ -117,19 +179,43 @@ func (c *JobCommand) Run(args []string) int {
 			c.UI.Error(err.Error())
 			return ExitCodeError
 		}
-		c.UI.Message(jobDetailOutput(job))
+		result, err := opt.OutputOption.FormatDetail(
+			job,
+			func() string { return jobDetailOutput(job) },
+			func() [][]string { return projectJobOutput([]gitlab.Job{*job}) },
+		)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return ExitCodeError
+		}
+		opt.OutputOption.Write(c.UI, result)
 	} else {
-		var result string
-		jobs, err := client.GetProjectJobs(
+		if err := validJobScope(listOpt.Scope); err != nil {
+			c.UI.Error(err.Error())
+			return ExitCodeError
+		}
+		opt.OutputOption.Stream(c.UI)
+		w := opt.OutputOption.NewListWriter()
+		err := client.GetProjectJobs(
 			makeProjectJobsOption(listOpt),
 			pInfo.Project,
+			internal.Limit(listOpt.Num, listOpt.All),
+			func(jobs []gitlab.Job) error {
+				return w.WritePage(jobs, func() [][]string {
+					return projectJobOutput(jobs)
+				})
+			},
 		)
 		if err != nil {
 			c.UI.Error(err.Error())
 			return ExitCodeError
 		}
-		result = columnize.SimpleFormat(projectJobOutput(jobs))
-		c.UI.Message(result)
+		result, err := w.Flush()
+		if err != nil {
+			c.UI.Error(err.Error())
+			return ExitCodeError
+		}
+		opt.OutputOption.Write(c.UI, result)
 	}
 
 	return ExitCodeOK
@@ -137,20 +223,21 @@ func (c *JobCommand) Run(args []string) int {
 
 func makeProjectJobsOption(opt *ListJobOption) *gitlab.ListJobsOptions {
 	listOption := &gitlab.ListOptions{
-		Page:    1,
-		PerPage: opt.Num,
+		Page: 1,
 	}
 	listJobOption := &gitlab.ListJobsOptions{
 		ListOptions: *listOption,
-		// Scope:       gitlab.String(opt.Scope),
+	}
+	if opt.Scope != "" {
+		listJobOption.Scope = []gitlab.BuildStateValue{gitlab.BuildStateValue(opt.Scope)}
 	}
 	return listJobOption
 }
 
-func projectJobOutput(jobs []gitlab.Job) []string {
-	var outputs []string
+func projectJobOutput(jobs []gitlab.Job) [][]string {
+	var outputs [][]string
 	for _, job := range jobs {
-		output := strings.Join([]string{
+		output := []string{
 			strconv.Itoa(job.ID),
 			job.Status,
 			job.Ref,
@@ -158,7 +245,7 @@ func projectJobOutput(jobs []gitlab.Job) []string {
 			job.User.Username,
 			job.Stage,
 			job.Name,
-		}, "|")
+		}
 		outputs = append(outputs, output)
 	}
 	return outputs
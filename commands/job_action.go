@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// jobScopes are the statuses of the jobs the list is filtered by.
+var jobScopes = []string{"created", "pending", "running", "failed", "success", "canceled", "skipped", "manual"}
+
+func validJobScope(scope string) error {
+	if scope == "" {
+		return nil
+	}
+	for _, s := range jobScopes {
+		if s == scope {
+			return nil
+		}
+	}
+	return fmt.Errorf("Invalid scope %s, please input one of %s", scope, strings.Join(jobScopes, ", "))
+}
+
+// jobActionMethod runs an action on a job, and prints the id of the job.
+// Retrying a job makes a new job, so the id differs from the given one.
+type jobActionMethod struct {
+	action  func(repositoryName string, jobID int) (*gitlab.Job, error)
+	project string
+	id      int
+}
+
+func (m *jobActionMethod) Process() (string, error) {
+	job, err := m.action(m.project, m.id)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(job.ID), nil
+}
+
+// newJobActionMethod returns the method of the action option, or nil when no
+// action is given.
+func newJobActionMethod(client api.Job, opt *ActionJobOption, project string, id int) internal.Method {
+	var action func(repositoryName string, jobID int) (*gitlab.Job, error)
+	switch {
+	case opt.Play:
+		action = client.PlayJob
+	case opt.Retry:
+		action = client.RetryJob
+	case opt.Cancel:
+		action = client.CancelJob
+	case opt.Erase:
+		action = client.EraseJob
+	default:
+		return nil
+	}
+	return &jobActionMethod{
+		action:  action,
+		project: project,
+		id:      id,
+	}
+}
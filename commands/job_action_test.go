@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func TestJobActionMethod(t *testing.T) {
+	var called string
+	client := &api.MockLabJobClient{
+		MockPlayJob: func(repositoryName string, jobID int) (*gitlab.Job, error) {
+			called = "play"
+			return &gitlab.Job{ID: jobID}, nil
+		},
+		MockRetryJob: func(repositoryName string, jobID int) (*gitlab.Job, error) {
+			called = "retry"
+			return &gitlab.Job{ID: jobID + 1}, nil
+		},
+		MockCancelJob: func(repositoryName string, jobID int) (*gitlab.Job, error) {
+			called = "cancel"
+			return &gitlab.Job{ID: jobID}, nil
+		},
+		MockEraseJob: func(repositoryName string, jobID int) (*gitlab.Job, error) {
+			called = "erase"
+			return &gitlab.Job{ID: jobID}, nil
+		},
+	}
+
+	tests := []struct {
+		name   string
+		opt    *ActionJobOption
+		called string
+		want   string
+	}{
+		{name: "play", opt: &ActionJobOption{Play: true}, called: "play", want: "12"},
+		{name: "retry", opt: &ActionJobOption{Retry: true}, called: "retry", want: "13"},
+		{name: "cancel", opt: &ActionJobOption{Cancel: true}, called: "cancel", want: "12"},
+		{name: "erase", opt: &ActionJobOption{Erase: true}, called: "erase", want: "12"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = ""
+			method := newJobActionMethod(client, tt.opt, "group/project", 12)
+			got, err := method.Process()
+			if err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Process() = %q, want %q", got, tt.want)
+			}
+			if called != tt.called {
+				t.Errorf("called %q, want %q", called, tt.called)
+			}
+		})
+	}
+
+	if method := newJobActionMethod(client, &ActionJobOption{}, "group/project", 12); method != nil {
+		t.Errorf("newJobActionMethod() = %v, want nil", method)
+	}
+}
+
+func TestValidJobScope(t *testing.T) {
+	for _, scope := range append([]string{""}, jobScopes...) {
+		if err := validJobScope(scope); err != nil {
+			t.Errorf("validJobScope(%q) error = %v", scope, err)
+		}
+	}
+	if err := validJobScope("done"); err == nil {
+		t.Errorf("validJobScope(%q) want error", "done")
+	}
+}
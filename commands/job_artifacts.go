@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+	"github.com/lighttiger2505/lab/internal/ui"
+)
+
+// jobArtifactsMethod downloads the artifacts of a job, and prints the paths
+// of the downloaded files.
+type jobArtifactsMethod struct {
+	client  api.Job
+	ui      ui.UI
+	opt     *ArtifactsJobOption
+	project string
+	id      int
+}
+
+func (m *jobArtifactsMethod) Process() (string, error) {
+	if m.opt.ArtifactPath != "" {
+		path, err := internal.DownloadArtifactFile(
+			m.ui,
+			func(w io.Writer) error {
+				return m.client.DownloadArtifactFile(m.project, m.id, m.opt.ArtifactPath, w)
+			},
+			m.opt.ArtifactPath,
+			m.opt.Dir,
+		)
+		if err != nil {
+			return "", err
+		}
+		return path, nil
+	}
+
+	job, err := m.client.GetJob(m.project, m.id)
+	if err != nil {
+		return "", err
+	}
+	if job.ArtifactsFile.Filename == "" {
+		return "", fmt.Errorf("Not found artifacts of job %d", m.id)
+	}
+
+	paths, err := internal.DownloadArtifacts(
+		m.ui,
+		func(w io.Writer) error {
+			return m.client.DownloadArtifacts(m.project, m.id, w)
+		},
+		int64(job.ArtifactsFile.Size),
+		m.opt.Dir,
+	)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(paths, "\n"), nil
+}
+
+// newJobArtifactsMethod returns the method of the artifacts option, or nil
+// when no artifacts option is given.
+func newJobArtifactsMethod(client api.Job, u ui.UI, opt *ArtifactsJobOption, project string, id int) internal.Method {
+	if !opt.Artifacts && opt.ArtifactPath == "" {
+		return nil
+	}
+	return &jobArtifactsMethod{
+		client:  client,
+		ui:      u,
+		opt:     opt,
+		project: project,
+		id:      id,
+	}
+}
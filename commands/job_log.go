@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/lighttiger2505/lab/internal/api"
+	"github.com/lighttiger2505/lab/internal/ui"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// jobFollowInterval is the interval of reading the trace of a running job.
+const jobFollowInterval = 3 * time.Second
+
+// sectionMarker matches the markers of the collapsible sections in a trace,
+// e.g. "\x1b[0Ksection_start:1560896352:build_script\r\x1b[0K".
+var sectionMarker = regexp.MustCompile(`(\x1b\[0K)?section_(start|end):[0-9]+:([^\r\n\[]+)(\[[^\]]*\])?\r\x1b\[0K`)
+
+// followJobLog prints the trace of the job as it is written until the job
+// finishes, and returns the finished job.
+func followJobLog(client api.Job, project string, id int, w *traceWriter, sleep func(time.Duration)) (*gitlab.Job, error) {
+	offset := 0
+	for {
+		// The job is read before the trace not to miss the last lines
+		job, err := client.GetJob(project, id)
+		if err != nil {
+			return nil, err
+		}
+
+		trace, err := client.GetTraceFileFrom(project, id, offset)
+		if err != nil {
+			return nil, err
+		}
+		offset += len(trace)
+		w.Write(string(trace))
+
+		if !isJobRunning(job.Status) {
+			w.Flush()
+			return job, nil
+		}
+		sleep(jobFollowInterval)
+	}
+}
+
+func isJobRunning(status string) bool {
+	switch status {
+	case "created", "pending", "running":
+		return true
+	}
+	return false
+}
+
+// jobExitCode returns the exit code telling the status of a finished job.
+func jobExitCode(status string) int {
+	switch status {
+	case "failed":
+		return ExitCodeJobFailed
+	case "canceled":
+		return ExitCodeJobCanceled
+	}
+	return ExitCodeOK
+}
+
+// traceWriter prints a trace line by line. The trace is given in pieces,
+// so the last line is kept until it ends.
+type traceWriter struct {
+	ui    ui.UI
+	strip bool
+	buf   string
+}
+
+func newTraceWriter(ui ui.UI) *traceWriter {
+	return &traceWriter{ui: ui, strip: color.NoColor}
+}
+
+func (w *traceWriter) Write(trace string) {
+	lines := strings.Split(w.buf+trace, "\n")
+	w.buf = lines[len(lines)-1]
+	for _, line := range lines[:len(lines)-1] {
+		w.writeLine(line)
+	}
+}
+
+func (w *traceWriter) Flush() {
+	if w.buf != "" {
+		w.writeLine(w.buf)
+	}
+	w.buf = ""
+}
+
+func (w *traceWriter) writeLine(line string) {
+	rendered := renderSections(line, w.strip)
+	if rendered == "" && line != "" {
+		// The line only had section markers
+		return
+	}
+	w.ui.Message(rendered)
+}
+
+// renderSections replaces the section markers in a line of a trace with the
+// header of the section, or removes them when strip is true.
+func renderSections(line string, strip bool) string {
+	if !sectionMarker.MatchString(line) {
+		return line
+	}
+	cyan := color.New(color.FgCyan, color.Bold).SprintFunc()
+	rendered := sectionMarker.ReplaceAllStringFunc(line, func(marker string) string {
+		match := sectionMarker.FindStringSubmatch(marker)
+		if strip || match[2] == "end" {
+			return ""
+		}
+		return cyan("▸ "+match[3]) + " "
+	})
+	return strings.TrimRight(rendered, " ")
+}
@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lighttiger2505/lab/internal/api"
+	"github.com/lighttiger2505/lab/internal/ui"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func TestFollowJobLog(t *testing.T) {
+	statuses := []string{"running", "running", "failed"}
+	traces := []string{
+		"section_start:1560896352:build_script\r\x1b[0KRunning build\nmake",
+		" all\n",
+		"done\nsection_end:1560896353:build_script\r\x1b[0K\nERROR: Job failed",
+	}
+	var offsets []int
+	call := 0
+	client := &api.MockLabJobClient{
+		MockGetJob: func(repositoryName string, jobID int) (*gitlab.Job, error) {
+			return &gitlab.Job{ID: jobID, Status: statuses[call]}, nil
+		},
+		MockGetTraceFileFrom: func(repositoryName string, jobID int, offset int) ([]byte, error) {
+			offsets = append(offsets, offset)
+			trace := traces[call]
+			call++
+			return []byte(trace), nil
+		},
+	}
+
+	mockUI := ui.NewMockUi()
+	w := &traceWriter{ui: mockUI, strip: true}
+	sleeps := 0
+	job, err := followJobLog(client, "group/project", 12, w, func(time.Duration) { sleeps++ })
+	if err != nil {
+		t.Fatalf("followJobLog() error = %v", err)
+	}
+
+	if got, want := jobExitCode(job.Status), ExitCodeJobFailed; got != want {
+		t.Errorf("jobExitCode() = %d, want %d", got, want)
+	}
+	if sleeps != 2 {
+		t.Errorf("followJobLog() slept %d times, want 2", sleeps)
+	}
+	wantOffsets := []int{0, len(traces[0]), len(traces[0]) + len(traces[1])}
+	for i := range wantOffsets {
+		if offsets[i] != wantOffsets[i] {
+			t.Errorf("followJobLog() offsets = %v, want %v", offsets, wantOffsets)
+			break
+		}
+	}
+	got := mockUI.Writer.String()
+	want := "Running build\nmake all\ndone\nERROR: Job failed\n"
+	if got != want {
+		t.Errorf("followJobLog() printed %q, want %q", got, want)
+	}
+}
+
+func TestRenderSections(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		strip bool
+		want  string
+	}{
+		{
+			name:  "strip",
+			line:  "section_start:1560896352:build_script[collapsed=true]\r\x1b[0KRunning build",
+			strip: true,
+			want:  "Running build",
+		},
+		{
+			name:  "leading erase",
+			line:  "\x1b[0Ksection_start:1560896352:build_script\r\x1b[0KRunning build",
+			strip: true,
+			want:  "Running build",
+		},
+		{
+			name: "section end",
+			line: "\x1b[0Ksection_end:1560896353:build_script\r\x1b[0K",
+			want: "",
+		},
+		{
+			name: "plain line",
+			line: "make all ",
+			want: "make all ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderSections(tt.line, tt.strip); got != tt.want {
+				t.Errorf("renderSections() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package label
+
+import (
+	"fmt"
+
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+type createMethod struct {
+	client  api.Label
+	opt     *CreateUpdateOption
+	project string
+	group   string
+	name    string
+}
+
+func (m *createMethod) Process() (string, error) {
+	if m.opt.Color == "" {
+		return "", fmt.Errorf("Please input the color of the label with the color option")
+	}
+
+	createOption := makeCreateLabelOptions(m.opt, m.name)
+	var label *gitlab.Label
+	var err error
+	if m.group != "" {
+		label, err = m.client.CreateGroupLabel(m.group, createOption)
+	} else {
+		label, err = m.client.CreateLabel(m.project, createOption)
+	}
+	if err != nil {
+		return "", err
+	}
+	return label.Name, nil
+}
+
+func makeCreateLabelOptions(opt *CreateUpdateOption, name string) *gitlab.CreateLabelOptions {
+	createOption := &gitlab.CreateLabelOptions{
+		Name:  gitlab.String(name),
+		Color: gitlab.String(opt.Color),
+	}
+	if opt.Description != "" {
+		createOption.Description = gitlab.String(opt.Description)
+	}
+	return createOption
+}
+
+type updateMethod struct {
+	client  api.Label
+	opt     *CreateUpdateOption
+	project string
+	group   string
+	name    string
+}
+
+func (m *updateMethod) Process() (string, error) {
+	updateOption := makeUpdateLabelOptions(m.opt, m.name)
+	var err error
+	if m.group != "" {
+		_, err = m.client.UpdateGroupLabel(m.group, updateOption)
+	} else {
+		_, err = m.client.UpdateLabel(m.project, updateOption)
+	}
+	if err != nil {
+		return "", err
+	}
+	return "", nil
+}
+
+func makeUpdateLabelOptions(opt *CreateUpdateOption, name string) *gitlab.UpdateLabelOptions {
+	updateOption := &gitlab.UpdateLabelOptions{
+		Name: gitlab.String(name),
+	}
+	if opt.NewName != "" {
+		updateOption.NewName = gitlab.String(opt.NewName)
+	}
+	if opt.Color != "" {
+		updateOption.Color = gitlab.String(opt.Color)
+	}
+	if opt.Description != "" {
+		updateOption.Description = gitlab.String(opt.Description)
+	}
+	return updateOption
+}
@@ -0,0 +1,23 @@
+package label
+
+import "github.com/lighttiger2505/lab/internal/api"
+
+type deleteMethod struct {
+	client  api.Label
+	project string
+	group   string
+	name    string
+}
+
+func (m *deleteMethod) Process() (string, error) {
+	var err error
+	if m.group != "" {
+		err = m.client.DeleteGroupLabel(m.group, m.name)
+	} else {
+		err = m.client.DeleteLabel(m.project, m.name)
+	}
+	if err != nil {
+		return "", err
+	}
+	return "", nil
+}
@@ -0,0 +1,184 @@
+package label
+
+import (
+	"bytes"
+	"fmt"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+	"github.com/lighttiger2505/lab/internal/gitutil"
+	"github.com/lighttiger2505/lab/internal/ui"
+)
+
+const (
+	ExitCodeOK    int = iota //0
+	ExitCodeError int = iota //1
+)
+
+type Option struct {
+	ProjectProfileOption *internal.ProjectProfileOption `group:"Project, Profile Options"`
+	GroupOption          *GroupOption                   `group:"Group Options"`
+	CreateUpdateOption   *CreateUpdateOption            `group:"Create, Update Options"`
+	DeleteOption         *DeleteOption                  `group:"Delete Options"`
+	ListOption           *ListOption                    `group:"List Options"`
+	OutputOption         *internal.OutputOption         `group:"Output Options"`
+}
+
+type GroupOption struct {
+	Group string `short:"g" long:"group" value-name:"<group>" description:"Use the labels of the group instead of the project."`
+}
+
+type CreateUpdateOption struct {
+	Create      bool   `short:"c" long:"create" description:"Create the label."`
+	Color       string `long:"color" value-name:"<color>" description:"The color of the label, e.g. \"#FFAABB\" or \"red\"."`
+	Description string `long:"description" value-name:"<description>" description:"The description of the label."`
+	NewName     string `long:"new-name" value-name:"<name>" description:"Rename the label."`
+}
+
+func (o *CreateUpdateOption) hasUpdate() bool {
+	if o.Color != "" ||
+		o.Description != "" ||
+		o.NewName != "" {
+		return true
+	}
+	return false
+}
+
+type DeleteOption struct {
+	Delete bool `short:"D" long:"delete" description:"Delete the label."`
+}
+
+type ListOption struct {
+	Num int  `short:"n" long:"num" value-name:"<num>" default:"20" default-mask:"20" description:"Limit the number of label to output."`
+	All bool `long:"all" description:"Print all labels, ignore the num option."`
+}
+
+func newOptionParser(opt *Option) *flags.Parser {
+	opt.ProjectProfileOption = &internal.ProjectProfileOption{}
+	opt.GroupOption = &GroupOption{}
+	opt.CreateUpdateOption = &CreateUpdateOption{}
+	opt.DeleteOption = &DeleteOption{}
+	opt.ListOption = &ListOption{}
+	opt.OutputOption = &internal.OutputOption{}
+	parser := flags.NewParser(opt, flags.HelpFlag|flags.PassDoubleDash)
+	parser.Usage = `label - Create and Edit, List, Delete a label
+
+Synopsis:
+  # List label
+  lab label [-g <group>] [-n <num>] [--all] [--output=<format>]
+
+  # Create label
+  lab label <name> -c --color=<color> [--description=<description>] [-g <group>]
+
+  # Update label
+  lab label <name> [--new-name=<name>] [--color=<color>] [--description=<description>] [-g <group>]
+
+  # Delete label
+  lab label <name> -D [-g <group>]`
+	return parser
+}
+
+type LabelCommand struct {
+	UI              ui.UI
+	RemoteCollecter gitutil.Collecter
+	ClientFactory   api.APIClientFactory
+}
+
+func (c *LabelCommand) Synopsis() string {
+	return "Create and Edit, list a label"
+}
+
+func (c *LabelCommand) Help() string {
+	buf := &bytes.Buffer{}
+	var opt Option
+	parser := newOptionParser(&opt)
+	parser.WriteHelp(buf)
+	return buf.String()
+}
+
+func (c *LabelCommand) Run(args []string) int {
+	var opt Option
+	parser := newOptionParser(&opt)
+	parseArgs, err := parser.ParseArgs(args)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return ExitCodeError
+	}
+
+	pInfo, err := c.RemoteCollecter.CollectTarget(
+		opt.ProjectProfileOption.Project,
+		opt.ProjectProfileOption.Profile,
+	)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return ExitCodeError
+	}
+
+	if err := c.ClientFactory.Init(pInfo.ApiUrl(), pInfo.Token, pInfo.TLS); err != nil {
+		c.UI.Error(err.Error())
+		return ExitCodeError
+	}
+
+	method, err := c.getMethod(opt, parseArgs, pInfo)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return ExitCodeError
+	}
+
+	opt.OutputOption.Stream(c.UI)
+	res, err := method.Process()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return ExitCodeError
+	}
+
+	opt.OutputOption.Write(c.UI, res)
+
+	return ExitCodeOK
+}
+
+func (c *LabelCommand) getMethod(opt Option, args []string, pInfo *gitutil.GitLabProjectInfo) (internal.Method, error) {
+	client := c.ClientFactory.GetLabelClient()
+	group := opt.GroupOption.Group
+
+	if len(args) < 1 {
+		return &listMethod{
+			client:  client,
+			opt:     opt.ListOption,
+			output:  opt.OutputOption,
+			project: pInfo.Project,
+			group:   group,
+		}, nil
+	}
+
+	name := args[0]
+	createUpdateOption := opt.CreateUpdateOption
+	if opt.DeleteOption.Delete {
+		return &deleteMethod{
+			client:  client,
+			project: pInfo.Project,
+			group:   group,
+			name:    name,
+		}, nil
+	}
+	if createUpdateOption.Create {
+		return &createMethod{
+			client:  client,
+			opt:     createUpdateOption,
+			project: pInfo.Project,
+			group:   group,
+			name:    name,
+		}, nil
+	}
+	if createUpdateOption.hasUpdate() {
+		return &updateMethod{
+			client:  client,
+			opt:     createUpdateOption,
+			project: pInfo.Project,
+			group:   group,
+			name:    name,
+		}, nil
+	}
+	return nil, fmt.Errorf("Invalid args, please input the create, update or delete option with the label name")
+}
@@ -0,0 +1,122 @@
+package label
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func Test_createMethod_Process(t *testing.T) {
+	wantOption := &gitlab.CreateLabelOptions{
+		Name:        gitlab.String("bug"),
+		Color:       gitlab.String("#FF0000"),
+		Description: gitlab.String("Something is wrong"),
+	}
+	client := &api.MockLabelClient{
+		MockCreateLabel: func(project string, opt *gitlab.CreateLabelOptions) (*gitlab.Label, error) {
+			if project != "group/project" {
+				t.Errorf("invalid project, got %q", project)
+			}
+			if diff := cmp.Diff(opt, wantOption); diff != "" {
+				t.Errorf("invalide arg (-got +want)\n%s", diff)
+			}
+			return &gitlab.Label{Name: "bug"}, nil
+		},
+		MockCreateGroupLabel: func(group string, opt *gitlab.CreateLabelOptions) (*gitlab.Label, error) {
+			if group != "group" {
+				t.Errorf("invalid group, got %q", group)
+			}
+			if diff := cmp.Diff(opt, wantOption); diff != "" {
+				t.Errorf("invalide arg (-got +want)\n%s", diff)
+			}
+			return &gitlab.Label{Name: "bug"}, nil
+		},
+	}
+	opt := &CreateUpdateOption{
+		Create:      true,
+		Color:       "#FF0000",
+		Description: "Something is wrong",
+	}
+
+	tests := []struct {
+		name    string
+		method  internal.Method
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "project label",
+			method: &createMethod{
+				client:  client,
+				opt:     opt,
+				project: "group/project",
+				name:    "bug",
+			},
+			want: "bug",
+		},
+		{
+			name: "group label",
+			method: &createMethod{
+				client:  client,
+				opt:     opt,
+				project: "group/project",
+				group:   "group",
+				name:    "bug",
+			},
+			want: "bug",
+		},
+		{
+			name: "no color",
+			method: &createMethod{
+				client:  client,
+				opt:     &CreateUpdateOption{Create: true},
+				project: "group/project",
+				name:    "bug",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.method.Process()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("createMethod.Process() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("createMethod.Process() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_updateMethod_Process(t *testing.T) {
+	method := &updateMethod{
+		client: &api.MockLabelClient{
+			MockUpdateLabel: func(project string, opt *gitlab.UpdateLabelOptions) (*gitlab.Label, error) {
+				got := opt
+				want := &gitlab.UpdateLabelOptions{
+					Name:    gitlab.String("bug"),
+					NewName: gitlab.String("defect"),
+				}
+				if diff := cmp.Diff(got, want); diff != "" {
+					t.Errorf("invalide arg (-got +want)\n%s", diff)
+				}
+				return &gitlab.Label{Name: "defect"}, nil
+			},
+		},
+		opt:     &CreateUpdateOption{NewName: "defect"},
+		project: "group/project",
+		name:    "bug",
+	}
+	got, err := method.Process()
+	if err != nil {
+		t.Fatalf("updateMethod.Process() error = %v", err)
+	}
+	if got != "" {
+		t.Errorf("updateMethod.Process() = %v, want empty", got)
+	}
+}
@@ -0,0 +1,56 @@
+package label
+
+import (
+	"github.com/fatih/color"
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+type listMethod struct {
+	client  api.Label
+	opt     *ListOption
+	output  *internal.OutputOption
+	project string
+	group   string
+}
+
+func (m *listMethod) Process() (string, error) {
+	w := m.output.NewListWriter()
+	f := func(labels []*gitlab.Label) error {
+		return w.WritePage(labels, func() [][]string {
+			return listOutput(labels)
+		})
+	}
+
+	var err error
+	if m.group != "" {
+		err = m.client.ListGroupLabels(m.group, makeListLabelsOptions(), internal.Limit(m.opt.Num, m.opt.All), f)
+	} else {
+		err = m.client.ListLabels(m.project, makeListLabelsOptions(), internal.Limit(m.opt.Num, m.opt.All), f)
+	}
+	if err != nil {
+		return "", err
+	}
+	return w.Flush()
+}
+
+func makeListLabelsOptions() *gitlab.ListLabelsOptions {
+	return &gitlab.ListLabelsOptions{
+		Page: 1,
+	}
+}
+
+func listOutput(labels []*gitlab.Label) [][]string {
+	yellow := color.New(color.FgYellow).SprintFunc()
+	var outputs [][]string
+	for _, label := range labels {
+		output := []string{
+			yellow(label.Name),
+			label.Color,
+			label.Description,
+		}
+		outputs = append(outputs, output)
+	}
+	return outputs
+}
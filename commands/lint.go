@@ -4,12 +4,61 @@ import (
 	"bytes"
 	"fmt"
 	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 
+	flags "github.com/jessevdk/go-flags"
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/git"
 	"github.com/lighttiger2505/lab/internal/api"
+	"github.com/lighttiger2505/lab/internal/ciconfig"
 	"github.com/lighttiger2505/lab/internal/gitutil"
 	"github.com/lighttiger2505/lab/internal/ui"
 )
 
+// ciConfigFile is the CI config linted when no file is given.
+const ciConfigFile = ".gitlab-ci.yml"
+
+type LintCommandOption struct {
+	ProjectProfileOption *internal.ProjectProfileOption `group:"Project, Profile Options"`
+	LintOption           *LintOption                    `group:"Lint Options"`
+}
+
+func newLintOptionParser(opt *LintCommandOption) *flags.Parser {
+	opt.ProjectProfileOption = &internal.ProjectProfileOption{}
+	opt.LintOption = &LintOption{}
+	parser := flags.NewParser(opt, flags.HelpFlag|flags.PassDoubleDash)
+	parser.Usage = `lint - validate .gitlab-ci.yml
+
+Synopsis:
+  # Validate .gitlab-ci.yml at the top of the repository
+  lab lint [<gitlab-ci.yml file path>]
+
+  # Print the CI config with the includes merged
+  lab lint [<gitlab-ci.yml file path>] --merged
+
+  # Validate the CI config of the project at the ref
+  lab lint --project=<group>/<name> [--ref=<ref>]
+
+  # List the jobs the pipeline of the ref would run
+  lab lint [<gitlab-ci.yml file path>] --dry-run [--ref=<ref>]
+
+  # Validate and simulate without GitLab
+  lab lint [<gitlab-ci.yml file path>] --offline [--dry-run [--ref=<ref>] [--tag | --merge-request]]`
+	return parser
+}
+
+type LintOption struct {
+	Ref          string `long:"ref" value-name:"<ref>" description:"Validate in the context of the project at the ref. The default branch of the project by default."`
+	DryRun       bool   `long:"dry-run" description:"Simulate the pipeline of the ref, and list the jobs it would run."`
+	Merged       bool   `long:"merged" description:"Print the CI config with the includes merged."`
+	Offline      bool   `long:"offline" description:"Validate by the bundled schema without GitLab. Only the local includes are merged."`
+	Tag          bool   `long:"tag" description:"Simulate the pipeline of the ref as a tag. Only with the offline option."`
+	MergeRequest bool   `long:"merge-request" description:"Simulate the merge request pipeline of the ref as the source branch. Only with the offline option."`
+}
+
 type LintCommand struct {
 	UI              ui.UI
 	RemoteCollecter gitutil.Collecter
@@ -21,50 +70,241 @@ func (c *LintCommand) Synopsis() string {
 }
 
 func (c *LintCommand) Help() string {
+	var opt LintCommandOption
+	parser := newLintOptionParser(&opt)
 	buf := &bytes.Buffer{}
-	buf.WriteString(`Usage:
-    lab lint [gitlab-ci.yal file path]`)
+	parser.WriteHelp(buf)
 	return buf.String()
 }
 
 func (c *LintCommand) Run(args []string) int {
-	pInfo, err := c.RemoteCollecter.CollectTarget("", "")
+	var opt LintCommandOption
+	parser := newLintOptionParser(&opt)
+	parseArgs, err := parser.ParseArgs(args)
 	if err != nil {
 		c.UI.Error(err.Error())
 		return ExitCodeError
 	}
+	lintOpt := opt.LintOption
+	project := opt.ProjectProfileOption.Project
 
-	if err := c.ClientFactory.Init(pInfo.ApiUrl(), pInfo.Token); err != nil {
+	// The CI config of the project is validated when the project is given
+	// without a file
+	path, content := ciConfigFile, ""
+	if len(parseArgs) > 0 || project == "" || lintOpt.Offline {
+		path, err = lintFilePath(parseArgs)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return ExitCodeError
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Failed read validate file. \nError: %s", err.Error()))
+			return ExitCodeFileError
+		}
+		content = string(b)
+	}
+
+	var result *api.LintResult
+	if lintOpt.Offline {
+		result, err = lintOffline(path, lintOpt)
+	} else {
+		result, err = c.lint(project, opt.ProjectProfileOption.Profile, content, lintOpt)
+	}
+	if err != nil {
 		c.UI.Error(err.Error())
 		return ExitCodeError
 	}
+
+	for _, msg := range lintMessages(path, content, result) {
+		c.UI.Error(msg)
+	}
+	if !result.IsValid() {
+		return ExitCodeError
+	}
+
+	if lintOpt.Merged {
+		c.UI.Message(strings.TrimRight(result.MergedYaml, "\n"))
+	}
+	if lintOpt.DryRun {
+		c.UI.Message(lintJobsOutput(result.Jobs))
+	}
+	return ExitCodeOK
+}
+
+func (c *LintCommand) lint(project, profile, content string, opt *LintOption) (*api.LintResult, error) {
+	pInfo, err := c.RemoteCollecter.CollectTarget(project, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.ClientFactory.Init(pInfo.ApiUrl(), pInfo.Token, pInfo.TLS); err != nil {
+		return nil, err
+	}
 	client := c.ClientFactory.GetLintClient()
 
-	var content string = ""
-	if len(args) > 0 {
-		b, err := ioutil.ReadFile(args[0])
-		content = string(b)
+	if project != "" || opt.Ref != "" || opt.DryRun {
+		return client.ProjectLint(pInfo.Project, &api.ProjectLintOptions{
+			Content:     content,
+			Ref:         opt.Ref,
+			DryRun:      opt.DryRun,
+			IncludeJobs: opt.DryRun,
+		})
+	}
+	return client.Lint(content)
+}
+
+// lintOffline validates the CI config by the bundled schema, and simulates
+// the pipeline of the ref for the dry run option. The local includes are
+// read from the top of the repository.
+func lintOffline(path string, opt *LintOption) (*api.LintResult, error) {
+	root, err := git.Root()
+	if err != nil {
+		root = filepath.Dir(path)
+	}
+
+	config, err := ciconfig.Load(root, path)
+	if err != nil {
+		return &api.LintResult{Status: "invalid", Errors: []string{err.Error()}}, nil
+	}
+	result := &api.LintResult{Status: "valid", Warnings: config.Warnings}
+	if errs := ciconfig.Validate(config); len(errs) > 0 {
+		result.Status = "invalid"
+		result.Errors = errs
+		return result, nil
+	}
+
+	if opt.Merged {
+		merged, err := config.YAML()
 		if err != nil {
-			c.UI.Error(fmt.Sprintf("Failed read validate file. \nError: %s", err.Error()))
-			return ExitCodeError
+			return nil, err
 		}
-	} else {
-		c.UI.Error("Required validate file.")
-		return ExitCodeError
+		result.MergedYaml = merged
+	}
+
+	if opt.DryRun {
+		pipeline, err := offlinePipeline(root, opt)
+		if err != nil {
+			return nil, err
+		}
+		jobs, created, err := ciconfig.Simulate(config, pipeline)
+		if err != nil {
+			result.Status = "invalid"
+			result.Errors = []string{err.Error()}
+			return result, nil
+		}
+		if !created {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("workflow:rules create no pipeline for %s", pipeline.Ref))
+		}
+		for _, job := range jobs {
+			result.Jobs = append(result.Jobs, &api.LintJob{
+				Name:         job.Name,
+				Stage:        job.Stage,
+				When:         job.When,
+				AllowFailure: job.AllowFailure,
+			})
+		}
+	}
+	return result, nil
+}
+
+// offlinePipeline returns the pipeline of the ref, or of the current branch.
+func offlinePipeline(root string, opt *LintOption) (*ciconfig.Pipeline, error) {
+	ref := opt.Ref
+	if ref == "" {
+		currentBranch, err := git.CurrentBranch()
+		if err != nil {
+			return nil, err
+		}
+		ref = currentBranch
+	}
+
+	pipeline := &ciconfig.Pipeline{
+		Ref:       ref,
+		Tag:       opt.Tag,
+		Source:    ciconfig.SourcePush,
+		Variables: map[string]string{},
+		Root:      root,
+	}
+	if opt.MergeRequest {
+		pipeline.Source = ciconfig.SourceMergeRequest
 	}
+	if defaultBranch, err := git.DefaultBranch(); err == nil {
+		pipeline.Variables["CI_DEFAULT_BRANCH"] = defaultBranch
+	}
+	return pipeline, nil
+}
 
-	result, err := client.Lint(content)
+// lintFilePath returns the given file, or the CI config at the top of the
+// repository.
+func lintFilePath(args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+	root, err := git.Root()
 	if err != nil {
-		c.UI.Error(err.Error())
-		return ExitCodeError
+		return "", err
 	}
+	return filepath.Join(root, ciConfigFile), nil
+}
 
-	if result.Status == "invalid" {
-		for _, msg := range result.Errors {
-			c.UI.Message(msg)
-			return ExitCodeError
+var (
+	// lintErrorLine matches the line of the YAML syntax errors, e.g.
+	// "did not find expected key while parsing a block mapping at line 3 column 1"
+	// of GitLab, or "yaml: line 3: did not find expected key" of the offline
+	// lint
+	lintErrorLine = regexp.MustCompile(`^yaml: line ([0-9]+):|at line ([0-9]+)`)
+	// lintErrorJob matches the job of the errors, e.g.
+	// "jobs:unit config contains unknown keys: foo"
+	lintErrorJob = regexp.MustCompile(`^jobs:([^: ]+)`)
+)
+
+// lintMessages returns the errors and the warnings of a lint prefixed with
+// the file and the line they were found at, when the line is known.
+func lintMessages(path, content string, result *api.LintResult) []string {
+	var messages []string
+	for _, msg := range result.Errors {
+		messages = append(messages, fmt.Sprintf("%s: error: %s", lintLocation(path, content, msg), msg))
+	}
+	for _, msg := range result.Warnings {
+		messages = append(messages, fmt.Sprintf("%s: warning: %s", lintLocation(path, content, msg), msg))
+	}
+	return messages
+}
+
+func lintLocation(path, content, msg string) string {
+	if match := lintErrorLine.FindStringSubmatch(msg); match != nil {
+		return path + ":" + match[1] + match[2]
+	}
+	if match := lintErrorJob.FindStringSubmatch(msg); match != nil {
+		if line := keyLine(content, match[1]); line > 0 {
+			return path + ":" + strconv.Itoa(line)
 		}
 	}
+	return path
+}
 
-	return ExitCodeOK
+// keyLine returns the line of a top level key in a YAML, or zero when the
+// key is not found.
+func keyLine(content, key string) int {
+	for i, line := range strings.Split(content, "\n") {
+		for _, prefix := range []string{key, `"` + key + `"`, `'` + key + `'`} {
+			if strings.HasPrefix(line, prefix+":") {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
+
+func lintJobsOutput(jobs []*api.LintJob) string {
+	var rows [][]string
+	for _, job := range jobs {
+		when := job.When
+		if job.AllowFailure {
+			when += " (allow failure)"
+		}
+		rows = append(rows, []string{job.Stage, job.Name, when})
+	}
+	return internal.Columnize(rows)
 }
@@ -0,0 +1,161 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/lighttiger2505/lab/internal/api"
+	"github.com/lighttiger2505/lab/internal/gitutil"
+	"github.com/lighttiger2505/lab/internal/ui"
+)
+
+const lintConfig = `stages: [build, test]
+build:
+  stage: build
+unit:
+  stage: test
+  foo: bar
+`
+
+func writeLintConfig(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "lab-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, ciConfigFile)
+	if err := ioutil.WriteFile(path, []byte(lintConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path, func() { os.RemoveAll(dir) }
+}
+
+func TestLintCommand_Run_Invalid(t *testing.T) {
+	path, cleanup := writeLintConfig(t)
+	defer cleanup()
+
+	mockClient := &api.MockLintClient{
+		MockLint: func(content string) (*api.LintResult, error) {
+			return &api.LintResult{
+				Status: "invalid",
+				Errors: []string{
+					"jobs:unit config contains unknown keys: foo",
+					"root config contains unknown keys: variable",
+				},
+				Warnings: []string{"jobs:build may allow multiple pipelines to run"},
+			}, nil
+		},
+	}
+	mockUI := ui.NewMockUi()
+	c := LintCommand{
+		UI:              mockUI,
+		RemoteCollecter: &gitutil.MockCollecter{},
+		ClientFactory: &api.MockAPIClientFactory{
+			MockGetLintClient: func() api.Lint { return mockClient },
+		},
+	}
+
+	if code := c.Run([]string{path}); code != ExitCodeError {
+		t.Fatalf("wrong exit code. got %d, want %d", code, ExitCodeError)
+	}
+	got := mockUI.ErrorWriter.String()
+	want := path + ":4: error: jobs:unit config contains unknown keys: foo\n" +
+		path + ": error: root config contains unknown keys: variable\n" +
+		path + ":2: warning: jobs:build may allow multiple pipelines to run\n"
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("invalide arg (-got +want)\n%s", diff)
+	}
+}
+
+func TestLintCommand_Run_DryRun(t *testing.T) {
+	path, cleanup := writeLintConfig(t)
+	defer cleanup()
+
+	var gotOpt *api.ProjectLintOptions
+	mockClient := &api.MockLintClient{
+		MockProjectLint: func(repositoryName string, opt *api.ProjectLintOptions) (*api.LintResult, error) {
+			gotOpt = opt
+			return &api.LintResult{
+				Valid: true,
+				Jobs: []*api.LintJob{
+					{Name: "build", Stage: "build", When: "on_success"},
+					{Name: "unit", Stage: "test", When: "manual", AllowFailure: true},
+				},
+			}, nil
+		},
+	}
+	mockUI := ui.NewMockUi()
+	c := LintCommand{
+		UI:              mockUI,
+		RemoteCollecter: &gitutil.MockCollecter{},
+		ClientFactory: &api.MockAPIClientFactory{
+			MockGetLintClient: func() api.Lint { return mockClient },
+		},
+	}
+
+	if code := c.Run([]string{path, "--dry-run", "--ref", "develop"}); code != ExitCodeOK {
+		t.Fatalf("wrong exit code. errors: \n%s", mockUI.ErrorWriter.String())
+	}
+	wantOpt := &api.ProjectLintOptions{Content: lintConfig, Ref: "develop", DryRun: true, IncludeJobs: true}
+	if diff := cmp.Diff(gotOpt, wantOpt); diff != "" {
+		t.Errorf("invalide arg (-got +want)\n%s", diff)
+	}
+	got := mockUI.Writer.String()
+	want := "build  build  on_success\ntest   unit   manual (allow failure)\n"
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("invalide arg (-got +want)\n%s", diff)
+	}
+}
+
+func Test_lintLocation(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  string
+		want string
+	}{
+		{
+			name: "syntax error",
+			msg:  "(<unknown>): did not find expected key while parsing a block mapping at line 3 column 1",
+			want: ".gitlab-ci.yml:3",
+		},
+		{
+			name: "job",
+			msg:  "jobs:unit config contains unknown keys: foo",
+			want: ".gitlab-ci.yml:4",
+		},
+		{
+			name: "unknown job",
+			msg:  "jobs:lint config contains unknown keys: foo",
+			want: ".gitlab-ci.yml",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lintLocation(ciConfigFile, lintConfig, tt.msg); got != tt.want {
+				t.Errorf("lintLocation() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintCommand_Run_Offline(t *testing.T) {
+	path, cleanup := writeLintConfig(t)
+	defer cleanup()
+
+	// The offline lint does not use GitLab
+	mockUI := ui.NewMockUi()
+	c := LintCommand{UI: mockUI}
+
+	if code := c.Run([]string{path, "--offline"}); code != ExitCodeError {
+		t.Fatalf("wrong exit code. got %d, want %d", code, ExitCodeError)
+	}
+	got := mockUI.ErrorWriter.String()
+	want := path + ":2: error: jobs:build config should implement a script: or a trigger: keyword\n" +
+		path + ":4: error: jobs:unit config contains unknown keys: foo\n" +
+		path + ":4: error: jobs:unit config should implement a script: or a trigger: keyword\n"
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("invalide arg (-got +want)\n%s", diff)
+	}
+}
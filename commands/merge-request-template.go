@@ -2,23 +2,23 @@ package commands
 
 import (
 	"bytes"
-	"strings"
 
 	flags "github.com/jessevdk/go-flags"
 	"github.com/lighttiger2505/lab/commands/internal"
 	"github.com/lighttiger2505/lab/internal/api"
 	"github.com/lighttiger2505/lab/internal/gitutil"
 	"github.com/lighttiger2505/lab/internal/ui"
-	"github.com/ryanuber/columnize"
 	gitlab "github.com/xanzy/go-gitlab"
 )
 
 type MergeRequestTemplateCommnadOption struct {
 	ProjectProfileOption *internal.ProjectProfileOption `group:"Project, Profile Options"`
+	OutputOption         *internal.OutputOption         `group:"Output Options"`
 }
 
 func newMergeRequestTemplateCommandParser(opt *MergeRequestTemplateCommnadOption) *flags.Parser {
 	opt.ProjectProfileOption = &internal.ProjectProfileOption{}
+	opt.OutputOption = &internal.OutputOption{}
 	parser := flags.NewParser(opt, flags.HelpFlag|flags.PassDoubleDash)
 	parser.Usage = "merge-request-template [options]"
 	return parser
@@ -60,7 +60,7 @@ func (c *MergeRequestTemplateCommand) Run(args []string) int {
 		return ExitCodeError
 	}
 
-	if err := c.ClientFactory.Init(pInfo.ApiUrl(), pInfo.Token); err != nil {
+	if err := c.ClientFactory.Init(pInfo.ApiUrl(), pInfo.Token, pInfo.TLS); err != nil {
 		c.UI.Error(err.Error())
 		return ExitCodeError
 	}
@@ -87,8 +87,14 @@ func (c *MergeRequestTemplateCommand) Run(args []string) int {
 			c.UI.Error(err.Error())
 			return ExitCodeError
 		}
-		result := columnize.SimpleFormat(mergeRequestTemplateOutput(treeNode))
-		c.UI.Message(result)
+		result, err := opt.OutputOption.FormatList(treeNode, func() [][]string {
+			return mergeRequestTemplateOutput(treeNode)
+		})
+		if err != nil {
+			c.UI.Error(err.Error())
+			return ExitCodeError
+		}
+		opt.OutputOption.Write(c.UI, result)
 	}
 
 	return ExitCodeOK
@@ -109,12 +115,12 @@ func makeShowMergeRequestTemplateOption() *gitlab.GetRawFileOptions {
 	return opt
 }
 
-func mergeRequestTemplateOutput(treeNode []*gitlab.TreeNode) []string {
-	var outputs []string
+func mergeRequestTemplateOutput(treeNode []*gitlab.TreeNode) [][]string {
+	var outputs [][]string
 	for _, node := range treeNode {
-		output := strings.Join([]string{
+		output := []string{
 			node.Name,
-		}, "|")
+		}
 		outputs = append(outputs, output)
 	}
 	return outputs
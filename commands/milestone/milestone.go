@@ -2,7 +2,6 @@ package milestone
 
 import (
 	"bytes"
-	"strings"
 
 	"github.com/fatih/color"
 	flags "github.com/jessevdk/go-flags"
@@ -10,7 +9,6 @@ import (
 	"github.com/lighttiger2505/lab/internal/api"
 	"github.com/lighttiger2505/lab/internal/gitutil"
 	"github.com/lighttiger2505/lab/internal/ui"
-	"github.com/ryanuber/columnize"
 	gitlab "github.com/xanzy/go-gitlab"
 )
 
@@ -22,15 +20,18 @@ const (
 type Option struct {
 	ProjectProfileOption *internal.ProjectProfileOption `group:"Project, Profile Options"`
 	ListOption           *ListOption                    `group:"List Options"`
+	OutputOption         *internal.OutputOption         `group:"Output Options"`
 }
 
 type ListOption struct {
-	Num int `short:"n" long:"num" value-name:"<num>" default:"20" default-mask:"20" description:"Limit the number of milestone to output."`
+	Num int  `short:"n" long:"num" value-name:"<num>" default:"20" default-mask:"20" description:"Limit the number of milestone to output."`
+	All bool `long:"all" description:"Print all milestones, ignore the num option."`
 }
 
 func newOptionParser(opt *Option) *flags.Parser {
 	opt.ProjectProfileOption = &internal.ProjectProfileOption{}
 	opt.ListOption = &ListOption{}
+	opt.OutputOption = &internal.OutputOption{}
 	parser := flags.NewParser(opt, flags.HelpFlag|flags.PassDoubleDash)
 	parser.Usage = `config - Edit and Show config
 
@@ -79,32 +80,42 @@ func (c *MilestoneCommand) Run(args []string) int {
 		return ExitCodeError
 	}
 
-	if err := c.ClientFactory.Init(pInfo.ApiUrl(), pInfo.Token); err != nil {
+	if err := c.ClientFactory.Init(pInfo.ApiUrl(), pInfo.Token, pInfo.TLS); err != nil {
 		c.UI.Error(err.Error())
 		return ExitCodeError
 	}
 	client := c.ClientFactory.GetMilestoneClient()
 
-	milestones, err := client.ListMilestones(
+	opt.OutputOption.Stream(c.UI)
+	w := opt.OutputOption.NewListWriter()
+	err = client.ListMilestones(
 		pInfo.Project,
 		makeListMilestoneOptions(opt.ListOption),
+		internal.Limit(opt.ListOption.Num, opt.ListOption.All),
+		func(milestones []*gitlab.Milestone) error {
+			return w.WritePage(milestones, func() [][]string {
+				return milestoneOutput(milestones)
+			})
+		},
 	)
 	if err != nil {
 		c.UI.Error(err.Error())
 		return ExitCodeError
 	}
 
-	output := milestoneOutput(milestones)
-	result := columnize.SimpleFormat(output)
-	c.UI.Message(result)
+	result, err := w.Flush()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return ExitCodeError
+	}
+	opt.OutputOption.Write(c.UI, result)
 
 	return ExitCodeOK
 }
 
 func makeListMilestoneOptions(listOption *ListOption) *gitlab.ListMilestonesOptions {
 	lopt := &gitlab.ListOptions{
-		Page:    1,
-		PerPage: listOption.Num,
+		Page: 1,
 	}
 	opt := &gitlab.ListMilestonesOptions{
 		ListOptions: *lopt,
@@ -112,15 +123,15 @@ func makeListMilestoneOptions(listOption *ListOption) *gitlab.ListMilestonesOpti
 	return opt
 }
 
-func milestoneOutput(milestones []*gitlab.Milestone) []string {
+func milestoneOutput(milestones []*gitlab.Milestone) [][]string {
 	yellow := color.New(color.FgYellow).SprintFunc()
-	var outputs []string
+	var outputs [][]string
 	for _, milestone := range milestones {
-		output := strings.Join([]string{
+		output := []string{
 			yellow(milestone.ID),
 			milestone.Title,
 			milestone.Description,
-		}, "|")
+		}
 		outputs = append(outputs, output)
 	}
 	return outputs
@@ -0,0 +1,129 @@
+package mr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lighttiger2505/lab/git"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+type checkoutMethod struct {
+	mrClient      api.MergeRequest
+	projectClient api.Project
+	gitClient     git.Client
+	opt           *CheckoutOption
+	project       string
+	id            int
+}
+
+func (m *checkoutMethod) Process() (string, error) {
+	mergeRequest, err := m.mrClient.GetMergeRequest(m.id, m.project)
+	if err != nil {
+		return "", err
+	}
+
+	remotes, err := m.gitClient.RemoteInfos()
+	if err != nil {
+		return "", err
+	}
+	targetRemote := findRemote(remotes, m.project)
+
+	// The head of a merge request is fetched from the target project, even if
+	// the source branch is in a fork
+	if err := m.gitClient.Fetch(targetRemote, mergeRequestHeadRef(m.id)); err != nil {
+		return "", err
+	}
+
+	if m.opt.Detach {
+		if err := m.gitClient.CheckoutDetach("FETCH_HEAD"); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("HEAD is now at the head of merge request !%d", m.id), nil
+	}
+
+	sourceRemote := targetRemote
+	if mergeRequest.SourceProjectID != mergeRequest.TargetProjectID {
+		sourceRemote, err = m.forkRemote(remotes, targetRemote, mergeRequest.SourceProjectID)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	branch := m.opt.Branch
+	if branch == "" {
+		branch = mergeRequest.SourceBranch
+	}
+	if err := m.gitClient.CheckoutNewBranch(branch, "FETCH_HEAD"); err != nil {
+		return "", err
+	}
+	if err := m.gitClient.SetUpstream(branch, sourceRemote, mergeRequest.SourceBranch); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Switched to a new branch '%s' tracking %s/%s", branch, sourceRemote, mergeRequest.SourceBranch), nil
+}
+
+// forkRemote returns the remote of the source project of a merge request
+// from a fork. The remote is added when there is none.
+func (m *checkoutMethod) forkRemote(remotes []*git.RemoteInfo, targetRemote string, pid int) (string, error) {
+	project, err := m.projectClient.GetProject(pid)
+	if err != nil {
+		return "", err
+	}
+	for _, remote := range remotes {
+		if remote.RepositoryFullName() == project.PathWithNamespace {
+			return remote.Remote, nil
+		}
+	}
+
+	name := forkRemoteName(remotes, project)
+	url, err := m.forkURL(targetRemote, project)
+	if err != nil {
+		return "", err
+	}
+	if err := m.gitClient.AddRemote(name, url); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// forkURL uses the same protocol as the remote of the target project.
+func (m *checkoutMethod) forkURL(targetRemote string, project *gitlab.Project) (string, error) {
+	targetURL, err := m.gitClient.RemoteURL(targetRemote)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(targetURL, "http://") || strings.HasPrefix(targetURL, "https://") {
+		return project.HTTPURLToRepo, nil
+	}
+	return project.SSHURLToRepo, nil
+}
+
+func forkRemoteName(remotes []*git.RemoteInfo, project *gitlab.Project) string {
+	name := project.PathWithNamespace
+	if project.Namespace != nil && project.Namespace.Path != "" {
+		name = project.Namespace.Path
+	}
+	for _, remote := range remotes {
+		if remote.Remote == name {
+			return strings.Replace(project.PathWithNamespace, "/", "-", -1)
+		}
+	}
+	return name
+}
+
+// findRemote returns the remote of the project, or "origin" when no remote
+// matches it.
+func findRemote(remotes []*git.RemoteInfo, project string) string {
+	for _, remote := range remotes {
+		if remote.RepositoryFullName() == project {
+			return remote.Remote
+		}
+	}
+	return "origin"
+}
+
+func mergeRequestHeadRef(iid int) string {
+	return fmt.Sprintf("refs/merge-requests/%d/head", iid)
+}
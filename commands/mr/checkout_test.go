@@ -0,0 +1,143 @@
+package mr
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lighttiger2505/lab/git"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func newMockCheckoutGitClient(calls *[]string) *git.MockClient {
+	record := func(args ...string) {
+		*calls = append(*calls, strings.Join(args, " "))
+	}
+	return &git.MockClient{
+		MockRemoteInfos: func() ([]*git.RemoteInfo, error) {
+			return []*git.RemoteInfo{
+				&git.RemoteInfo{Remote: "origin", Domain: "gitlab.com", Group: "group", Repository: "repo"},
+			}, nil
+		},
+		MockRemoteURL: func(remote string) (string, error) {
+			return "git@gitlab.com:group/repo.git", nil
+		},
+		MockAddRemote: func(name, url string) error {
+			record("remote add", name, url)
+			return nil
+		},
+		MockFetch: func(remote string, refspecs ...string) error {
+			record(append([]string{"fetch", remote}, refspecs...)...)
+			return nil
+		},
+		MockCheckoutNewBranch: func(branch, startPoint string) error {
+			record("checkout -b", branch, startPoint)
+			return nil
+		},
+		MockCheckoutDetach: func(startPoint string) error {
+			record("checkout --detach", startPoint)
+			return nil
+		},
+		MockSetUpstream: func(branch, remote, remoteBranch string) error {
+			record("upstream", branch, remote, remoteBranch)
+			return nil
+		},
+	}
+}
+
+func TestCheckoutMethod_Process(t *testing.T) {
+	tests := []struct {
+		name      string
+		opt       *CheckoutOption
+		sourcePID int
+		want      string
+		wantCalls []string
+	}{
+		{
+			name:      "same project",
+			opt:       &CheckoutOption{},
+			sourcePID: 1,
+			want:      "Switched to a new branch 'feature' tracking origin/feature",
+			wantCalls: []string{
+				"fetch origin refs/merge-requests/12/head",
+				"checkout -b feature FETCH_HEAD",
+				"upstream feature origin feature",
+			},
+		},
+		{
+			name:      "branch name",
+			opt:       &CheckoutOption{Branch: "review"},
+			sourcePID: 1,
+			want:      "Switched to a new branch 'review' tracking origin/feature",
+			wantCalls: []string{
+				"fetch origin refs/merge-requests/12/head",
+				"checkout -b review FETCH_HEAD",
+				"upstream review origin feature",
+			},
+		},
+		{
+			name:      "fork",
+			opt:       &CheckoutOption{},
+			sourcePID: 2,
+			want:      "Switched to a new branch 'feature' tracking contributor/feature",
+			wantCalls: []string{
+				"fetch origin refs/merge-requests/12/head",
+				"remote add contributor git@gitlab.com:contributor/repo.git",
+				"checkout -b feature FETCH_HEAD",
+				"upstream feature contributor feature",
+			},
+		},
+		{
+			name:      "detach",
+			opt:       &CheckoutOption{Detach: true},
+			sourcePID: 2,
+			want:      "HEAD is now at the head of merge request !12",
+			wantCalls: []string{
+				"fetch origin refs/merge-requests/12/head",
+				"checkout --detach FETCH_HEAD",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			m := &checkoutMethod{
+				mrClient: &api.MockLabMergeRequestClient{
+					MockGetMergeRequest: func(pid int, repositoryName string) (*gitlab.MergeRequest, error) {
+						return &gitlab.MergeRequest{
+							IID:             12,
+							SourceBranch:    "feature",
+							SourceProjectID: tt.sourcePID,
+							TargetProjectID: 1,
+						}, nil
+					},
+				},
+				projectClient: &api.MockProjectClient{
+					MockGetProject: func(pid int) (*gitlab.Project, error) {
+						return &gitlab.Project{
+							PathWithNamespace: "contributor/repo",
+							SSHURLToRepo:      "git@gitlab.com:contributor/repo.git",
+							HTTPURLToRepo:     "https://gitlab.com/contributor/repo.git",
+							Namespace:         &gitlab.ProjectNamespace{Path: "contributor"},
+						}, nil
+					},
+				},
+				gitClient: newMockCheckoutGitClient(&calls),
+				opt:       tt.opt,
+				project:   "group/repo",
+				id:        12,
+			}
+			got, err := m.Process()
+			if err != nil {
+				t.Fatalf("checkoutMethod.Process() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("checkoutMethod.Process() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(calls, tt.wantCalls) {
+				t.Errorf("git calls = \n%v\nwant\n%v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package mr
+
+import (
+	"strconv"
+
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+type commentMethod struct {
+	client   api.Note
+	opt      *internal.CommentOption
+	message  string
+	project  string
+	id       int
+	editFunc func(program, file string) error
+}
+
+func (m *commentMethod) Process() (string, error) {
+	quote := ""
+	if m.opt.Quote != 0 {
+		note, err := m.client.GetMergeRequestNote(m.project, m.id, m.opt.Quote)
+		if err != nil {
+			return "", err
+		}
+		quote = internal.QuoteNote(note)
+	}
+
+	body, err := internal.NewComment(quote, m.message, m.editFunc)
+	if err != nil {
+		return "", err
+	}
+
+	note, err := m.client.CreateMergeRequestNote(
+		m.project,
+		m.id,
+		&gitlab.CreateMergeRequestNoteOptions{Body: gitlab.String(body)},
+	)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(note.ID), nil
+}
+
+type editNoteMethod struct {
+	client   api.Note
+	opt      *internal.CommentOption
+	message  string
+	project  string
+	id       int
+	editFunc func(program, file string) error
+}
+
+func (m *editNoteMethod) Process() (string, error) {
+	body := m.message
+	if body == "" {
+		note, err := m.client.GetMergeRequestNote(m.project, m.id, m.opt.EditNote)
+		if err != nil {
+			return "", err
+		}
+		body, err = internal.EditComment(note.Body, m.editFunc)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	_, err := m.client.UpdateMergeRequestNote(
+		m.project,
+		m.id,
+		m.opt.EditNote,
+		&gitlab.UpdateMergeRequestNoteOptions{Body: gitlab.String(body)},
+	)
+	if err != nil {
+		return "", err
+	}
+	return "", nil
+}
+
+type deleteNoteMethod struct {
+	client  api.Note
+	opt     *internal.CommentOption
+	project string
+	id      int
+}
+
+func (m *deleteNoteMethod) Process() (string, error) {
+	if err := m.client.DeleteMergeRequestNote(m.project, m.id, m.opt.DeleteNote); err != nil {
+		return "", err
+	}
+	return "", nil
+}
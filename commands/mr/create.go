@@ -117,6 +117,9 @@ func makeCreateMergeRequestOption(opt *CreateUpdateOption, title, description, b
 	if opt.MilestoneID != 0 {
 		createMergeRequestOption.MilestoneID = gitlab.Int(opt.MilestoneID)
 	}
+	if labels := opt.getLabels(nil); len(labels) > 0 {
+		createMergeRequestOption.Labels = gitlab.Labels(labels)
+	}
 	return createMergeRequestOption
 }
 
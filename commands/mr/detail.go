@@ -2,6 +2,8 @@ package mr
 
 import (
 	"fmt"
+	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/fatih/color"
@@ -15,47 +17,71 @@ type detailMethod struct {
 	mrClient   api.MergeRequest
 	noteClient api.Note
 	opt        *ShowOption
+	output     *internal.OutputOption
 	project    string
 	id         int
 }
 
+// mergeRequestDetail is the merge request with its comments, rendered by
+// json and yaml output.
+type mergeRequestDetail struct {
+	*gitlab.MergeRequest
+	Approvals *gitlab.MergeRequestApprovals `json:"approvals,omitempty"`
+	Notes     []*gitlab.Note                `json:"notes,omitempty"`
+}
+
 func (m *detailMethod) Process() (string, error) {
 	// Do get merge request
 	mergeRequest, err := m.mrClient.GetMergeRequest(m.id, m.project)
 	if err != nil {
 		return "", err
 	}
-	res := outMergeRequestDetail(mergeRequest)
 
-	if m.opt.NoComment {
-		return res, nil
+	approvals, err := m.mrClient.GetMergeRequestApprovals(m.id, m.project)
+	if err != nil {
+		// Approvals are not available on every GitLab edition
+		switch api.StatusCode(err) {
+		case http.StatusForbidden, http.StatusNotFound:
+		default:
+			return "", err
+		}
 	}
 
-	notes, err := m.noteClient.GetMergeRequestNotes(m.project, m.id, makeListMergeRequestNotesOptions())
-	if err != nil {
-		return "", err
+	var notes []*gitlab.Note
+	if !m.opt.NoComment {
+		notes, err = m.noteClient.GetMergeRequestNotes(m.project, m.id, makeListMergeRequestNotesOptions())
+		if err != nil {
+			return "", err
+		}
 	}
+
+	return m.output.FormatDetail(
+		&mergeRequestDetail{MergeRequest: mergeRequest, Approvals: approvals, Notes: notes},
+		func() string { return outMergeRequestDetailWithNotes(mergeRequest, approvals, notes) },
+		func() [][]string { return mergeRequestDetailRow(mergeRequest) },
+	)
+}
+
+func outMergeRequestDetailWithNotes(mergeRequest *gitlab.MergeRequest, approvals *gitlab.MergeRequestApprovals, notes []*gitlab.Note) string {
+	res := outMergeRequestDetail(mergeRequest, approvals)
 	noteOutputs := make([]string, len(notes))
 	for i, note := range notes {
 		noteOutputs[i] = noteOutput(note)
 	}
-	res = res + strings.Join(noteOutputs, "\n")
-
-	return res, nil
+	return res + strings.Join(noteOutputs, "\n")
 }
 
 func makeListMergeRequestNotesOptions() *gitlab.ListMergeRequestNotesOptions {
 	return &gitlab.ListMergeRequestNotesOptions{
-		Page:    1,
-		PerPage: 20,
+		Page: 1,
 	}
 }
 
-func outMergeRequestDetail(mergeRequest *gitlab.MergeRequest) string {
+func outMergeRequestDetail(mergeRequest *gitlab.MergeRequest, approvals *gitlab.MergeRequestApprovals) string {
 	base := `%s %s [%s] (created by @%s, %s)
 Assignee: %s
 Milestone: %s
-Labels: %s
+Labels: %s%s
 
 %s`
 
@@ -73,6 +99,12 @@ Labels: %s
 		milestone = mergeRequest.Milestone.Title
 	}
 
+	// Merge requests without approval rules have nothing to show
+	approvalsLine := ""
+	if approvals != nil && (approvals.ApprovalsRequired > 0 || len(approvals.ApprovedBy) > 0) {
+		approvalsLine = "\nApprovals: " + approvalsSummary(approvals)
+	}
+
 	detial := fmt.Sprintf(base,
 		yellow(mergeRequest.IID),
 		cyan(mergeRequest.Title),
@@ -82,11 +114,32 @@ Labels: %s
 		mergeRequest.Assignee.Name,
 		milestone,
 		strings.Join(mergeRequest.Labels, ", "),
+		approvalsLine,
 		internal.SweepMarkdownComment(mergeRequest.Description),
 	)
 	return detial
 }
 
+func mergeRequestDetailRow(mergeRequest *gitlab.MergeRequest) [][]string {
+	milestone := ""
+	if mergeRequest.Milestone != nil {
+		milestone = mergeRequest.Milestone.Title
+	}
+	return [][]string{
+		{
+			strconv.Itoa(mergeRequest.IID),
+			mergeRequest.State,
+			mergeRequest.Author.Username,
+			mergeRequest.Assignee.Username,
+			mergeRequest.SourceBranch,
+			mergeRequest.TargetBranch,
+			milestone,
+			strings.Join(mergeRequest.Labels, ","),
+			mergeRequest.Title,
+		},
+	}
+}
+
 func noteOutput(note *gitlab.Note) string {
 	base := `
 %s (created by @%s, %s)
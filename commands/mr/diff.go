@@ -0,0 +1,299 @@
+package mr
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/lighttiger2505/lab/cmd"
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+)
+
+// statGraphWidth is the largest number of "+" and "-" drawn for a file by
+// the stat output.
+const statGraphWidth = 50
+
+type diffMethod struct {
+	client  api.MergeRequest
+	opt     *DiffOption
+	output  *internal.OutputOption
+	project string
+	id      int
+	paths   []string
+	// pager is the command receiving the diff. The diff is returned when it
+	// is empty
+	pager string
+	spawn func(command, input string) error
+}
+
+// fileChange is the change of a file in a merge request.
+type fileChange struct {
+	OldPath     string `json:"old_path"`
+	NewPath     string `json:"new_path"`
+	AMode       string `json:"a_mode"`
+	BMode       string `json:"b_mode"`
+	Diff        string `json:"diff"`
+	NewFile     bool   `json:"new_file"`
+	RenamedFile bool   `json:"renamed_file"`
+	DeletedFile bool   `json:"deleted_file"`
+}
+
+func (m *diffMethod) Process() (string, error) {
+	mergeRequest, err := m.client.GetMergeRequestChanges(m.id, m.project)
+	if err != nil {
+		return "", err
+	}
+
+	var changes []fileChange
+	for _, change := range mergeRequest.Changes {
+		c := fileChange(change)
+		if matchPaths(&c, m.paths) {
+			changes = append(changes, c)
+		}
+	}
+
+	if m.output.IsMachine() {
+		return m.output.FormatList(changes, func() [][]string {
+			return diffRows(changes)
+		})
+	}
+	if m.opt.NameOnly {
+		return diffNameOutput(changes), nil
+	}
+	if m.opt.Stat {
+		return diffStatOutput(changes), nil
+	}
+	if m.opt.Tool != "" {
+		// Diff tools color the diff by themselves
+		return "", m.spawn(m.opt.Tool, plainDiffOutput(changes))
+	}
+	if m.pager != "" {
+		return "", m.spawn(m.pager, diffOutput(changes))
+	}
+	return diffOutput(changes), nil
+}
+
+// diffPager returns the pager of the diff. The pager is used only when the
+// diff is printed to a terminal, like git.
+func diffPager(opt *DiffOption) string {
+	if opt.NoPager || color.NoColor {
+		return ""
+	}
+	return os.Getenv("PAGER")
+}
+
+func spawnWithInput(command, input string) error {
+	if os.Getenv("LESS") == "" {
+		// Keep the colors and quit when the diff fits in a screen
+		os.Setenv("LESS", "FRX")
+	}
+	if err := cmd.NewBasicCmd(command).SpawnWithInput(input); err != nil {
+		return fmt.Errorf("Failed run %s. %s", command, err.Error())
+	}
+	return nil
+}
+
+// matchPaths reports whether the change is on one of the paths. A path
+// matches the file, the files under the directory or a glob pattern.
+func matchPaths(change *fileChange, paths []string) bool {
+	if len(paths) == 0 {
+		return true
+	}
+	for _, path := range paths {
+		path = strings.TrimSuffix(path, "/")
+		for _, name := range []string{change.OldPath, change.NewPath} {
+			if name == path || strings.HasPrefix(name, path+"/") {
+				return true
+			}
+			if ok, _ := filepath.Match(path, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *fileChange) status() string {
+	switch {
+	case c.NewFile:
+		return "A"
+	case c.DeletedFile:
+		return "D"
+	case c.RenamedFile:
+		return "R"
+	}
+	return "M"
+}
+
+func (c *fileChange) name() string {
+	if c.DeletedFile {
+		return c.OldPath
+	}
+	return c.NewPath
+}
+
+// countLines returns the number of added and deleted lines of the diff.
+func (c *fileChange) countLines() (added, deleted int) {
+	for _, line := range strings.Split(c.Diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+"):
+			added++
+		case strings.HasPrefix(line, "-"):
+			deleted++
+		}
+	}
+	return added, deleted
+}
+
+func diffRows(changes []fileChange) [][]string {
+	var rows [][]string
+	for _, change := range changes {
+		added, deleted := change.countLines()
+		rows = append(rows, []string{
+			change.status(),
+			change.name(),
+			strconv.Itoa(added),
+			strconv.Itoa(deleted),
+		})
+	}
+	return rows
+}
+
+func diffNameOutput(changes []fileChange) string {
+	names := make([]string, len(changes))
+	for i, change := range changes {
+		names[i] = change.name()
+	}
+	return strings.Join(names, "\n")
+}
+
+func diffStatOutput(changes []fileChange) string {
+	if len(changes) == 0 {
+		return ""
+	}
+
+	names := make([]string, len(changes))
+	addedCounts := make([]int, len(changes))
+	deletedCounts := make([]int, len(changes))
+	var nameWidth, maxChanged, totalAdded, totalDeleted int
+	for i, change := range changes {
+		names[i] = change.name()
+		if change.RenamedFile {
+			names[i] = fmt.Sprintf("%s => %s", change.OldPath, change.NewPath)
+		}
+		if len(names[i]) > nameWidth {
+			nameWidth = len(names[i])
+		}
+		addedCounts[i], deletedCounts[i] = change.countLines()
+		if changed := addedCounts[i] + deletedCounts[i]; changed > maxChanged {
+			maxChanged = changed
+		}
+		totalAdded += addedCounts[i]
+		totalDeleted += deletedCounts[i]
+	}
+	countWidth := len(strconv.Itoa(maxChanged))
+
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	lines := make([]string, 0, len(changes)+1)
+	for i := range changes {
+		added, deleted := addedCounts[i], deletedCounts[i]
+		if maxChanged > statGraphWidth {
+			added = added * statGraphWidth / maxChanged
+			deleted = deleted * statGraphWidth / maxChanged
+		}
+		line := fmt.Sprintf(
+			" %-*s | %*d %s%s",
+			nameWidth,
+			names[i],
+			countWidth,
+			addedCounts[i]+deletedCounts[i],
+			green(strings.Repeat("+", added)),
+			red(strings.Repeat("-", deleted)),
+		)
+		lines = append(lines, strings.TrimRight(line, " "))
+	}
+
+	summary := fmt.Sprintf(" %d %s changed", len(changes), plural(len(changes), "file", "files"))
+	if totalAdded > 0 {
+		summary += fmt.Sprintf(", %d %s(+)", totalAdded, plural(totalAdded, "insertion", "insertions"))
+	}
+	if totalDeleted > 0 {
+		summary += fmt.Sprintf(", %d %s(-)", totalDeleted, plural(totalDeleted, "deletion", "deletions"))
+	}
+	lines = append(lines, summary)
+	return strings.Join(lines, "\n")
+}
+
+func plural(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
+
+// diffOutput renders the changes as a unified diff colored like git.
+func diffOutput(changes []fileChange) string {
+	bold := color.New(color.Bold).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+
+	buf := &bytes.Buffer{}
+	for _, change := range changes {
+		header := []string{fmt.Sprintf("diff --git a/%s b/%s", change.OldPath, change.NewPath)}
+		switch {
+		case change.NewFile:
+			header = append(header, "new file mode "+change.BMode)
+		case change.DeletedFile:
+			header = append(header, "deleted file mode "+change.AMode)
+		case change.AMode != change.BMode:
+			header = append(header, "old mode "+change.AMode, "new mode "+change.BMode)
+		}
+		if change.RenamedFile {
+			header = append(header, "rename from "+change.OldPath, "rename to "+change.NewPath)
+		}
+		if change.Diff != "" {
+			oldName, newName := "a/"+change.OldPath, "b/"+change.NewPath
+			if change.NewFile {
+				oldName = "/dev/null"
+			}
+			if change.DeletedFile {
+				newName = "/dev/null"
+			}
+			header = append(header, "--- "+oldName, "+++ "+newName)
+		}
+		for _, line := range header {
+			fmt.Fprintln(buf, bold(line))
+		}
+
+		if change.Diff == "" {
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(change.Diff, "\n"), "\n") {
+			switch {
+			case strings.HasPrefix(line, "@@"):
+				fmt.Fprintln(buf, cyan(line))
+			case strings.HasPrefix(line, "+"):
+				fmt.Fprintln(buf, green(line))
+			case strings.HasPrefix(line, "-"):
+				fmt.Fprintln(buf, red(line))
+			default:
+				fmt.Fprintln(buf, line)
+			}
+		}
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func plainDiffOutput(changes []fileChange) string {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+	return diffOutput(changes)
+}
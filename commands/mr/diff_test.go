@@ -0,0 +1,199 @@
+package mr
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+const mockChanges = `{
+  "iid": 12,
+  "changes": [
+    {
+      "old_path": "main.go",
+      "new_path": "main.go",
+      "a_mode": "100644",
+      "b_mode": "100644",
+      "diff": "@@ -1,2 +1,2 @@\n package main\n-var a = 1\n+var a = 2\n"
+    },
+    {
+      "old_path": "docs/usage.md",
+      "new_path": "docs/usage.md",
+      "a_mode": "0",
+      "b_mode": "100644",
+      "diff": "@@ -0,0 +1,2 @@\n+# Usage\n+lab\n",
+      "new_file": true
+    },
+    {
+      "old_path": "old.go",
+      "new_path": "new.go",
+      "a_mode": "100644",
+      "b_mode": "100644",
+      "diff": "",
+      "renamed_file": true
+    }
+  ]
+}`
+
+func newMockDiffClient(t *testing.T) *api.MockLabMergeRequestClient {
+	return &api.MockLabMergeRequestClient{
+		MockGetMergeRequestChanges: func(pid int, repositoryName string) (*gitlab.MergeRequest, error) {
+			mr := &gitlab.MergeRequest{}
+			if err := json.Unmarshal([]byte(mockChanges), mr); err != nil {
+				t.Fatal(err)
+			}
+			return mr, nil
+		},
+	}
+}
+
+func TestDiffMethod_Process(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	tests := []struct {
+		name  string
+		opt   *DiffOption
+		paths []string
+		want  string
+	}{
+		{
+			name: "diff",
+			opt:  &DiffOption{Diff: true},
+			want: `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1,2 +1,2 @@
+ package main
+-var a = 1
++var a = 2
+diff --git a/docs/usage.md b/docs/usage.md
+new file mode 100644
+--- /dev/null
++++ b/docs/usage.md
+@@ -0,0 +1,2 @@
++# Usage
++lab
+diff --git a/old.go b/new.go
+rename from old.go
+rename to new.go`,
+		},
+		{
+			name: "stat",
+			opt:  &DiffOption{Stat: true},
+			want: ` main.go          | 2 +-
+ docs/usage.md    | 2 ++
+ old.go => new.go | 0
+ 3 files changed, 3 insertions(+), 1 deletion(-)`,
+		},
+		{
+			name: "name only",
+			opt:  &DiffOption{NameOnly: true},
+			want: "main.go\ndocs/usage.md\nnew.go",
+		},
+		{
+			name:  "directory",
+			opt:   &DiffOption{NameOnly: true},
+			paths: []string{"docs/"},
+			want:  "docs/usage.md",
+		},
+		{
+			name:  "pattern",
+			opt:   &DiffOption{NameOnly: true},
+			paths: []string{"*.go"},
+			want:  "main.go\nnew.go",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &diffMethod{
+				client:  newMockDiffClient(t),
+				opt:     tt.opt,
+				output:  &internal.OutputOption{},
+				project: "group/repo",
+				id:      12,
+				paths:   tt.paths,
+			}
+			got, err := m.Process()
+			if err != nil {
+				t.Fatalf("diffMethod.Process() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("diffMethod.Process() = \n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffMethod_ProcessWithCommand(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	defer func() { color.NoColor = noColor }()
+
+	tests := []struct {
+		name        string
+		opt         *DiffOption
+		pager       string
+		wantCommand string
+		wantColor   bool
+	}{
+		{
+			name:        "pager",
+			opt:         &DiffOption{Diff: true},
+			pager:       "less",
+			wantCommand: "less",
+			wantColor:   true,
+		},
+		{
+			name:        "diff tool",
+			opt:         &DiffOption{Tool: "delta"},
+			pager:       "less",
+			wantCommand: "delta",
+			wantColor:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotCommand, gotInput string
+			m := &diffMethod{
+				client:  newMockDiffClient(t),
+				opt:     tt.opt,
+				output:  &internal.OutputOption{},
+				project: "group/repo",
+				id:      12,
+				pager:   tt.pager,
+				spawn: func(command, input string) error {
+					gotCommand, gotInput = command, input
+					return nil
+				},
+			}
+			got, err := m.Process()
+			if err != nil {
+				t.Fatalf("diffMethod.Process() error = %v", err)
+			}
+			if got != "" {
+				t.Errorf("diffMethod.Process() = %q, want empty", got)
+			}
+			if gotCommand != tt.wantCommand {
+				t.Errorf("diffMethod.Process() command = %q, want %q", gotCommand, tt.wantCommand)
+			}
+			if gotColor := containsEscape(gotInput); gotColor != tt.wantColor {
+				t.Errorf("diffMethod.Process() colored = %v, want %v", gotColor, tt.wantColor)
+			}
+		})
+	}
+}
+
+func containsEscape(s string) bool {
+	for _, r := range s {
+		if r == '\x1b' {
+			return true
+		}
+	}
+	return false
+}
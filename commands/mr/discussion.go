@@ -0,0 +1,303 @@
+package mr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// shortIDLength is the length of the discussion ids printed, like the short
+// sha of git. Any unique prefix of an id is accepted.
+const shortIDLength = 8
+
+type discussionListMethod struct {
+	client  api.Discussion
+	opt     *DiscussionOption
+	output  *internal.OutputOption
+	project string
+	id      int
+}
+
+func (m *discussionListMethod) Process() (string, error) {
+	discussions, err := m.client.GetMergeRequestDiscussions(m.project, m.id)
+	if err != nil {
+		return "", err
+	}
+
+	var threads []*gitlab.Discussion
+	for _, discussion := range discussions {
+		if isSystemDiscussion(discussion) {
+			continue
+		}
+		if m.opt.Unresolved && !(isResolvable(discussion) && !isResolved(discussion)) {
+			continue
+		}
+		threads = append(threads, discussion)
+	}
+
+	if m.output.IsMachine() {
+		return m.output.FormatList(threads, func() [][]string {
+			return discussionRows(threads)
+		})
+	}
+
+	outputs := make([]string, len(threads))
+	for i, thread := range threads {
+		outputs[i] = discussionOutput(thread)
+	}
+	return strings.Join(outputs, "\n\n"), nil
+}
+
+func discussionRows(discussions []*gitlab.Discussion) [][]string {
+	rows := make([][]string, len(discussions))
+	for i, discussion := range discussions {
+		first := discussion.Notes[0]
+		rows[i] = []string{
+			shortID(discussion.ID),
+			discussionState(discussion),
+			discussionPosition(first.Position),
+			first.Author.Username,
+			strings.SplitN(first.Body, "\n", 2)[0],
+		}
+	}
+	return rows
+}
+
+type startDiscussionMethod struct {
+	mrClient         api.MergeRequest
+	discussionClient api.Discussion
+	opt              *DiscussionOption
+	message          string
+	project          string
+	id               int
+}
+
+func (m *startDiscussionMethod) Process() (string, error) {
+	if m.message == "" {
+		return "", fmt.Errorf("Please input the comment with the message option")
+	}
+	createOption := &gitlab.CreateMergeRequestDiscussionOptions{
+		Body: gitlab.String(m.message),
+	}
+
+	if m.opt.File != "" {
+		if m.opt.Line == 0 && m.opt.OldLine == 0 {
+			return "", fmt.Errorf("Please input the line to comment on with the line or old-line option")
+		}
+		versions, err := m.mrClient.GetMergeRequestDiffVersions(m.id, m.project)
+		if err != nil {
+			return "", err
+		}
+		if len(versions) == 0 {
+			return "", fmt.Errorf("Merge request !%d has no diff to comment on", m.id)
+		}
+		oldPath, err := diffOldPath(m.mrClient, m.project, m.id, m.opt.File)
+		if err != nil {
+			return "", err
+		}
+		createOption.Position = makeNotePosition(versions[0], oldPath, m.opt)
+	}
+
+	discussion, err := m.discussionClient.CreateMergeRequestDiscussion(m.project, m.id, createOption)
+	if err != nil {
+		return "", err
+	}
+	return shortID(discussion.ID), nil
+}
+
+// diffOldPath returns the path of a file before the merge request, which
+// differs from the path of the renamed files.
+func diffOldPath(client api.MergeRequest, project string, iid int, file string) (string, error) {
+	mergeRequest, err := client.GetMergeRequestChanges(iid, project)
+	if err != nil {
+		return "", err
+	}
+	for _, change := range mergeRequest.Changes {
+		if change.NewPath == file {
+			return change.OldPath, nil
+		}
+	}
+	return "", fmt.Errorf("Not found %s in the diff of merge request !%d", file, iid)
+}
+
+// makeNotePosition returns the position of a line in the latest version of
+// the diff. A line kept by the merge request has both of the line numbers.
+func makeNotePosition(version *gitlab.MergeRequestDiffVersion, oldPath string, opt *DiscussionOption) *gitlab.NotePosition {
+	return &gitlab.NotePosition{
+		BaseSHA:      version.BaseCommitSHA,
+		StartSHA:     version.StartCommitSHA,
+		HeadSHA:      version.HeadCommitSHA,
+		PositionType: "text",
+		NewPath:      opt.File,
+		NewLine:      opt.Line,
+		OldPath:      oldPath,
+		OldLine:      opt.OldLine,
+	}
+}
+
+type replyMethod struct {
+	client     api.Discussion
+	message    string
+	project    string
+	id         int
+	discussion string
+}
+
+func (m *replyMethod) Process() (string, error) {
+	if m.message == "" {
+		return "", fmt.Errorf("Please input the reply with the message option")
+	}
+	discussionID, err := findDiscussionID(m.client, m.project, m.id, m.discussion)
+	if err != nil {
+		return "", err
+	}
+
+	replyOption := &gitlab.AddMergeRequestDiscussionNoteOptions{
+		Body: gitlab.String(m.message),
+	}
+	note, err := m.client.AddMergeRequestDiscussionNote(m.project, m.id, discussionID, replyOption)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d", note.ID), nil
+}
+
+type resolveMethod struct {
+	client     api.Discussion
+	resolved   bool
+	project    string
+	id         int
+	discussion string
+}
+
+func (m *resolveMethod) Process() (string, error) {
+	discussionID, err := findDiscussionID(m.client, m.project, m.id, m.discussion)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := m.client.ResolveMergeRequestDiscussion(m.project, m.id, discussionID, m.resolved); err != nil {
+		return "", err
+	}
+	if m.resolved {
+		return fmt.Sprintf("Resolved discussion %s", shortID(discussionID)), nil
+	}
+	return fmt.Sprintf("Unresolved discussion %s", shortID(discussionID)), nil
+}
+
+// findDiscussionID returns the full id of the discussion starting with
+// prefix.
+func findDiscussionID(client api.Discussion, project string, iid int, prefix string) (string, error) {
+	discussions, err := client.GetMergeRequestDiscussions(project, iid)
+	if err != nil {
+		return "", err
+	}
+
+	var found []string
+	for _, discussion := range discussions {
+		if strings.HasPrefix(discussion.ID, prefix) {
+			found = append(found, discussion.ID)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("Not found discussion %s in merge request !%d", prefix, iid)
+	case 1:
+		return found[0], nil
+	}
+	return "", fmt.Errorf("Discussion %s is ambiguous, please input a longer id", prefix)
+}
+
+func shortID(id string) string {
+	if len(id) > shortIDLength {
+		return id[:shortIDLength]
+	}
+	return id
+}
+
+// isSystemDiscussion reports whether the discussion only has the notes made
+// by GitLab, like "added 1 commit".
+func isSystemDiscussion(discussion *gitlab.Discussion) bool {
+	for _, note := range discussion.Notes {
+		if !note.System {
+			return false
+		}
+	}
+	return true
+}
+
+func isResolvable(discussion *gitlab.Discussion) bool {
+	for _, note := range discussion.Notes {
+		if note.Resolvable {
+			return true
+		}
+	}
+	return false
+}
+
+func isResolved(discussion *gitlab.Discussion) bool {
+	for _, note := range discussion.Notes {
+		if note.Resolvable && !note.Resolved {
+			return false
+		}
+	}
+	return true
+}
+
+func discussionState(discussion *gitlab.Discussion) string {
+	if !isResolvable(discussion) {
+		return ""
+	}
+	if isResolved(discussion) {
+		return "resolved"
+	}
+	return "unresolved"
+}
+
+// discussionPosition returns the file and line of a diff comment, e.g.
+// "main.go:42".
+func discussionPosition(position *gitlab.NotePosition) string {
+	if position == nil {
+		return ""
+	}
+	if position.NewLine > 0 {
+		return fmt.Sprintf("%s:%d", position.NewPath, position.NewLine)
+	}
+	if position.OldLine > 0 {
+		return fmt.Sprintf("%s:%d", position.OldPath, position.OldLine)
+	}
+	return position.NewPath
+}
+
+func discussionOutput(discussion *gitlab.Discussion) string {
+	yellow := color.New(color.FgYellow).SprintFunc()
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+
+	header := []string{yellow(shortID(discussion.ID))}
+	if position := discussionPosition(discussion.Notes[0].Position); position != "" {
+		header = append(header, position)
+	}
+	switch state := discussionState(discussion); state {
+	case "resolved":
+		header = append(header, green("["+state+"]"))
+	case "unresolved":
+		header = append(header, red("["+state+"]"))
+	}
+
+	lines := []string{strings.Join(header, " ")}
+	for _, note := range discussion.Notes {
+		if note.System {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("  @%s, %s", note.Author.Username, note.CreatedAt.String()))
+		for _, line := range strings.Split(internal.SweepMarkdownComment(note.Body), "\n") {
+			lines = append(lines, "    "+line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
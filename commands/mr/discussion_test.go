@@ -0,0 +1,252 @@
+package mr
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+const mockDiscussions = `[
+  {
+    "id": "a1b2c3d4e5f6",
+    "notes": [
+      {
+        "id": 1,
+        "body": "Should be a constant",
+        "author": {"username": "alice"},
+        "created_at": "2018-02-14T00:00:00Z",
+        "position": {"new_path": "main.go", "new_line": 42},
+        "resolvable": true,
+        "resolved": false
+      },
+      {
+        "id": 2,
+        "body": "Why?",
+        "author": {"username": "bob"},
+        "created_at": "2018-02-15T00:00:00Z",
+        "resolvable": true,
+        "resolved": false
+      }
+    ]
+  },
+  {
+    "id": "a1ffffffffff",
+    "individual_note": true,
+    "notes": [
+      {
+        "id": 3,
+        "body": "added 1 commit",
+        "author": {"username": "alice"},
+        "created_at": "2018-02-16T00:00:00Z",
+        "system": true
+      }
+    ]
+  },
+  {
+    "id": "b9c8d7e6f5a4",
+    "notes": [
+      {
+        "id": 4,
+        "body": "LGTM",
+        "author": {"username": "carol"},
+        "created_at": "2018-02-17T00:00:00Z",
+        "resolvable": true,
+        "resolved": true
+      }
+    ]
+  }
+]`
+
+func newMockDiscussionClient(t *testing.T) *api.MockDiscussionClient {
+	return &api.MockDiscussionClient{
+		MockGetMergeRequestDiscussions: func(repositoryName string, iid int) ([]*gitlab.Discussion, error) {
+			var discussions []*gitlab.Discussion
+			if err := json.Unmarshal([]byte(mockDiscussions), &discussions); err != nil {
+				t.Fatal(err)
+			}
+			return discussions, nil
+		},
+	}
+}
+
+func TestDiscussionListMethod_Process(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	tests := []struct {
+		name   string
+		opt    *DiscussionOption
+		output *internal.OutputOption
+		want   string
+	}{
+		{
+			name:   "threads",
+			opt:    &DiscussionOption{},
+			output: &internal.OutputOption{},
+			want: `a1b2c3d4 main.go:42 [unresolved]
+  @alice, 2018-02-14 00:00:00 +0000 UTC
+    Should be a constant
+  @bob, 2018-02-15 00:00:00 +0000 UTC
+    Why?
+
+b9c8d7e6 [resolved]
+  @carol, 2018-02-17 00:00:00 +0000 UTC
+    LGTM`,
+		},
+		{
+			name:   "unresolved",
+			opt:    &DiscussionOption{Unresolved: true},
+			output: &internal.OutputOption{Output: "tsv"},
+			want:   "a1b2c3d4\tunresolved\tmain.go:42\talice\tShould be a constant",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &discussionListMethod{
+				client:  newMockDiscussionClient(t),
+				opt:     tt.opt,
+				output:  tt.output,
+				project: "group/repo",
+				id:      12,
+			}
+			got, err := m.Process()
+			if err != nil {
+				t.Fatalf("discussionListMethod.Process() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("discussionListMethod.Process() = \n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+const mockDiscussionChanges = `{
+  "changes": [
+    {"old_path": "main.go", "new_path": "main.go"},
+    {"old_path": "util.go", "new_path": "internal/util.go", "renamed_file": true}
+  ]
+}`
+
+func TestStartDiscussionMethod_Process(t *testing.T) {
+	tests := []struct {
+		name    string
+		opt     *DiscussionOption
+		want    *gitlab.NotePosition
+		wantErr bool
+	}{
+		{
+			name: "new line",
+			opt:  &DiscussionOption{File: "main.go", Line: 42},
+			want: &gitlab.NotePosition{
+				BaseSHA:      "base",
+				StartSHA:     "start",
+				HeadSHA:      "head",
+				PositionType: "text",
+				NewPath:      "main.go",
+				NewLine:      42,
+				OldPath:      "main.go",
+			},
+		},
+		{
+			name: "renamed file",
+			opt:  &DiscussionOption{File: "internal/util.go", Line: 12, OldLine: 10},
+			want: &gitlab.NotePosition{
+				BaseSHA:      "base",
+				StartSHA:     "start",
+				HeadSHA:      "head",
+				PositionType: "text",
+				NewPath:      "internal/util.go",
+				NewLine:      12,
+				OldPath:      "util.go",
+				OldLine:      10,
+			},
+		},
+		{
+			name:    "not in the diff",
+			opt:     &DiscussionOption{File: "README.md", Line: 1},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *gitlab.CreateMergeRequestDiscussionOptions
+			m := &startDiscussionMethod{
+				mrClient: &api.MockLabMergeRequestClient{
+					MockGetMergeRequestDiffVersions: func(pid int, repositoryName string) ([]*gitlab.MergeRequestDiffVersion, error) {
+						return []*gitlab.MergeRequestDiffVersion{
+							&gitlab.MergeRequestDiffVersion{BaseCommitSHA: "base", StartCommitSHA: "start", HeadCommitSHA: "head"},
+							&gitlab.MergeRequestDiffVersion{BaseCommitSHA: "base", StartCommitSHA: "start", HeadCommitSHA: "old"},
+						}, nil
+					},
+					MockGetMergeRequestChanges: func(pid int, repositoryName string) (*gitlab.MergeRequest, error) {
+						mr := &gitlab.MergeRequest{}
+						if err := json.Unmarshal([]byte(mockDiscussionChanges), mr); err != nil {
+							return nil, err
+						}
+						return mr, nil
+					},
+				},
+				discussionClient: &api.MockDiscussionClient{
+					MockCreateMergeRequestDiscussion: func(repositoryName string, iid int, opt *gitlab.CreateMergeRequestDiscussionOptions) (*gitlab.Discussion, error) {
+						got = opt
+						return &gitlab.Discussion{ID: "0123456789abcdef"}, nil
+					},
+				},
+				opt:     tt.opt,
+				message: "Should be a constant",
+				project: "group/repo",
+				id:      12,
+			}
+
+			res, err := m.Process()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("startDiscussionMethod.Process() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if got != nil {
+					t.Errorf("startDiscussionMethod.Process() created a discussion")
+				}
+				return
+			}
+			if res != "01234567" {
+				t.Errorf("startDiscussionMethod.Process() = %q, want %q", res, "01234567")
+			}
+			if !reflect.DeepEqual(got.Position, tt.want) {
+				t.Errorf("startDiscussionMethod.Process() position = %#v, want %#v", got.Position, tt.want)
+			}
+			if *got.Body != "Should be a constant" {
+				t.Errorf("startDiscussionMethod.Process() body = %q", *got.Body)
+			}
+		})
+	}
+}
+
+func Test_findDiscussionID(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefix  string
+		want    string
+		wantErr bool
+	}{
+		{name: "unique", prefix: "b9c8", want: "b9c8d7e6f5a4"},
+		{name: "ambiguous", prefix: "a1", wantErr: true},
+		{name: "not found", prefix: "ff", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := findDiscussionID(newMockDiscussionClient(t), "group/repo", 12, tt.prefix)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("findDiscussionID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("findDiscussionID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
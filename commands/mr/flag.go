@@ -3,6 +3,8 @@ package mr
 import (
 	flags "github.com/jessevdk/go-flags"
 	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
 )
 
 type Option struct {
@@ -10,19 +12,29 @@ type Option struct {
 	CreateUpdateOption   *CreateUpdateOption            `group:"Create, Update Options"`
 	ListOption           *ListOption                    `group:"List Options"`
 	ShowOption           *ShowOption                    `group:"Show Options"`
+	CheckoutOption       *CheckoutOption                `group:"Checkout Options"`
+	DiffOption           *DiffOption                    `group:"Diff Options"`
+	MergeOption          *MergeOption                   `group:"Merge, Approve Options"`
+	DiscussionOption     *DiscussionOption              `group:"Discussion Options"`
+	CommentOption        *internal.CommentOption        `group:"Comment Options"`
 	BrowseOption         *internal.BrowseOption         `group:"Browse Options"`
+	OutputOption         *internal.OutputOption         `group:"Output Options"`
+	CacheOption          *internal.CacheOption          `group:"Cache Options"`
 }
 
 type CreateUpdateOption struct {
-	Edit         bool   `short:"e" long:"edit" description:"Edit the merge request on editor. Start the editor with the contents in the given title and message options."`
-	Title        string `short:"i" long:"title" value-name:"<title>" description:"The title of an merge request"`
-	Message      string `short:"m" long:"message" value-name:"<message>" description:"The message of an merge request"`
-	Template     string `short:"p" long:"template" value-name:"<merge request template>" description:"Start the editor with file using merge request template"`
-	SourceBranch string `long:"source" value-name:"<source branch>" description:"The source branch"`
-	TargetBranch string `long:"target" value-name:"<target branch>" default:"master" default-mask:"master" description:"The target branch"`
-	StateEvent   string `long:"state-event" value-name:"<state>" description:"Change the status. \"opened\", \"closed\""`
-	AssigneeID   int    `long:"cu-assignee-id" value-name:"<assignee id>" description:"The ID of the user to assign the merge request to."`
-	MilestoneID  int    `long:"cu-milestone-id" value-name:"<milestone id>" description:"The global ID of a milestone to assign the merge request to. "`
+	Edit         bool     `short:"e" long:"edit" description:"Edit the merge request on editor. Start the editor with the contents in the given title and message options."`
+	Title        string   `short:"i" long:"title" value-name:"<title>" description:"The title of an merge request"`
+	Message      string   `short:"m" long:"message" value-name:"<message>" description:"The message of an merge request"`
+	Template     string   `short:"p" long:"template" value-name:"<merge request template>" description:"Start the editor with file using merge request template"`
+	SourceBranch string   `long:"source" value-name:"<source branch>" description:"The source branch"`
+	TargetBranch string   `long:"target" value-name:"<target branch>" default:"master" default-mask:"master" description:"The target branch"`
+	StateEvent   string   `long:"state-event" value-name:"<state>" description:"Change the status. \"opened\", \"closed\""`
+	AssigneeID   int      `long:"cu-assignee-id" value-name:"<assignee id>" description:"The ID of the user to assign the merge request to."`
+	MilestoneID  int      `long:"cu-milestone-id" value-name:"<milestone id>" description:"The global ID of a milestone to assign the merge request to. "`
+	Labels       []string `long:"cu-label" value-name:"<label>" description:"Set the labels of the merge request. Repeat or separate by comma to give multiple labels."`
+	AddLabels    []string `long:"add-label" value-name:"<label>" description:"Add the labels to the merge request."`
+	RemoveLabels []string `long:"remove-label" value-name:"<label>" description:"Remove the labels from the merge request."`
 }
 
 func (o *CreateUpdateOption) hasEdit() bool {
@@ -35,7 +47,9 @@ func (o *CreateUpdateOption) hasEdit() bool {
 func (o *CreateUpdateOption) hasCreate() bool {
 	if o.Title != "" ||
 		o.AssigneeID != 0 ||
-		o.MilestoneID != 0 {
+		o.MilestoneID != 0 ||
+		len(o.Labels) > 0 ||
+		len(o.AddLabels) > 0 {
 		return true
 	}
 	return false
@@ -46,28 +60,50 @@ func (o *CreateUpdateOption) hasUpdate() bool {
 		o.Message != "" ||
 		o.StateEvent != "" ||
 		o.AssigneeID != 0 ||
-		o.MilestoneID != 0 {
+		o.MilestoneID != 0 ||
+		o.hasLabel() {
 		return true
 	}
 	return false
 }
 
+func (o *CreateUpdateOption) hasLabel() bool {
+	if len(o.Labels) > 0 ||
+		len(o.AddLabels) > 0 ||
+		len(o.RemoveLabels) > 0 {
+		return true
+	}
+	return false
+}
+
+// getLabels returns the labels of the merge request changed by the label
+// options.
+func (o *CreateUpdateOption) getLabels(labels []string) []string {
+	if len(o.Labels) > 0 {
+		labels = internal.SplitLabels(o.Labels)
+	}
+	return internal.ChangeLabels(labels, internal.SplitLabels(o.AddLabels), internal.SplitLabels(o.RemoveLabels))
+}
+
 type ListOption struct {
-	Num        int    `short:"n" long:"num" value-name:"<num>" default:"20" default-mask:"20" description:"Limit the number of merge request to output."`
-	State      string `long:"state" value-name:"<state>" default:"all" default-mask:"all" description:"Print only merge request of the state just those that are \"opened\", \"closed\", \"merged\" or \"all\""`
-	Scope      string `long:"scope" value-name:"<scope>" default:"all" default-mask:"all" description:"Print only given scope. \"created-by-me\", \"assigned-to-me\" or \"all\"."`
-	OrderBy    string `long:"orderby" value-name:"<orderby>" default:"updated_at" default-mask:"updated_at" description:"Print merge request ordered by \"created_at\" or \"updated_at\" fields."`
-	Sort       string `long:"sort"  value-name:"<sort>" default:"desc" default-mask:"desc" description:"Print merge request ordered in \"asc\" or \"desc\" order."`
-	Search     string `short:"s" long:"search"  value-name:"<search word>" description:"Search merge request against their title and description."`
-	Milestone  string `long:"milestone"  value-name:"<milestone>" description:"Print merge requests for a specific milestone. "`
-	AuthorID   int    `long:"author-id"  value-name:"<auther id>" description:"Print merge requests created by the given user id"`
-	AssigneeID int    `long:"assignee-id"  value-name:"<assignee id>" description:"Print merge requests assigned to the given user id."`
-	Opened     bool   `short:"O" long:"opened" description:"Shorthand of the state option for \"--state=opened\"."`
-	Closed     bool   `short:"C" long:"closed" description:"Shorthand of the state option for \"--state=closed\"."`
-	Merged     bool   `short:"g" long:"merged" description:"Shorthand of the state option for \"--state=merged\"."`
-	CreatedMe  bool   `short:"r" long:"created-me" description:"Shorthand of the scope option for \"--scope=created-by-me\"."`
-	AssignedMe bool   `short:"a" long:"assigned-me" description:"Shorthand of the scope option for \"--scope=assigned-by-me\"."`
-	AllProject bool   `short:"A" long:"all-project" description:"Print the merge request of all projects"`
+	Num        int      `short:"n" long:"num" value-name:"<num>" default:"20" default-mask:"20" description:"Limit the number of merge request to output."`
+	All        bool     `long:"all" description:"Print all merge requests, ignore the num option."`
+	State      string   `long:"state" value-name:"<state>" default:"all" default-mask:"all" description:"Print only merge request of the state just those that are \"opened\", \"closed\", \"merged\" or \"all\""`
+	Scope      string   `long:"scope" value-name:"<scope>" default:"all" default-mask:"all" description:"Print only given scope. \"created-by-me\", \"assigned-to-me\" or \"all\"."`
+	OrderBy    string   `long:"orderby" value-name:"<orderby>" default:"updated_at" default-mask:"updated_at" description:"Print merge request ordered by \"created_at\" or \"updated_at\" fields."`
+	Sort       string   `long:"sort"  value-name:"<sort>" default:"desc" default-mask:"desc" description:"Print merge request ordered in \"asc\" or \"desc\" order."`
+	Search     string   `short:"s" long:"search"  value-name:"<search word>" description:"Search merge request against their title and description."`
+	Milestone  string   `long:"milestone"  value-name:"<milestone>" description:"Print merge requests for a specific milestone. "`
+	AuthorID   int      `long:"author-id"  value-name:"<auther id>" description:"Print merge requests created by the given user id"`
+	AssigneeID int      `long:"assignee-id"  value-name:"<assignee id>" description:"Print merge requests assigned to the given user id."`
+	Labels     []string `long:"label" value-name:"<label>" description:"Print merge requests having all of the labels."`
+	NotLabels  []string `long:"not-label" value-name:"<label>" description:"Print merge requests having none of the labels."`
+	Opened     bool     `short:"O" long:"opened" description:"Shorthand of the state option for \"--state=opened\"."`
+	Closed     bool     `short:"C" long:"closed" description:"Shorthand of the state option for \"--state=closed\"."`
+	Merged     bool     `short:"g" long:"merged" description:"Shorthand of the state option for \"--state=merged\"."`
+	CreatedMe  bool     `short:"r" long:"created-me" description:"Shorthand of the scope option for \"--scope=created-by-me\"."`
+	AssignedMe bool     `short:"a" long:"assigned-me" description:"Shorthand of the scope option for \"--scope=assigned-by-me\"."`
+	AllProject bool     `short:"A" long:"all-project" description:"Print the merge request of all projects"`
 }
 
 func (l *ListOption) getState() string {
@@ -93,10 +129,59 @@ func (l *ListOption) getScope() string {
 	return l.Scope
 }
 
+// listOptionFuncs returns the filters which the list options of go-gitlab
+// can not give.
+func (l *ListOption) listOptionFuncs() []gitlab.OptionFunc {
+	var options []gitlab.OptionFunc
+	if labels := internal.SplitLabels(l.NotLabels); len(labels) > 0 {
+		options = append(options, api.WithNotLabels(labels))
+	}
+	return options
+}
+
 type ShowOption struct {
 	NoComment bool `long:"no-comment" description:"Not print a list of comments for a spcific merge request."`
 }
 
+type CheckoutOption struct {
+	Detach bool   `long:"detach" description:"Check out the merge request on a detached HEAD."`
+	Branch string `long:"branch" value-name:"<branch>" description:"The name of the local branch. The source branch of the merge request by default."`
+}
+
+type DiffOption struct {
+	Diff     bool   `long:"diff" description:"Print the changes of the merge request. The paths given after the merge request id limit the changed files."`
+	Stat     bool   `long:"stat" description:"Print the number of changed lines of each file instead of the diff."`
+	NameOnly bool   `long:"name-only" description:"Print only the names of the changed files."`
+	Tool     string `long:"diff-tool" value-name:"<command>" description:"Pipe the diff without colors into the given command. e.g. \"delta\", \"diff-so-fancy\""`
+	NoPager  bool   `long:"no-pager" description:"Not pipe the diff into $PAGER."`
+}
+
+func (o *DiffOption) hasDiff() bool {
+	if o.Diff || o.Stat || o.NameOnly || o.Tool != "" {
+		return true
+	}
+	return false
+}
+
+type MergeOption struct {
+	Merge                bool   `long:"merge" description:"Merge the merge request."`
+	Squash               bool   `long:"squash" description:"Squash the commits of the merge request into a single commit."`
+	RemoveSourceBranch   bool   `long:"remove-source-branch" description:"Remove the source branch after the merge."`
+	WhenPipelineSucceeds bool   `long:"when-pipeline-succeeds" description:"Merge the merge request when the pipeline succeeds."`
+	SHA                  string `long:"sha" value-name:"<sha>" description:"Merge or approve only when the head of the source branch is the given commit."`
+	MergeCommitMessage   string `long:"merge-commit-message" value-name:"<message>" description:"The message of the merge commit."`
+	SquashCommitMessage  string `long:"squash-commit-message" value-name:"<message>" description:"The message of the squashed commit."`
+	Approve              bool   `long:"approve" description:"Approve the merge request."`
+	Unapprove            bool   `long:"unapprove" description:"Remove your approval of the merge request."`
+}
+
+type DiscussionOption struct {
+	File       string `long:"file" value-name:"<path>" description:"The file of the diff to comment on, by its new path when it is renamed."`
+	Line       int    `long:"line" value-name:"<line>" description:"The line of the new file to comment on."`
+	OldLine    int    `long:"old-line" value-name:"<line>" description:"The line of the old file to comment on. Give both of the lines for an unchanged line."`
+	Unresolved bool   `long:"unresolved" description:"Print only unresolved discussions."`
+}
+
 type BrowseOption struct {
 	Browse bool `short:"b" long:"browse" description:"Browse merge request."`
 }
@@ -105,7 +190,14 @@ func newOptionParser(opt *Option) *flags.Parser {
 	opt.ProjectProfileOption = &internal.ProjectProfileOption{}
 	opt.CreateUpdateOption = &CreateUpdateOption{}
 	opt.ListOption = &ListOption{}
+	opt.CheckoutOption = &CheckoutOption{}
+	opt.DiffOption = &DiffOption{}
+	opt.MergeOption = &MergeOption{}
+	opt.DiscussionOption = &DiscussionOption{}
+	opt.CommentOption = &internal.CommentOption{}
 	opt.BrowseOption = &internal.BrowseOption{}
+	opt.OutputOption = &internal.OutputOption{}
+	opt.CacheOption = &internal.CacheOption{}
 	parser := flags.NewParser(opt, flags.HelpFlag|flags.PassDoubleDash)
 	parser.Usage = `merge-request - Create and Edit, List, Browse a merge request
 
@@ -113,19 +205,52 @@ Synopsis:
   # List merge request
   lab merge-request [-n <num>] [--state=<state> | -o | -c] [--scope=<scope> | -r | -a] [-s <search word>]
                     [--milestone=<milestone>] [--author-id=<author id>] [--assignee-id=<assignee id>]
-                    [--orderby <orderby>] [--sort <sort>] [-A]
+                    [--label=<label>...] [--not-label=<label>...]
+                    [--orderby <orderby>] [--sort <sort>] [-A] [--output=<format>]
 
   # Create merge request
   lab merge-request -e | -i <title> [-m <message>] 
                     [--cu-assignee-id=<assignee id>] [--cu-milestone-id=<milestone id>]
+                    [--cu-label=<label>...]
 
   # Update merge request
   lab merge-request <merge request id> [-e] [-i <title>] [-m <message>] 
                                        [--state-event=<state>]
                                        [--cu-assignee-id=<assignee id>] [--cu-milestone-id=<milestone id>]
+                                       [--cu-label=<label>...] [--add-label=<label>...] [--remove-label=<label>...]
 
   # Show merge request
-  lab merge-request <merge request id> [--no-comment]
+  lab merge-request <merge request id> [--no-comment] [--output=<format>]
+
+  # Show changes of merge request
+  lab merge-request <merge request id> --diff | --stat | --name-only [--diff-tool=<command>] [--no-pager]
+                                       [--] [<path>...]
+
+  # Add, edit or delete comment of merge request
+  lab merge-request <merge request id> --comment [-e | -m <message>] [--quote=<note id>]
+  lab merge-request <merge request id> --edit-note=<note id> [-m <message>]
+  lab merge-request <merge request id> --delete-note=<note id>
+
+  # Merge merge request
+  lab merge-request <merge request id> --merge [--squash] [--remove-source-branch] [--when-pipeline-succeeds]
+                                       [--sha=<sha>] [--merge-commit-message=<message>]
+                                       [--squash-commit-message=<message>]
+
+  # Approve or unapprove merge request
+  lab merge-request <merge request id> --approve [--sha=<sha>] | --unapprove
+
+  # List discussions of merge request
+  lab merge-request <merge request id> discussions [--unresolved] [--output=<format>]
+
+  # Comment on merge request, or on a line of the diff
+  lab merge-request <merge request id> comment -m <message> [--file=<path> --line=<line> [--old-line=<line>]]
+
+  # Reply to, resolve or unresolve discussion
+  lab merge-request <merge request id> reply <discussion id> -m <message>
+  lab merge-request <merge request id> resolve | unresolve <discussion id>
+
+  # Check out merge request
+  lab merge-request checkout <merge request id> [--detach | --branch=<branch>]
 
   # Browse merge request
   lab merge-request -b [<merge request id>]`
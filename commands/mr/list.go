@@ -6,7 +6,6 @@ import (
 	"github.com/fatih/color"
 	"github.com/lighttiger2505/lab/commands/internal"
 	"github.com/lighttiger2505/lab/internal/api"
-	"github.com/ryanuber/columnize"
 	gitlab "github.com/xanzy/go-gitlab"
 )
 
@@ -14,45 +13,58 @@ type listMethod struct {
 	internal.Method
 	client  api.MergeRequest
 	opt     *ListOption
+	output  *internal.OutputOption
 	project string
 }
 
 func (m *listMethod) Process() (string, error) {
-	mergeRequests, err := m.client.GetProjectMargeRequest(
+	w := m.output.NewListWriter()
+	err := m.client.GetProjectMargeRequest(
 		makeProjectMergeRequestOption(m.opt),
 		m.project,
+		internal.Limit(m.opt.Num, m.opt.All),
+		func(mergeRequests []*gitlab.MergeRequest) error {
+			return w.WritePage(mergeRequests, func() [][]string {
+				return outProjectMergeRequest(mergeRequests)
+			})
+		},
+		m.opt.listOptionFuncs()...,
 	)
 	if err != nil {
-		return "", nil
+		return "", err
 	}
-	outputs := outProjectMergeRequest(mergeRequests)
-	return columnize.SimpleFormat(outputs), nil
+	return w.Flush()
 }
 
 type listAllMethod struct {
 	internal.Method
 	client api.MergeRequest
 	opt    *ListOption
+	output *internal.OutputOption
 }
 
 func (m *listAllMethod) Process() (string, error) {
-	// Do get merge request list
-	mergeRequests, err := m.client.GetAllProjectMergeRequest(
+	// Print merge request list page by page
+	w := m.output.NewListWriter()
+	err := m.client.GetAllProjectMergeRequest(
 		makeMergeRequestOption(m.opt),
+		internal.Limit(m.opt.Num, m.opt.All),
+		func(mergeRequests []*gitlab.MergeRequest) error {
+			return w.WritePage(mergeRequests, func() [][]string {
+				return outMergeRequest(mergeRequests)
+			})
+		},
+		m.opt.listOptionFuncs()...,
 	)
 	if err != nil {
-		return "", nil
+		return "", err
 	}
-
-	// Print merge request list
-	outputs := outMergeRequest(mergeRequests)
-	return columnize.SimpleFormat(outputs), nil
+	return w.Flush()
 }
 
 func makeMergeRequestOption(listMergeRequestsOption *ListOption) *gitlab.ListMergeRequestsOptions {
 	listOption := &gitlab.ListOptions{
-		Page:    1,
-		PerPage: listMergeRequestsOption.Num,
+		Page: 1,
 	}
 
 	listRequestsOptions := &gitlab.ListMergeRequestsOptions{
@@ -73,13 +85,16 @@ func makeMergeRequestOption(listMergeRequestsOption *ListOption) *gitlab.ListMer
 	if listMergeRequestsOption.AssigneeID != 0 {
 		listRequestsOptions.AssigneeID = gitlab.Int(listMergeRequestsOption.AssigneeID)
 	}
+	if labels := internal.SplitLabels(listMergeRequestsOption.Labels); len(labels) > 0 {
+		// The labels of merge requests are not sent separated by comma
+		listRequestsOptions.Labels = gitlab.Labels{strings.Join(labels, ",")}
+	}
 	return listRequestsOptions
 }
 
 func makeProjectMergeRequestOption(listMergeRequestsOption *ListOption) *gitlab.ListProjectMergeRequestsOptions {
 	listOption := &gitlab.ListOptions{
-		Page:    1,
-		PerPage: listMergeRequestsOption.Num,
+		Page: 1,
 	}
 	listMergeRequestsOptions := &gitlab.ListProjectMergeRequestsOptions{
 		State:       gitlab.String(listMergeRequestsOption.getState()),
@@ -99,32 +114,36 @@ func makeProjectMergeRequestOption(listMergeRequestsOption *ListOption) *gitlab.
 	if listMergeRequestsOption.AssigneeID != 0 {
 		listMergeRequestsOptions.AssigneeID = gitlab.Int(listMergeRequestsOption.AssigneeID)
 	}
+	if labels := internal.SplitLabels(listMergeRequestsOption.Labels); len(labels) > 0 {
+		// The labels of merge requests are not sent separated by comma
+		listMergeRequestsOptions.Labels = gitlab.Labels{strings.Join(labels, ",")}
+	}
 	return listMergeRequestsOptions
 }
 
-func outProjectMergeRequest(mergeRequsets []*gitlab.MergeRequest) []string {
+func outProjectMergeRequest(mergeRequsets []*gitlab.MergeRequest) [][]string {
 	yellow := color.New(color.FgYellow).SprintFunc()
-	outputs := []string{}
+	outputs := [][]string{}
 	for _, mergeRequest := range mergeRequsets {
-		output := strings.Join([]string{
+		output := []string{
 			yellow(mergeRequest.IID),
 			mergeRequest.Title,
-		}, "|")
+		}
 		outputs = append(outputs, output)
 	}
 	return outputs
 }
 
-func outMergeRequest(mergeRequsets []*gitlab.MergeRequest) []string {
+func outMergeRequest(mergeRequsets []*gitlab.MergeRequest) [][]string {
 	cyan := color.New(color.FgCyan).SprintFunc()
 	yellow := color.New(color.FgYellow).SprintFunc()
-	outputs := []string{}
+	outputs := [][]string{}
 	for _, mergeRequest := range mergeRequsets {
-		output := strings.Join([]string{
+		output := []string{
 			cyan(internal.ParceRepositoryFullName(mergeRequest.WebURL)),
 			yellow(mergeRequest.IID),
 			mergeRequest.Title,
-		}, "|")
+		}
 		outputs = append(outputs, output)
 	}
 	return outputs
@@ -0,0 +1,144 @@
+package mr
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+type mergeMethod struct {
+	client  api.MergeRequest
+	opt     *MergeOption
+	project string
+	id      int
+}
+
+func (m *mergeMethod) Process() (string, error) {
+	mergeRequest, err := m.client.AcceptMergeRequest(makeAcceptMergeRequestOptions(m.opt), m.id, m.project)
+	if err != nil {
+		return "", m.mergeError(err)
+	}
+
+	if mergeRequest.State != "merged" && mergeRequest.MergeWhenPipelineSucceeds {
+		return fmt.Sprintf("Merge request !%d will be merged when the pipeline succeeds", m.id), nil
+	}
+	return fmt.Sprintf("Merged merge request !%d", m.id), nil
+}
+
+// mergeError maps the reasons why GitLab refuses the merge to exit codes.
+func (m *mergeMethod) mergeError(err error) error {
+	switch api.StatusCode(err) {
+	case http.StatusMethodNotAllowed:
+		// GitLab answers 405 for every merge request which can not be merged
+		// yet, the pipeline tells whether to wait for it
+		mergeRequest, getErr := m.client.GetMergeRequest(m.id, m.project)
+		if getErr == nil && isPipelineRunning(mergeRequest) {
+			return &internal.ExitError{
+				Code: ExitCodePipelineNotFinished,
+				Err:  fmt.Errorf("The pipeline of merge request !%d is not finished. Use --when-pipeline-succeeds to merge it after the pipeline", m.id),
+			}
+		}
+		return &internal.ExitError{Code: ExitCodeNotMergeable, Err: err}
+	case http.StatusNotAcceptable:
+		return &internal.ExitError{Code: ExitCodeNotMergeable, Err: err}
+	case http.StatusConflict:
+		return &internal.ExitError{
+			Code: ExitCodeSHAMismatch,
+			Err:  fmt.Errorf("The source branch of merge request !%d is not at %s", m.id, m.opt.SHA),
+		}
+	}
+	return err
+}
+
+func isPipelineRunning(mergeRequest *gitlab.MergeRequest) bool {
+	switch mergeRequest.Pipeline.Status {
+	case "created", "pending", "running":
+		return true
+	}
+	return false
+}
+
+func makeAcceptMergeRequestOptions(opt *MergeOption) *api.AcceptMergeRequestOptions {
+	acceptOption := &api.AcceptMergeRequestOptions{}
+	if opt.MergeCommitMessage != "" {
+		acceptOption.MergeCommitMessage = gitlab.String(opt.MergeCommitMessage)
+	}
+	if opt.RemoveSourceBranch {
+		acceptOption.ShouldRemoveSourceBranch = gitlab.Bool(true)
+	}
+	if opt.WhenPipelineSucceeds {
+		acceptOption.MergeWhenPipelineSucceeds = gitlab.Bool(true)
+	}
+	if opt.SHA != "" {
+		acceptOption.Sha = gitlab.String(opt.SHA)
+	}
+	if opt.Squash {
+		acceptOption.Squash = gitlab.Bool(true)
+	}
+	if opt.SquashCommitMessage != "" {
+		acceptOption.SquashCommitMessage = gitlab.String(opt.SquashCommitMessage)
+	}
+	return acceptOption
+}
+
+type approveMethod struct {
+	client  api.MergeRequest
+	opt     *MergeOption
+	project string
+	id      int
+}
+
+func (m *approveMethod) Process() (string, error) {
+	approveOption := &gitlab.ApproveMergeRequestOptions{}
+	if m.opt.SHA != "" {
+		approveOption.SHA = gitlab.String(m.opt.SHA)
+	}
+
+	approvals, err := m.client.ApproveMergeRequest(approveOption, m.id, m.project)
+	if err != nil {
+		if api.StatusCode(err) == http.StatusConflict {
+			return "", &internal.ExitError{
+				Code: ExitCodeSHAMismatch,
+				Err:  fmt.Errorf("The source branch of merge request !%d is not at %s", m.id, m.opt.SHA),
+			}
+		}
+		return "", err
+	}
+	return fmt.Sprintf("Approved merge request !%d (%s)", m.id, approvalsSummary(approvals)), nil
+}
+
+type unapproveMethod struct {
+	client  api.MergeRequest
+	project string
+	id      int
+}
+
+func (m *unapproveMethod) Process() (string, error) {
+	if err := m.client.UnapproveMergeRequest(m.id, m.project); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Unapproved merge request !%d", m.id), nil
+}
+
+// approvalsSummary returns the number of approvals against the required
+// number, and the approvers. e.g. "1/2 approved by @alice"
+func approvalsSummary(approvals *gitlab.MergeRequestApprovals) string {
+	summary := strconv.Itoa(len(approvals.ApprovedBy))
+	if approvals.ApprovalsRequired > 0 {
+		summary = fmt.Sprintf("%d/%d", len(approvals.ApprovedBy), approvals.ApprovalsRequired)
+	}
+	if len(approvals.ApprovedBy) == 0 {
+		return summary
+	}
+
+	names := make([]string, len(approvals.ApprovedBy))
+	for i, approver := range approvals.ApprovedBy {
+		names[i] = "@" + approver.User.Username
+	}
+	return summary + " approved by " + strings.Join(names, ", ")
+}
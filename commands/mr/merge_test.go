@@ -0,0 +1,155 @@
+package mr
+
+import (
+	"testing"
+
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func TestMergeMethod_Process(t *testing.T) {
+	tests := []struct {
+		name         string
+		opt          *MergeOption
+		merged       *gitlab.MergeRequest
+		acceptErr    error
+		pipeline     string
+		want         string
+		wantExitCode int
+	}{
+		{
+			name:   "merged",
+			opt:    &MergeOption{Merge: true},
+			merged: &gitlab.MergeRequest{State: "merged"},
+			want:   "Merged merge request !12",
+		},
+		{
+			name:   "when pipeline succeeds",
+			opt:    &MergeOption{Merge: true, WhenPipelineSucceeds: true},
+			merged: &gitlab.MergeRequest{State: "opened", MergeWhenPipelineSucceeds: true},
+			want:   "Merge request !12 will be merged when the pipeline succeeds",
+		},
+		{
+			name:         "pipeline not finished",
+			opt:          &MergeOption{Merge: true},
+			acceptErr:    &api.ResponseError{StatusCode: 405, Message: "Method Not Allowed"},
+			pipeline:     "running",
+			wantExitCode: ExitCodePipelineNotFinished,
+		},
+		{
+			name:         "not mergeable",
+			opt:          &MergeOption{Merge: true},
+			acceptErr:    &api.ResponseError{StatusCode: 405, Message: "Method Not Allowed"},
+			pipeline:     "failed",
+			wantExitCode: ExitCodeNotMergeable,
+		},
+		{
+			name:         "conflict",
+			opt:          &MergeOption{Merge: true},
+			acceptErr:    &api.ResponseError{StatusCode: 406, Message: "Branch cannot be merged"},
+			wantExitCode: ExitCodeNotMergeable,
+		},
+		{
+			name:         "sha mismatch",
+			opt:          &MergeOption{Merge: true, SHA: "abc"},
+			acceptErr:    &api.ResponseError{StatusCode: 409, Message: "SHA does not match HEAD of source branch"},
+			wantExitCode: ExitCodeSHAMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mergeMethod{
+				client: &api.MockLabMergeRequestClient{
+					MockAcceptMergeRequest: func(opt *api.AcceptMergeRequestOptions, pid int, repositoryName string) (*gitlab.MergeRequest, error) {
+						return tt.merged, tt.acceptErr
+					},
+					MockGetMergeRequest: func(pid int, repositoryName string) (*gitlab.MergeRequest, error) {
+						mergeRequest := &gitlab.MergeRequest{}
+						mergeRequest.Pipeline.Status = tt.pipeline
+						return mergeRequest, nil
+					},
+				},
+				opt:     tt.opt,
+				project: "group/repo",
+				id:      12,
+			}
+			got, err := m.Process()
+			if tt.wantExitCode != 0 {
+				if gotCode := internal.ExitCode(err, ExitCodeError); gotCode != tt.wantExitCode {
+					t.Errorf("mergeMethod.Process() exit code = %d, want %d, error %v", gotCode, tt.wantExitCode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("mergeMethod.Process() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("mergeMethod.Process() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_makeAcceptMergeRequestOptions(t *testing.T) {
+	got := makeAcceptMergeRequestOptions(&MergeOption{
+		Squash:              true,
+		RemoveSourceBranch:  true,
+		SHA:                 "abc",
+		SquashCommitMessage: "squashed",
+	})
+	if got.Squash == nil || !*got.Squash {
+		t.Errorf("Squash = %v, want true", got.Squash)
+	}
+	if got.ShouldRemoveSourceBranch == nil || !*got.ShouldRemoveSourceBranch {
+		t.Errorf("ShouldRemoveSourceBranch = %v, want true", got.ShouldRemoveSourceBranch)
+	}
+	if got.Sha == nil || *got.Sha != "abc" {
+		t.Errorf("Sha = %v, want abc", got.Sha)
+	}
+	if got.SquashCommitMessage == nil || *got.SquashCommitMessage != "squashed" {
+		t.Errorf("SquashCommitMessage = %v, want squashed", got.SquashCommitMessage)
+	}
+	if got.MergeWhenPipelineSucceeds != nil || got.MergeCommitMessage != nil {
+		t.Errorf("unset options are sent, %#v", got)
+	}
+}
+
+func Test_approvalsSummary(t *testing.T) {
+	approved := &gitlab.MergeRequestApprovals{ApprovalsRequired: 2}
+	approved.ApprovedBy = make([]struct {
+		User struct {
+			ID        int    `json:"id"`
+			Name      string `json:"name"`
+			Username  string `json:"username"`
+			State     string `json:"state"`
+			AvatarURL string `json:"avatar_url"`
+			WebURL    string `json:"web_url"`
+		} `json:"user"`
+	}, 1)
+	approved.ApprovedBy[0].User.Username = "alice"
+
+	tests := []struct {
+		name      string
+		approvals *gitlab.MergeRequestApprovals
+		want      string
+	}{
+		{
+			name:      "no rule",
+			approvals: &gitlab.MergeRequestApprovals{},
+			want:      "0",
+		},
+		{
+			name:      "approved",
+			approvals: approved,
+			want:      "1/2 approved by @alice",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := approvalsSummary(tt.approvals); got != tt.want {
+				t.Errorf("approvalsSummary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
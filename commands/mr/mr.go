@@ -9,6 +9,7 @@ import (
 	"github.com/lighttiger2505/lab/git"
 	"github.com/lighttiger2505/lab/internal/api"
 	"github.com/lighttiger2505/lab/internal/browse"
+	"github.com/lighttiger2505/lab/internal/cache"
 	"github.com/lighttiger2505/lab/internal/gitutil"
 	"github.com/lighttiger2505/lab/internal/ui"
 )
@@ -17,6 +18,15 @@ const (
 	ExitCodeOK        int = iota //0
 	ExitCodeError     int = iota //1
 	ExitCodeFileError int = iota //2
+	// ExitCodeNotMergeable is returned when GitLab refuses to merge, e.g. for
+	// conflicts, missing approvals or unresolved discussions
+	ExitCodeNotMergeable int = iota //3
+	// ExitCodePipelineNotFinished is returned when the pipeline has to
+	// succeed before the merge
+	ExitCodePipelineNotFinished int = iota //4
+	// ExitCodeSHAMismatch is returned when the source branch moved from the
+	// commit given by --sha
+	ExitCodeSHAMismatch int = iota //5
 )
 
 type MergeRequestCommand struct {
@@ -25,6 +35,8 @@ type MergeRequestCommand struct {
 	GitClient       git.Client
 	ClientFactory   api.APIClientFactory
 	EditFunc        func(program, file string) error
+	// Cache keeps what is read from GitLab for --offline, nil to keep nothing
+	Cache *cache.Store
 }
 
 func (c *MergeRequestCommand) Synopsis() string {
@@ -57,27 +69,32 @@ func (c *MergeRequestCommand) Run(args []string) int {
 		return ExitCodeError
 	}
 
-	if err := c.ClientFactory.Init(pInfo.ApiUrl(), pInfo.Token); err != nil {
+	if err := c.ClientFactory.Init(pInfo.ApiUrl(), pInfo.Token, pInfo.TLS); err != nil {
 		c.UI.Error(err.Error())
 		return ExitCodeError
 	}
 
-	method, err := c.getMethod(opt, parseArgs, pInfo, c.ClientFactory)
+	clientFactory, err := internal.CacheClientFactory(c.ClientFactory, c.Cache, pInfo.Domain, opt.CacheOption)
 	if err != nil {
 		c.UI.Error(err.Error())
 		return ExitCodeError
 	}
 
-	res, err := method.Process()
+	method, err := c.getMethod(opt, parseArgs, pInfo, clientFactory)
 	if err != nil {
 		c.UI.Error(err.Error())
 		return ExitCodeError
 	}
 
-	if res != "" {
-		c.UI.Message(res)
+	opt.OutputOption.Stream(c.UI)
+	res, err := method.Process()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return internal.ExitCode(err, ExitCodeError)
 	}
 
+	opt.OutputOption.Write(c.UI, res)
+
 	return ExitCodeOK
 }
 
@@ -91,6 +108,24 @@ func (c *MergeRequestCommand) getMethod(opt Option, args []string, pInfo *gituti
 	repositoryClient := clientFactory.GetRepositoryClient()
 	noteClient := clientFactory.GetNoteClient()
 
+	if len(args) > 0 && args[0] == "checkout" {
+		iid, err := validMergeRequestIID(args[1:])
+		if err != nil {
+			return nil, err
+		}
+		if iid == 0 {
+			return nil, fmt.Errorf("Invalid args, please input merge request id")
+		}
+		return &checkoutMethod{
+			mrClient:      mrClient,
+			projectClient: clientFactory.GetProjectClient(),
+			gitClient:     c.GitClient,
+			opt:           opt.CheckoutOption,
+			project:       pInfo.Project,
+			id:            iid,
+		}, nil
+	}
+
 	iid, err := validMergeRequestIID(args)
 	if err != nil {
 		return nil, err
@@ -105,8 +140,51 @@ func (c *MergeRequestCommand) getMethod(opt Option, args []string, pInfo *gituti
 		}, nil
 	}
 
+	if len(args) > 1 && !opt.DiffOption.hasDiff() {
+		return c.getDiscussionMethod(opt, args, pInfo, clientFactory, iid)
+	}
+
 	// Case of getting Merge Request id
 	if len(args) > 0 {
+		if opt.CommentOption.HasComment() {
+			return c.getCommentMethod(opt, pInfo, noteClient, iid), nil
+		}
+		mergeOption := opt.MergeOption
+		if mergeOption.Merge {
+			return &mergeMethod{
+				client:  mrClient,
+				opt:     mergeOption,
+				project: pInfo.Project,
+				id:      iid,
+			}, nil
+		}
+		if mergeOption.Approve {
+			return &approveMethod{
+				client:  mrClient,
+				opt:     mergeOption,
+				project: pInfo.Project,
+				id:      iid,
+			}, nil
+		}
+		if mergeOption.Unapprove {
+			return &unapproveMethod{
+				client:  mrClient,
+				project: pInfo.Project,
+				id:      iid,
+			}, nil
+		}
+		if opt.DiffOption.hasDiff() {
+			return &diffMethod{
+				client:  mrClient,
+				opt:     opt.DiffOption,
+				output:  opt.OutputOption,
+				project: pInfo.Project,
+				id:      iid,
+				paths:   args[1:],
+				pager:   diffPager(opt.DiffOption),
+				spawn:   spawnWithInput,
+			}, nil
+		}
 		if createUpdateOption.hasEdit() {
 			return &updateOnEditorMethod{
 				client:   mrClient,
@@ -129,6 +207,7 @@ func (c *MergeRequestCommand) getMethod(opt Option, args []string, pInfo *gituti
 			mrClient:   mrClient,
 			noteClient: noteClient,
 			opt:        showOption,
+			output:     opt.OutputOption,
 			project:    pInfo.Project,
 			id:         iid,
 		}, nil
@@ -157,16 +236,95 @@ func (c *MergeRequestCommand) getMethod(opt Option, args []string, pInfo *gituti
 		return &listAllMethod{
 			client: mrClient,
 			opt:    listOption,
+			output: opt.OutputOption,
 		}, nil
 
 	}
 	return &listMethod{
 		client:  mrClient,
 		opt:     listOption,
+		output:  opt.OutputOption,
 		project: pInfo.Project,
 	}, nil
 }
 
+func (c *MergeRequestCommand) getCommentMethod(opt Option, pInfo *gitutil.GitLabProjectInfo, client api.Note, iid int) internal.Method {
+	commentOption := opt.CommentOption
+	if commentOption.DeleteNote != 0 {
+		return &deleteNoteMethod{
+			client:  client,
+			opt:     commentOption,
+			project: pInfo.Project,
+			id:      iid,
+		}
+	}
+	if commentOption.EditNote != 0 {
+		return &editNoteMethod{
+			client:   client,
+			opt:      commentOption,
+			message:  opt.CreateUpdateOption.Message,
+			project:  pInfo.Project,
+			id:       iid,
+			editFunc: c.EditFunc,
+		}
+	}
+	return &commentMethod{
+		client:   client,
+		opt:      commentOption,
+		message:  opt.CreateUpdateOption.Message,
+		project:  pInfo.Project,
+		id:       iid,
+		editFunc: c.EditFunc,
+	}
+}
+
+// getDiscussionMethod returns the method of the subcommands following the
+// merge request id, e.g. "lab mr 12 reply <discussion id>".
+func (c *MergeRequestCommand) getDiscussionMethod(opt Option, args []string, pInfo *gitutil.GitLabProjectInfo, clientFactory api.APIClientFactory, iid int) (internal.Method, error) {
+	discussionClient := clientFactory.GetDiscussionClient()
+
+	switch args[1] {
+	case "discussions":
+		return &discussionListMethod{
+			client:  discussionClient,
+			opt:     opt.DiscussionOption,
+			output:  opt.OutputOption,
+			project: pInfo.Project,
+			id:      iid,
+		}, nil
+	case "comment":
+		return &startDiscussionMethod{
+			mrClient:         clientFactory.GetMergeRequestClient(),
+			discussionClient: discussionClient,
+			opt:              opt.DiscussionOption,
+			message:          opt.CreateUpdateOption.Message,
+			project:          pInfo.Project,
+			id:               iid,
+		}, nil
+	case "reply", "resolve", "unresolve":
+		if len(args) < 3 {
+			return nil, fmt.Errorf("Invalid args, please input discussion id")
+		}
+		if args[1] == "reply" {
+			return &replyMethod{
+				client:     discussionClient,
+				message:    opt.CreateUpdateOption.Message,
+				project:    pInfo.Project,
+				id:         iid,
+				discussion: args[2],
+			}, nil
+		}
+		return &resolveMethod{
+			client:     discussionClient,
+			resolved:   args[1] == "resolve",
+			project:    pInfo.Project,
+			id:         iid,
+			discussion: args[2],
+		}, nil
+	}
+	return nil, fmt.Errorf("Unknown subcommand %s, please input \"discussions\", \"comment\", \"reply\", \"resolve\" or \"unresolve\"", args[1])
+}
+
 func validMergeRequestIID(args []string) (int, error) {
 	if len(args) < 1 {
 		return 0, nil
@@ -61,6 +61,9 @@ var mockGitlabMergeRequestClient = &api.MockLabMergeRequestClient{
 	MockUpdateMergeRequest: func(opt *gitlab.UpdateMergeRequestOptions, pid int, repositoryName string) (*gitlab.MergeRequest, error) {
 		return mergeRequest, nil
 	},
+	MockGetMergeRequestApprovals: func(pid int, repositoryName string) (*gitlab.MergeRequestApprovals, error) {
+		return &gitlab.MergeRequestApprovals{}, nil
+	},
 }
 
 var mockRepositoryClient = &api.MockRepositoryClient{
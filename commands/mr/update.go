@@ -33,7 +33,7 @@ func (m *updateMethod) Process() (string, error) {
 
 	// Do update merge request
 	_, err = m.client.UpdateMergeRequest(
-		makeUpdateMergeRequestOption(m.opt, updatedTitle, updatedMessage),
+		makeUpdateMergeRequestOption(m.opt, mergeRequest.Labels, updatedTitle, updatedMessage),
 		m.id,
 		m.project,
 	)
@@ -70,7 +70,7 @@ func (m *updateOnEditorMethod) Process() (string, error) {
 
 	// Do update merge request
 	_, err = m.client.UpdateMergeRequest(
-		makeUpdateMergeRequestOption(m.opt, title, message),
+		makeUpdateMergeRequestOption(m.opt, mergeRequest.Labels, title, message),
 		m.id,
 		m.project,
 	)
@@ -82,7 +82,10 @@ func (m *updateOnEditorMethod) Process() (string, error) {
 	return "", nil
 }
 
-func makeUpdateMergeRequestOption(opt *CreateUpdateOption, title, description string) *gitlab.UpdateMergeRequestOptions {
+// makeUpdateMergeRequestOption returns the options updating the merge request.
+// labels are the current labels of the merge request, which the label options
+// change.
+func makeUpdateMergeRequestOption(opt *CreateUpdateOption, labels []string, title, description string) *gitlab.UpdateMergeRequestOptions {
 	updateMergeRequestOptions := &gitlab.UpdateMergeRequestOptions{
 		Title:        gitlab.String(title),
 		Description:  gitlab.String(description),
@@ -97,5 +100,8 @@ func makeUpdateMergeRequestOption(opt *CreateUpdateOption, title, description st
 	if opt.MilestoneID != 0 {
 		updateMergeRequestOptions.MilestoneID = gitlab.Int(opt.MilestoneID)
 	}
+	if opt.hasLabel() {
+		updateMergeRequestOptions.Labels = internal.MakeLabels(opt.getLabels(labels))
+	}
 	return updateMergeRequestOptions
 }
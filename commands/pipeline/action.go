@@ -0,0 +1,137 @@
+package pipeline
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lighttiger2505/lab/git"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+type runMethod struct {
+	client  api.Pipeline
+	trigger api.PipelineTrigger
+	opt     *ActionOption
+	project string
+	url     string
+}
+
+func (m *runMethod) Process() (string, error) {
+	ref := m.opt.Ref
+	if ref == "" {
+		// Run the pipeline of the current branch when non specific flags
+		currentBranch, err := git.CurrentBranch()
+		if err != nil {
+			return "", err
+		}
+		ref = currentBranch
+	}
+
+	if m.opt.Token != "" {
+		runOption, err := makeRunPipelineTriggerOptions(ref, m.opt.Token, m.opt.Variables)
+		if err != nil {
+			return "", err
+		}
+		pipeline, err := m.trigger.RunTrigger(m.project, runOption)
+		if err != nil {
+			return "", err
+		}
+		return pipelineResult(pipeline, m.url), nil
+	}
+
+	createOption, err := makeCreatePipelineOptions(ref, m.opt.Variables)
+	if err != nil {
+		return "", err
+	}
+	pipeline, err := m.client.CreatePipeline(m.project, createOption)
+	if err != nil {
+		return "", err
+	}
+	return pipelineResult(pipeline, m.url), nil
+}
+
+func makeCreatePipelineOptions(ref string, variables []string) (*gitlab.CreatePipelineOptions, error) {
+	createOption := &gitlab.CreatePipelineOptions{
+		Ref: gitlab.String(ref),
+	}
+	for _, variable := range variables {
+		key, value, err := splitVariable(variable)
+		if err != nil {
+			return nil, err
+		}
+		createOption.Variables = append(createOption.Variables, &gitlab.PipelineVariable{
+			Key:   key,
+			Value: value,
+		})
+	}
+	return createOption, nil
+}
+
+func makeRunPipelineTriggerOptions(ref, token string, variables []string) (*gitlab.RunPipelineTriggerOptions, error) {
+	runOption := &gitlab.RunPipelineTriggerOptions{
+		Ref:   gitlab.String(ref),
+		Token: gitlab.String(token),
+	}
+	for _, variable := range variables {
+		key, value, err := splitVariable(variable)
+		if err != nil {
+			return nil, err
+		}
+		if runOption.Variables == nil {
+			runOption.Variables = map[string]string{}
+		}
+		runOption.Variables[key] = value
+	}
+	return runOption, nil
+}
+
+// splitVariable splits a variable given as KEY=VALUE. The value may have "=".
+func splitVariable(variable string) (string, string, error) {
+	kv := strings.SplitN(variable, "=", 2)
+	if len(kv) != 2 || kv[0] == "" {
+		return "", "", fmt.Errorf("Invalid variable %s, please input it as KEY=VALUE", variable)
+	}
+	return kv[0], kv[1], nil
+}
+
+type retryMethod struct {
+	client  api.Pipeline
+	project string
+	id      int
+	url     string
+}
+
+func (m *retryMethod) Process() (string, error) {
+	pipeline, err := m.client.RetryPipeline(m.project, m.id)
+	if err != nil {
+		return "", err
+	}
+	return pipelineResult(pipeline, m.url), nil
+}
+
+type cancelMethod struct {
+	client  api.Pipeline
+	project string
+	id      int
+	url     string
+}
+
+func (m *cancelMethod) Process() (string, error) {
+	pipeline, err := m.client.CancelPipeline(m.project, m.id)
+	if err != nil {
+		return "", err
+	}
+	return pipelineResult(pipeline, m.url), nil
+}
+
+// pipelineResult returns the id of the pipeline, and its web url following
+// the id when url is the url of the pipelines page.
+func pipelineResult(pipeline *gitlab.Pipeline, url string) string {
+	id := strconv.Itoa(pipeline.ID)
+	if url == "" {
+		return id
+	}
+	return id + "\n" + url + "/" + id
+}
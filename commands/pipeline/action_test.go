@@ -0,0 +1,150 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func Test_runMethod_Process(t *testing.T) {
+	tests := []struct {
+		name    string
+		opt     *ActionOption
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "ref and variables",
+			opt: &ActionOption{
+				Ref:       "develop",
+				Variables: []string{"DEPLOY=true", "TARGET=a=b"},
+			},
+			want: "15",
+		},
+		{
+			name: "web url",
+			opt: &ActionOption{
+				Ref:       "develop",
+				Variables: []string{"DEPLOY=true", "TARGET=a=b"},
+			},
+			url:  "https://gitlab.com/group/project/pipelines",
+			want: "15\nhttps://gitlab.com/group/project/pipelines/15",
+		},
+		{
+			name: "invalid variable",
+			opt: &ActionOption{
+				Ref:       "develop",
+				Variables: []string{"DEPLOY"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &runMethod{
+				client: &api.MockPipelineClient{
+					MockCreatePipeline: func(repositoryName string, opt *gitlab.CreatePipelineOptions) (*gitlab.Pipeline, error) {
+						want := &gitlab.CreatePipelineOptions{
+							Ref: gitlab.String("develop"),
+							Variables: []*gitlab.PipelineVariable{
+								{Key: "DEPLOY", Value: "true"},
+								{Key: "TARGET", Value: "a=b"},
+							},
+						}
+						if diff := cmp.Diff(opt, want); diff != "" {
+							t.Errorf("invalide arg (-got +want)\n%s", diff)
+						}
+						return &gitlab.Pipeline{ID: 15}, nil
+					},
+				},
+				opt:     tt.opt,
+				project: "group/project",
+				url:     tt.url,
+			}
+			got, err := m.Process()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("runMethod.Process() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("runMethod.Process() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_runMethod_Process_triggerToken(t *testing.T) {
+	m := &runMethod{
+		trigger: &api.MockPipelineTriggerClient{
+			MockRunTrigger: func(project string, opt *gitlab.RunPipelineTriggerOptions) (*gitlab.Pipeline, error) {
+				want := &gitlab.RunPipelineTriggerOptions{
+					Ref:   gitlab.String("develop"),
+					Token: gitlab.String("secret"),
+					Variables: map[string]string{
+						"DEPLOY": "true",
+						"TARGET": "a=b",
+					},
+				}
+				if diff := cmp.Diff(opt, want); diff != "" {
+					t.Errorf("invalide arg (-got +want)\n%s", diff)
+				}
+				return &gitlab.Pipeline{ID: 16}, nil
+			},
+		},
+		opt: &ActionOption{
+			Ref:       "develop",
+			Variables: []string{"DEPLOY=true", "TARGET=a=b"},
+			Token:     "secret",
+		},
+		project: "group/project",
+	}
+	got, err := m.Process()
+	if err != nil {
+		t.Fatalf("runMethod.Process() error = %v", err)
+	}
+	if got != "16" {
+		t.Errorf("runMethod.Process() = %q, want %q", got, "16")
+	}
+}
+
+func Test_retryCancelMethod_Process(t *testing.T) {
+	client := &api.MockPipelineClient{
+		MockRetryPipeline: func(repositoryName string, pid int) (*gitlab.Pipeline, error) {
+			return &gitlab.Pipeline{ID: pid, Status: "pending"}, nil
+		},
+		MockCancelPipeline: func(repositoryName string, pid int) (*gitlab.Pipeline, error) {
+			return &gitlab.Pipeline{ID: pid, Status: "canceled"}, nil
+		},
+	}
+	tests := []struct {
+		name   string
+		method internal.Method
+		want   string
+	}{
+		{
+			name:   "retry",
+			method: &retryMethod{client: client, project: "group/project", id: 12},
+			want:   "12",
+		},
+		{
+			name:   "cancel",
+			method: &cancelMethod{client: client, project: "group/project", id: 12, url: "https://gitlab.com/group/project/pipelines"},
+			want:   "12\nhttps://gitlab.com/group/project/pipelines/12",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.method.Process()
+			if err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Process() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
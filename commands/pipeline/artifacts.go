@@ -0,0 +1,59 @@
+package pipeline
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/git"
+	"github.com/lighttiger2505/lab/internal/api"
+	"github.com/lighttiger2505/lab/internal/ui"
+)
+
+// artifactsMethod downloads the latest artifacts of the job of the name on
+// the ref of the pipeline, or on the current branch when no pipeline is
+// given.
+type artifactsMethod struct {
+	client    api.Pipeline
+	jobClient api.Job
+	ui        ui.UI
+	opt       *ArtifactsOption
+	project   string
+	id        int
+}
+
+func (m *artifactsMethod) Process() (string, error) {
+	if m.opt.Job == "" {
+		return "", fmt.Errorf("Please input the job name with --job")
+	}
+
+	ref, err := m.ref()
+	if err != nil {
+		return "", err
+	}
+
+	paths, err := internal.DownloadArtifacts(
+		m.ui,
+		func(w io.Writer) error {
+			return m.jobClient.DownloadArtifactsByRef(m.project, ref, m.opt.Job, w)
+		},
+		0,
+		m.opt.Dir,
+	)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(paths, "\n"), nil
+}
+
+func (m *artifactsMethod) ref() (string, error) {
+	if m.id == 0 {
+		return git.CurrentBranch()
+	}
+	pipeline, err := m.client.GetPipeline(m.project, m.id)
+	if err != nil {
+		return "", err
+	}
+	return pipeline.Ref, nil
+}
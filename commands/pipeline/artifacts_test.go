@@ -0,0 +1,54 @@
+package pipeline
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/lighttiger2505/lab/internal/api"
+	"github.com/lighttiger2505/lab/internal/ui"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func TestArtifactsMethod_Process(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lab-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var gotRef, gotJob string
+	method := &artifactsMethod{
+		client: &api.MockPipelineClient{
+			MockGetPipeline: func(repositoryName string, pid int) (*gitlab.Pipeline, error) {
+				return &gitlab.Pipeline{ID: pid, Ref: "feature/artifacts"}, nil
+			},
+		},
+		jobClient: &api.MockLabJobClient{
+			MockDownloadArtifactsByRef: func(repositoryName string, ref string, jobName string, w io.Writer) error {
+				gotRef, gotJob = ref, jobName
+				return nil
+			},
+		},
+		ui:      ui.NewMockUi(),
+		opt:     &ArtifactsOption{Artifacts: true, Job: "build", Dir: dir},
+		project: "group/project",
+		id:      12,
+	}
+
+	// The empty archive is not a zip file
+	_, err = method.Process()
+	if err == nil || !strings.Contains(err.Error(), "Failed open artifacts") {
+		t.Errorf("Process() error = %v, want open error", err)
+	}
+	if gotRef != "feature/artifacts" || gotJob != "build" {
+		t.Errorf("Process() downloaded %s of %s, want build of feature/artifacts", gotJob, gotRef)
+	}
+
+	method.opt.Job = ""
+	if _, err := method.Process(); err == nil {
+		t.Errorf("Process() want error without job name")
+	}
+}
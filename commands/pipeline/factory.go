@@ -1,19 +1,25 @@
 package pipeline
 
 import (
+	"time"
+
+	"github.com/fatih/color"
 	"github.com/lighttiger2505/lab/commands/internal"
 	"github.com/lighttiger2505/lab/internal/api"
 	"github.com/lighttiger2505/lab/internal/browse"
 	"github.com/lighttiger2505/lab/internal/gitutil"
+	"github.com/lighttiger2505/lab/internal/ui"
 )
 
 type MethodFactory interface {
-	CreateMethod(opt Option, pInfo *gitutil.GitLabProjectInfo, iid int, factory api.APIClientFactory) internal.Method
+	CreateMethod(opt Option, pInfo *gitutil.GitLabProjectInfo, subcommand string, iid int, factory api.APIClientFactory) internal.Method
 }
 
-type PipelineMethodFacotry struct{}
+type PipelineMethodFacotry struct {
+	UI ui.UI
+}
 
-func (c *PipelineMethodFacotry) CreateMethod(opt Option, pInfo *gitutil.GitLabProjectInfo, iid int, factory api.APIClientFactory) internal.Method {
+func (c *PipelineMethodFacotry) CreateMethod(opt Option, pInfo *gitutil.GitLabProjectInfo, subcommand string, iid int, factory api.APIClientFactory) internal.Method {
 	if opt.BrowseOption.Browse {
 		return &browseMethod{
 			opener: &browse.Browser{},
@@ -22,10 +28,75 @@ func (c *PipelineMethodFacotry) CreateMethod(opt Option, pInfo *gitutil.GitLabPr
 		}
 	}
 
+	var url string
+	if opt.ActionOption.URL {
+		url = pInfo.SubpageUrl("pipelines")
+	}
+	if subcommand == "run" {
+		return &runMethod{
+			client:  factory.GetPipelineClient(),
+			trigger: factory.GetPipelineTriggerClient(),
+			opt:     opt.ActionOption,
+			project: pInfo.Project,
+			url:     url,
+		}
+	}
+
+	if opt.WatchOption.Watch {
+		return &watchMethod{
+			client:  factory.GetPipelineClient(),
+			ui:      c.UI,
+			project: pInfo.Project,
+			id:      iid,
+			redraw:  !color.NoColor,
+			sleep:   time.Sleep,
+			now:     time.Now,
+		}
+	}
+
+	if opt.GraphOption.Graph || opt.GraphOption.Dot {
+		return &graphMethod{
+			client:     factory.GetPipelineClient(),
+			repository: factory.GetRepositoryClient(),
+			ui:         c.UI,
+			project:    pInfo.Project,
+			id:         iid,
+			dot:        opt.GraphOption.Dot,
+		}
+	}
+
+	if opt.ArtifactsOption.Artifacts {
+		return &artifactsMethod{
+			client:    factory.GetPipelineClient(),
+			jobClient: factory.GetJobClient(),
+			ui:        c.UI,
+			opt:       opt.ArtifactsOption,
+			project:   pInfo.Project,
+			id:        iid,
+		}
+	}
+
 	if iid > 0 {
+		if opt.ActionOption.Retry {
+			return &retryMethod{
+				client:  factory.GetPipelineClient(),
+				project: pInfo.Project,
+				id:      iid,
+				url:     url,
+			}
+		}
+		if opt.ActionOption.Cancel {
+			return &cancelMethod{
+				client:  factory.GetPipelineClient(),
+				project: pInfo.Project,
+				id:      iid,
+				url:     url,
+			}
+		}
 		return &listJobMethod{
 			client:  factory.GetPipelineClient(),
 			opt:     opt.ListOption,
+			output:  opt.OutputOption,
 			project: pInfo.Project,
 			id:      iid,
 		}
@@ -34,12 +105,13 @@ func (c *PipelineMethodFacotry) CreateMethod(opt Option, pInfo *gitutil.GitLabPr
 	return &listMethod{
 		client:  factory.GetPipelineClient(),
 		opt:     opt.ListOption,
+		output:  opt.OutputOption,
 		project: pInfo.Project,
 	}
 }
 
 type MockMethodFactory struct{}
 
-func (c *MockMethodFactory) CreateMethod(opt Option, pInfo *gitutil.GitLabProjectInfo, iid int, factory api.APIClientFactory) internal.Method {
+func (c *MockMethodFactory) CreateMethod(opt Option, pInfo *gitutil.GitLabProjectInfo, subcommand string, iid int, factory api.APIClientFactory) internal.Method {
 	return &internal.MockMethod{}
 }
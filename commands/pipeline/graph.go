@@ -0,0 +1,403 @@
+package pipeline
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lighttiger2505/lab/internal/api"
+	"github.com/lighttiger2505/lab/internal/ciconfig"
+	"github.com/lighttiger2505/lab/internal/ui"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// ciConfigFile is the CI config the needs of the jobs are read from.
+const ciConfigFile = ".gitlab-ci.yml"
+
+type graphMethod struct {
+	client     api.Pipeline
+	repository api.Repository
+	ui         ui.UI
+	project    string
+	id         int
+	dot        bool
+}
+
+func (m *graphMethod) Process() (string, error) {
+	id := m.id
+	if id == 0 {
+		latest, err := latestPipelineID(m.client, m.project)
+		if err != nil {
+			return "", err
+		}
+		id = latest
+	}
+
+	pipeline, err := m.client.GetPipeline(m.project, id)
+	if err != nil {
+		return "", err
+	}
+	var jobs []*gitlab.Job
+	err = m.client.ProjectPipelineJobs(
+		m.project,
+		makeListPiplineJobOptions(),
+		id,
+		0,
+		func(page []*gitlab.Job) error {
+			jobs = append(jobs, page...)
+			return nil
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	stages, stageJobs := watchStages(jobs)
+	needs := m.needs(pipeline.Sha)
+	if m.dot {
+		return graphDot(pipeline, stages, stageJobs, needs), nil
+	}
+	return graphOutput(stages, stageJobs, needs), nil
+}
+
+// needs returns the needs of the jobs in the CI config of the commit, with
+// the local includes and the extends resolved. The graph is drawn by the
+// stages alone when the config can not be read.
+func (m *graphMethod) needs(sha string) map[string][]string {
+	config, err := ciconfig.LoadFunc(ciConfigFile, func(path string) ([]byte, error) {
+		content, err := m.repository.GetFile(
+			m.project,
+			path,
+			&gitlab.GetRawFileOptions{Ref: gitlab.String(sha)},
+		)
+		return []byte(content), err
+	})
+	if err != nil {
+		m.ui.Error(fmt.Sprintf("warning: the needs are not drawn, failed read %s. %s", ciConfigFile, err))
+		return nil
+	}
+	for _, warning := range config.Warnings {
+		m.ui.Error(fmt.Sprintf("warning: %s, the needs in it are not drawn", warning))
+	}
+	return jobNeeds(config)
+}
+
+// jobNeeds returns the needs of the jobs by the job name. The jobs with
+// "needs: []" have an empty slice, and the jobs without needs are missing.
+func jobNeeds(config *ciconfig.Config) map[string][]string {
+	needs := map[string][]string{}
+	for _, name := range config.Jobs() {
+		if jobs, ok := config.Needs(name); ok {
+			needs[name] = jobs
+		}
+	}
+	return needs
+}
+
+// graphCell is the place of a job in the graph, the index of the stage and
+// the index of the job in the stage.
+type graphCell struct {
+	stage int
+	row   int
+}
+
+// graphGutter is the space between a stage and the next one. The needs are
+// drawn in lanes, the vertical lines of the gutter.
+type graphGutter struct {
+	// out are the rows of the jobs of the stage with needed jobs in the
+	// next stages, by the lane
+	out []int
+	// targets are the rows of the jobs of the next stage needing the job of
+	// an out lane
+	targets map[int][]int
+	// in are the buses bringing the needs of the earlier stages to the next
+	// stage, by the lane after the out lanes
+	in []*graphBus
+}
+
+func (g *graphGutter) lanes() int {
+	return len(g.out) + len(g.in)
+}
+
+// graphBus is a line under the jobs carrying the needs of a job to the
+// stages after the next one.
+type graphBus struct {
+	source  graphCell
+	targets map[int][]int
+}
+
+// graphOutput draws the stages as columns with their jobs under them, and the
+// needs of the jobs as lines between the columns. The needs which can not be
+// drawn are listed after the graph, like the needs in the same stage.
+func graphOutput(stages []string, stageJobs map[string][]*watchJob, needs map[string][]string) string {
+	if len(stages) == 0 {
+		return ""
+	}
+
+	cells := map[string]graphCell{}
+	texts := make([][]string, len(stages))
+	depth := 0
+	for i, stage := range stages {
+		for j, job := range stageJobs[stage] {
+			cells[job.Name] = graphCell{stage: i, row: j}
+			texts[i] = append(texts[i], fmt.Sprintf("%s (%s)", job.Name, job.Status))
+		}
+		if len(stageJobs[stage]) > depth {
+			depth = len(stageJobs[stage])
+		}
+	}
+
+	// The needs of the next stage go through the gutter, the needs of the
+	// later stages go through a bus
+	gutters := make([]*graphGutter, len(stages)-1)
+	for i := range gutters {
+		gutters[i] = &graphGutter{targets: map[int][]int{}}
+	}
+	var buses []*graphBus
+	busOf := map[graphCell]*graphBus{}
+	var listed []string
+	for _, stage := range stages {
+		for _, job := range stageJobs[stage] {
+			to := cells[job.Name]
+			var unlinked []string
+			for _, need := range needs[job.Name] {
+				from, ok := cells[need]
+				switch {
+				case !ok:
+					// The optional needs missing in the pipeline
+				case from.stage >= to.stage:
+					unlinked = append(unlinked, need)
+				case from.stage+1 == to.stage:
+					gutters[from.stage].targets[from.row] = append(gutters[from.stage].targets[from.row], to.row)
+				default:
+					bus, ok := busOf[from]
+					if !ok {
+						bus = &graphBus{source: from, targets: map[int][]int{}}
+						busOf[from] = bus
+						buses = append(buses, bus)
+					}
+					bus.targets[to.stage] = append(bus.targets[to.stage], to.row)
+				}
+			}
+			if len(unlinked) > 0 {
+				listed = append(listed, fmt.Sprintf("  %s --> %s", strings.Join(unlinked, ", "), job.Name))
+			}
+		}
+	}
+
+	// A line entering a job from a lane left of the lane of the job itself
+	// would run into the line leaving the job. Those needs are listed
+	for i, gutter := range gutters {
+		for row := 0; row < len(texts[i]); row++ {
+			if len(gutter.targets[row]) > 0 || busOf[graphCell{stage: i, row: row}] != nil {
+				gutter.out = append(gutter.out, row)
+			}
+		}
+		for _, from := range gutter.out {
+			var kept []int
+			for _, to := range gutter.targets[from] {
+				if from < to && (len(gutter.targets[to]) > 0 || busOf[graphCell{stage: i, row: to}] != nil) {
+					listed = append(listed, fmt.Sprintf("  %s --> %s", stageJobs[stages[i]][from].Name, stageJobs[stages[i+1]][to].Name))
+					continue
+				}
+				kept = append(kept, to)
+			}
+			gutter.targets[from] = kept
+		}
+	}
+	for _, bus := range buses {
+		for stage := range bus.targets {
+			gutters[stage-1].in = append(gutters[stage-1].in, bus)
+		}
+	}
+
+	// The x of the columns and of the lanes
+	widths := make([]int, len(stages))
+	xs := make([]int, len(stages))
+	for i, stage := range stages {
+		widths[i] = len([]rune(stage))
+		for _, text := range texts[i] {
+			if n := len([]rune(text)); n > widths[i] {
+				widths[i] = n
+			}
+		}
+		if i > 0 {
+			gutterWidth := 1
+			if n := gutters[i-1].lanes(); n > 0 {
+				gutterWidth = 2*n + 3
+			}
+			xs[i] = xs[i-1] + widths[i-1] + 1 + gutterWidth
+		}
+	}
+	laneX := func(gutter, lane int) int {
+		return xs[gutter] + widths[gutter] + 2 + 2*lane
+	}
+	arrowX := func(gutter int) int {
+		return xs[gutter+1] - 2
+	}
+
+	c := newGraphCanvas(xs[len(stages)-1]+widths[len(stages)-1], 1+depth+len(buses))
+	for i, stage := range stages {
+		c.text(xs[i], 0, stage)
+		for j, text := range texts[i] {
+			c.text(xs[i], 1+j, text)
+		}
+	}
+	busY := map[*graphBus]int{}
+	for i, bus := range buses {
+		busY[bus] = 1 + depth + i
+	}
+	for i, gutter := range gutters {
+		for lane, from := range gutter.out {
+			x := laneX(i, lane)
+			ys := []int{1 + from}
+			for _, to := range gutter.targets[from] {
+				ys = append(ys, 1+to)
+				c.hline(x, arrowX(i)-1, 1+to)
+				c.put(arrowX(i), 1+to, '>')
+			}
+			if bus := busOf[graphCell{stage: i, row: from}]; bus != nil {
+				ys = append(ys, busY[bus])
+			}
+			c.hline(xs[i]+len([]rune(texts[i][from]))+1, x, 1+from)
+			c.lane(x, ys)
+		}
+		for lane, bus := range gutter.in {
+			x := laneX(i, len(gutter.out)+lane)
+			ys := []int{busY[bus]}
+			for _, to := range bus.targets[i+1] {
+				ys = append(ys, 1+to)
+				c.hline(x, arrowX(i)-1, 1+to)
+				c.put(arrowX(i), 1+to, '>')
+			}
+			c.lane(x, ys)
+		}
+	}
+	for _, bus := range buses {
+		gutter := gutters[bus.source.stage]
+		from := 0
+		for lane, row := range gutter.out {
+			if row == bus.source.row {
+				from = laneX(bus.source.stage, lane)
+			}
+		}
+		to := from
+		for stage := range bus.targets {
+			for lane, b := range gutters[stage-1].in {
+				if b == bus {
+					if x := laneX(stage-1, len(gutters[stage-1].out)+lane); x > to {
+						to = x
+					}
+				}
+			}
+		}
+		c.hline(from, to, busY[bus])
+	}
+
+	lines := c.lines()
+	if len(listed) > 0 {
+		lines = append(lines, "", "needs:")
+		lines = append(lines, listed...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// graphCanvas is the characters of a graph. The vertical lines cross the
+// horizontal ones, and "+" joins them.
+type graphCanvas [][]rune
+
+func newGraphCanvas(width, height int) graphCanvas {
+	c := make(graphCanvas, height)
+	for y := range c {
+		c[y] = []rune(strings.Repeat(" ", width))
+	}
+	return c
+}
+
+func (c graphCanvas) text(x, y int, text string) {
+	for i, r := range []rune(text) {
+		c[y][x+i] = r
+	}
+}
+
+func (c graphCanvas) put(x, y int, r rune) {
+	switch c[y][x] {
+	case '+', '>':
+		return
+	case '|':
+		if r == '-' {
+			return
+		}
+	}
+	c[y][x] = r
+}
+
+// hline draws a horizontal line from x1 to x2, both included.
+func (c graphCanvas) hline(x1, x2, y int) {
+	for x := x1; x <= x2; x++ {
+		c.put(x, y, '-')
+	}
+}
+
+// lane draws a vertical line through the ys, joined to the lines at them.
+func (c graphCanvas) lane(x int, ys []int) {
+	top, bottom := ys[0], ys[0]
+	for _, y := range ys {
+		if y < top {
+			top = y
+		}
+		if y > bottom {
+			bottom = y
+		}
+	}
+	for y := top; y <= bottom; y++ {
+		c.put(x, y, '|')
+	}
+	for _, y := range ys {
+		c[y][x] = '+'
+	}
+}
+
+func (c graphCanvas) lines() []string {
+	lines := make([]string, len(c))
+	for y, line := range c {
+		lines[y] = strings.TrimRight(string(line), " ")
+	}
+	return lines
+}
+
+// graphDot returns the graph in the DOT language of Graphviz. The jobs with
+// needs are linked to the needed jobs, and the other jobs are linked to the
+// jobs of the previous stage by dashed edges.
+func graphDot(pipeline *gitlab.Pipeline, stages []string, stageJobs map[string][]*watchJob, needs map[string][]string) string {
+	lines := []string{
+		fmt.Sprintf("digraph %q {", fmt.Sprintf("pipeline #%d", pipeline.ID)),
+		"  rankdir=LR;",
+		"  node [shape=box];",
+	}
+	for i, stage := range stages {
+		lines = append(lines, fmt.Sprintf("  subgraph %q {", fmt.Sprintf("cluster_%d", i)))
+		lines = append(lines, fmt.Sprintf("    label=%q;", stage))
+		for _, job := range stageJobs[stage] {
+			lines = append(lines, fmt.Sprintf("    %q [label=%q];", job.Name, job.Name+"\n"+job.Status))
+		}
+		lines = append(lines, "  }")
+	}
+	for i, stage := range stages {
+		for _, job := range stageJobs[stage] {
+			if jobNeeds, ok := needs[job.Name]; ok {
+				for _, need := range jobNeeds {
+					lines = append(lines, fmt.Sprintf("  %q -> %q;", need, job.Name))
+				}
+				continue
+			}
+			if i == 0 {
+				continue
+			}
+			for _, previous := range stageJobs[stages[i-1]] {
+				lines = append(lines, fmt.Sprintf("  %q -> %q [style=dashed];", previous.Name, job.Name))
+			}
+		}
+	}
+	lines = append(lines, "}")
+	return strings.Join(lines, "\n")
+}
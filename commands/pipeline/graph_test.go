@@ -0,0 +1,202 @@
+package pipeline
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/lighttiger2505/lab/internal/api"
+	"github.com/lighttiger2505/lab/internal/ciconfig"
+	"github.com/lighttiger2505/lab/internal/ui"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+var graphFiles = map[string]string{
+	".gitlab-ci.yml": `
+stages: [build, test, deploy]
+include:
+  - local: /ci/deploy.yml
+  - template: Security/SAST.gitlab-ci.yml
+.template:
+  needs: [hidden]
+compile:
+  stage: build
+unit:
+  stage: test
+  needs: [compile]
+lint:
+  stage: test
+  needs: []
+`,
+	"ci/deploy.yml": `
+.deploy:
+  stage: deploy
+  needs:
+    - job: unit
+    - lint
+production:
+  extends: .deploy
+notify:
+  stage: deploy
+  needs: [compile]
+`,
+}
+
+func readGraphFile(path string) ([]byte, error) {
+	content, ok := graphFiles[path]
+	if !ok {
+		return nil, fmt.Errorf("404 File Not Found")
+	}
+	return []byte(content), nil
+}
+
+func Test_jobNeeds(t *testing.T) {
+	config, err := ciconfig.LoadFunc(ciConfigFile, readGraphFile)
+	if err != nil {
+		t.Fatalf("LoadFunc() error = %v", err)
+	}
+	want := map[string][]string{
+		"unit":       {"compile"},
+		"lint":       {},
+		"production": {"unit", "lint"},
+		"notify":     {"compile"},
+	}
+	if diff := cmp.Diff(jobNeeds(config), want); diff != "" {
+		t.Errorf("invalide arg (-got +want)\n%s", diff)
+	}
+}
+
+func Test_graphOutput(t *testing.T) {
+	stages, stageJobs := watchStages([]*gitlab.Job{
+		{ID: 1, Stage: "build", Name: "compile", Status: "success"},
+		{ID: 2, Stage: "build", Name: "assets", Status: "success"},
+		{ID: 3, Stage: "test", Name: "unit", Status: "running"},
+	})
+	tests := []struct {
+		name  string
+		needs map[string][]string
+		want  string
+	}{
+		{
+			name: "no needs",
+			want: `build              test
+compile (success)  unit (running)
+assets (success)`,
+		},
+		{
+			name: "needs in the same stage",
+			needs: map[string][]string{
+				"assets": {"compile"},
+				"unit":   {"assets", "missing"},
+			},
+			want: `build                  test
+compile (success)  +-> unit (running)
+assets (success) --+
+
+needs:
+  compile --> assets`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := graphOutput(stages, stageJobs, tt.needs)
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("invalide arg (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_graphMethod_Process(t *testing.T) {
+	jobs := []*gitlab.Job{
+		{ID: 1, Stage: "build", Name: "compile", Status: "success"},
+		{ID: 2, Stage: "test", Name: "unit", Status: "success"},
+		{ID: 3, Stage: "test", Name: "lint", Status: "failed"},
+		{ID: 4, Stage: "deploy", Name: "production", Status: "manual"},
+		{ID: 5, Stage: "deploy", Name: "notify", Status: "skipped"},
+	}
+	var gotRef string
+	var mockUI *ui.MockUi
+	newMethod := func(dot bool) *graphMethod {
+		mockUI = ui.NewMockUi()
+		return &graphMethod{
+			client: &api.MockPipelineClient{
+				MockGetPipeline: func(repositoryName string, pid int) (*gitlab.Pipeline, error) {
+					return &gitlab.Pipeline{ID: pid, Sha: "1a2b3c4d"}, nil
+				},
+				MockProjectPipelineJobs: func(repositoryName string, opt *gitlab.ListJobsOptions, pid int) ([]*gitlab.Job, error) {
+					return jobs, nil
+				},
+			},
+			repository: &api.MockRepositoryClient{
+				MockGetFile: func(repositoryName string, filename string, opt *gitlab.GetRawFileOptions) (string, error) {
+					gotRef = *opt.Ref
+					content, err := readGraphFile(filename)
+					return string(content), err
+				},
+			},
+			ui:      mockUI,
+			project: "group/project",
+			id:      12,
+			dot:     dot,
+		}
+	}
+
+	tests := []struct {
+		name string
+		dot  bool
+		want string
+	}{
+		{
+			name: "graph",
+			want: `build                  test                    deploy
+compile (success) -+-> unit (success) -+-+---> production (manual)
+                   |   lint (failed) ----+ +-> notify (skipped)
+                   +-----------------------+`,
+		},
+		{
+			name: "dot",
+			dot:  true,
+			want: `digraph "pipeline #12" {
+  rankdir=LR;
+  node [shape=box];
+  subgraph "cluster_0" {
+    label="build";
+    "compile" [label="compile\nsuccess"];
+  }
+  subgraph "cluster_1" {
+    label="test";
+    "unit" [label="unit\nsuccess"];
+    "lint" [label="lint\nfailed"];
+  }
+  subgraph "cluster_2" {
+    label="deploy";
+    "production" [label="production\nmanual"];
+    "notify" [label="notify\nskipped"];
+  }
+  "compile" -> "unit";
+  "unit" -> "production";
+  "lint" -> "production";
+  "compile" -> "notify";
+}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newMethod(tt.dot).Process()
+			if err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("invalide arg (-got +want)\n%s", diff)
+			}
+			if gotRef != "1a2b3c4d" {
+				t.Errorf("read %s at %s, want 1a2b3c4d", ciConfigFile, gotRef)
+			}
+			wantWarning := "warning: include {template: Security/SAST.gitlab-ci.yml} can not be read from the repository, the needs in it are not drawn\n"
+			if got := mockUI.ErrorWriter.String(); got != wantWarning {
+				t.Errorf("Process() warned %q, want %q", got, wantWarning)
+			}
+		})
+	}
+}
@@ -2,50 +2,63 @@ package pipeline
 
 import (
 	"strconv"
-	"strings"
 
+	"github.com/lighttiger2505/lab/commands/internal"
 	"github.com/lighttiger2505/lab/internal/api"
-	"github.com/ryanuber/columnize"
 	gitlab "github.com/xanzy/go-gitlab"
 )
 
 type listMethod struct {
 	client  api.Pipeline
 	opt     *ListOption
+	output  *internal.OutputOption
 	project string
 }
 
 func (m *listMethod) Process() (string, error) {
-	pipelines, err := m.client.ProjectPipelines(
+	w := m.output.NewListWriter()
+	err := m.client.ProjectPipelines(
 		m.project,
 		makeListPipelineOptions(m.opt),
+		internal.Limit(m.opt.Num, m.opt.All),
+		func(pipelines gitlab.PipelineList) error {
+			return w.WritePage(pipelines, func() [][]string {
+				return pipelineListOutput(pipelines)
+			})
+		},
 	)
 	if err != nil {
 		return "", err
 	}
-
-	result := columnize.SimpleFormat(pipelineListOutput(pipelines))
-	return result, nil
+	return w.Flush()
 }
 
 type listJobMethod struct {
 	client  api.Pipeline
 	opt     *ListOption
+	output  *internal.OutputOption
 	project string
 	id      int
 }
 
 func (m *listJobMethod) Process() (string, error) {
-	jobs, err := m.client.ProjectPipelineJobs(
+	// The jobs of a pipeline are printed at once, there is no reason to cut them
+	w := m.output.NewListWriter()
+	err := m.client.ProjectPipelineJobs(
 		m.project,
 		makeListPiplineJobOptions(),
 		m.id,
+		0,
+		func(jobs []*gitlab.Job) error {
+			return w.WritePage(jobs, func() [][]string {
+				return pipelineJobListOutput(jobs)
+			})
+		},
 	)
 	if err != nil {
 		return "", err
 	}
-	result := columnize.SimpleFormat(pipelineJobListOutput(jobs))
-	return result, nil
+	return w.Flush()
 }
 
 func makeListPipelineOptions(listPipelineOption *ListOption) *gitlab.ListProjectPipelinesOptions {
@@ -59,8 +72,7 @@ func makeListPipelineOptions(listPipelineOption *ListOption) *gitlab.ListProject
 		status = &v
 	}
 	listOption := &gitlab.ListOptions{
-		Page:    1,
-		PerPage: listPipelineOption.Num,
+		Page: 1,
 	}
 	listPipelinesOptions := &gitlab.ListProjectPipelinesOptions{
 		Scope:       scope,
@@ -80,24 +92,24 @@ func makeListPiplineJobOptions() *gitlab.ListJobsOptions {
 	return &gitlab.ListJobsOptions{}
 }
 
-func pipelineListOutput(pipelines gitlab.PipelineList) []string {
-	var outputs []string
+func pipelineListOutput(pipelines gitlab.PipelineList) [][]string {
+	var outputs [][]string
 	for _, pipeline := range pipelines {
-		output := strings.Join([]string{
+		output := []string{
 			strconv.Itoa(pipeline.ID),
 			pipeline.Status,
 			pipeline.Ref,
 			pipeline.Sha,
-		}, "|")
+		}
 		outputs = append(outputs, output)
 	}
 	return outputs
 }
 
-func pipelineJobListOutput(jobs []*gitlab.Job) []string {
-	var outputs []string
+func pipelineJobListOutput(jobs []*gitlab.Job) [][]string {
+	var outputs [][]string
 	for _, job := range jobs {
-		output := strings.Join([]string{
+		output := []string{
 			strconv.Itoa(job.ID),
 			job.Status,
 			job.Ref,
@@ -105,7 +117,7 @@ func pipelineJobListOutput(jobs []*gitlab.Job) []string {
 			job.User.Username,
 			job.Stage,
 			job.Name,
-		}, "|")
+		}
 		outputs = append(outputs, output)
 	}
 	return outputs
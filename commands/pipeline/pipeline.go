@@ -8,6 +8,7 @@ import (
 	flags "github.com/jessevdk/go-flags"
 	"github.com/lighttiger2505/lab/commands/internal"
 	"github.com/lighttiger2505/lab/internal/api"
+	"github.com/lighttiger2505/lab/internal/cache"
 	"github.com/lighttiger2505/lab/internal/gitutil"
 	"github.com/lighttiger2505/lab/internal/ui"
 )
@@ -16,38 +17,95 @@ const (
 	ExitCodeOK        int = iota //0
 	ExitCodeError     int = iota //1
 	ExitCodeFileError int = iota //2
+	// ExitCodePipelineFailed is returned when the watched pipeline failed
+	ExitCodePipelineFailed int = iota //3
+	// ExitCodePipelineCanceled is returned when the watched pipeline was
+	// canceled
+	ExitCodePipelineCanceled int = iota //4
 )
 
 type Option struct {
 	ProjectProfileOption *internal.ProjectProfileOption `group:"Project, Profile Options"`
 	ListOption           *ListOption                    `group:"List Options"`
+	ActionOption         *ActionOption                  `group:"Run, Retry, Cancel Options"`
+	WatchOption          *WatchOption                   `group:"Watch Options"`
+	ArtifactsOption      *ArtifactsOption               `group:"Artifacts Options"`
+	GraphOption          *GraphOption                   `group:"Graph Options"`
 	BrowseOption         *BrowseOption                  `group:"Brwose Options"`
+	OutputOption         *internal.OutputOption         `group:"Output Options"`
+	CacheOption          *internal.CacheOption          `group:"Cache Options"`
 }
 
 func newOptionParser(opt *Option) *flags.Parser {
 	opt.ProjectProfileOption = &internal.ProjectProfileOption{}
 	opt.ListOption = &ListOption{}
+	opt.ActionOption = &ActionOption{}
+	opt.WatchOption = &WatchOption{}
+	opt.ArtifactsOption = &ArtifactsOption{}
+	opt.GraphOption = &GraphOption{}
+	opt.OutputOption = &internal.OutputOption{}
+	opt.CacheOption = &internal.CacheOption{}
 	parser := flags.NewParser(opt, flags.HelpFlag|flags.PassDoubleDash)
 	parser.Usage = `pipeline [options]
 
 Synopsis:
   # List pipeline
-  lab pipeline 
+  lab pipeline [--output=<format>]
 
   # Show pipeline
-  lab pipeline <Pipeline ID>
+  lab pipeline <Pipeline ID> [--output=<format>]
+
+  # Run pipeline
+  lab pipeline run [--ref=<ref>] [--var=<key>=<value>...] [--trigger-token=<token>] [-u]
+
+  # Retry or cancel pipeline
+  lab pipeline <Pipeline ID> --retry | --cancel [-u]
+
+  # Watch pipeline until it finishes
+  lab pipeline [<Pipeline ID>] --watch
+
+  # Draw stages and needs of pipeline
+  lab pipeline [<Pipeline ID>] --graph | --dot
+
+  # Download latest artifacts of job
+  lab pipeline [<Pipeline ID>] --artifacts --job=<name> [--output-dir=<dir>]
 `
 	return parser
 }
 
 type ListOption struct {
 	Num     int    `short:"n" long:"num" value-name:"<num>" default:"20" default-mask:"20" description:"Limit the number of pipeline to output."`
+	All     bool   `long:"all" description:"Print all pipelines, ignore the num option."`
 	Sort    string `long:"sort"  value-name:"<sort>" default:"desc" default-mask:"desc" description:"Print pipeline ordered in \"asc\" or \"desc\" order."`
 	Scope   string `short:"c" long:"scope" description:"The scope of pipelines, one of: running, pending, finished, branches, tags"`
 	States  string `short:"t" long:"states" description:" The status of pipelines, one of: running, pending, success, failed, canceled, skipped"`
 	OrderBy string `short:"o" long:"orderby" default:"id" default-mask:"id" description:"Order pipelines by id, status, ref, or user_id"`
 }
 
+type ActionOption struct {
+	Ref       string   `long:"ref" value-name:"<ref>" description:"The branch or tag to run the pipeline for. The current branch by default."`
+	Variables []string `long:"var" value-name:"<key>=<value>" description:"The variable to run the pipeline with. Repeat to give multiple variables."`
+	Token     string   `long:"trigger-token" value-name:"<token>" description:"Run the pipeline with the trigger token, as the owner of the token."`
+	Retry     bool     `long:"retry" description:"Retry the failed and canceled jobs of the pipeline."`
+	Cancel    bool     `long:"cancel" description:"Cancel the running jobs of the pipeline."`
+	URL       bool     `short:"u" long:"url" description:"Print the web url of the pipeline after the id."`
+}
+
+type WatchOption struct {
+	Watch bool `short:"w" long:"watch" description:"Print the jobs of the pipeline until it finishes, and exit with the status of the pipeline. The latest pipeline of the current branch by default."`
+}
+
+type ArtifactsOption struct {
+	Artifacts bool   `long:"artifacts" description:"Download and extract the latest artifacts of the job on the ref of the pipeline. The current branch by default."`
+	Job       string `long:"job" value-name:"<name>" description:"The name of the job to download the artifacts of."`
+	Dir       string `long:"output-dir" value-name:"<dir>" default:"." default-mask:"." description:"The directory to download the artifacts into."`
+}
+
+type GraphOption struct {
+	Graph bool `long:"graph" description:"Draw the stages of the pipeline as columns, and the needs of the jobs. The latest pipeline of the current branch by default."`
+	Dot   bool `long:"dot" description:"Print the graph of the pipeline in the DOT language of Graphviz."`
+}
+
 type BrowseOption struct {
 	Browse bool `short:"b" long:"browse" description:"Browse issue."`
 }
@@ -59,6 +117,8 @@ type PipelineCommand struct {
 	UI              ui.UI
 	RemoteCollecter gitutil.Collecter
 	MethodFactory   MethodFactory
+	// Cache keeps what is read from GitLab for --offline, nil to keep nothing
+	Cache *cache.Store
 }
 
 func (c *PipelineCommand) Synopsis() string {
@@ -78,12 +138,24 @@ func (c *PipelineCommand) Run(args []string) int {
 		return ExitCodeError
 	}
 
+	var subcommand string
+	if len(parseArgs) > 0 && parseArgs[0] == "run" {
+		subcommand = parseArgs[0]
+		parseArgs = parseArgs[1:]
+	}
+
 	iid, err := validIID(parseArgs)
 	if err != nil {
 		c.UI.Error(err.Error())
 		return ExitCodeError
 	}
 
+	// The cache does not change, so the watch would never end
+	if opt.CacheOption.Offline && opt.WatchOption.Watch {
+		c.UI.Error("Cannot watch pipeline offline")
+		return ExitCodeError
+	}
+
 	pInfo, err := c.RemoteCollecter.CollectTarget(
 		opt.ProjectProfileOption.Project,
 		opt.ProjectProfileOption.Profile,
@@ -93,23 +165,27 @@ func (c *PipelineCommand) Run(args []string) int {
 		return ExitCodeError
 	}
 
-	clientFacotry, err := api.NewGitlabClientFactory(pInfo.ApiUrl(), pInfo.Token)
+	clientFacotry, err := api.NewGitlabClientFactory(pInfo.ApiUrl(), pInfo.Token, pInfo.TLS)
 	if err != nil {
 		c.UI.Error(err.Error())
 		return ExitCodeError
 	}
-
-	method := c.MethodFactory.CreateMethod(opt, pInfo, iid, clientFacotry)
-	res, err := method.Process()
+	clientFacotry, err = internal.CacheClientFactory(clientFacotry, c.Cache, pInfo.Domain, opt.CacheOption)
 	if err != nil {
 		c.UI.Error(err.Error())
 		return ExitCodeError
 	}
 
-	if res != "" {
-		c.UI.Message(res)
+	method := c.MethodFactory.CreateMethod(opt, pInfo, subcommand, iid, clientFacotry)
+	opt.OutputOption.Stream(c.UI)
+	res, err := method.Process()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return internal.ExitCode(err, ExitCodeError)
 	}
 
+	opt.OutputOption.Write(c.UI, res)
+
 	return ExitCodeOK
 }
 
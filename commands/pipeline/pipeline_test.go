@@ -69,6 +69,17 @@ func TestPipelineCommand_Run(t *testing.T) {
 			wantOut:  "result\n",
 			wantErr:  "",
 		},
+		{
+			name: "run",
+			fields: fields{
+				RemoteCollecter: mockCollecter,
+				MethodFactory:   mockMethodFactory,
+			},
+			args:     []string{"run", "--ref", "master"},
+			wantCode: 0,
+			wantOut:  "result\n",
+			wantErr:  "",
+		},
 		{
 			name: "invalid args",
 			fields: fields{
@@ -80,6 +91,17 @@ func TestPipelineCommand_Run(t *testing.T) {
 			wantOut:  "",
 			wantErr:  "Invalid args, please intput pipeline id.\n",
 		},
+		{
+			name: "watch offline",
+			fields: fields{
+				RemoteCollecter: mockCollecter,
+				MethodFactory:   mockMethodFactory,
+			},
+			args:     []string{"--watch", "--offline"},
+			wantCode: 1,
+			wantOut:  "",
+			wantErr:  "Cannot watch pipeline offline\n",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,241 @@
+package pipeline
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/git"
+	"github.com/lighttiger2505/lab/internal/api"
+	"github.com/lighttiger2505/lab/internal/ui"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// watchInterval is the interval of reading a running pipeline.
+const watchInterval = 5 * time.Second
+
+type watchMethod struct {
+	client  api.Pipeline
+	ui      ui.UI
+	project string
+	id      int
+	// redraw replaces the printed view with the next one, instead of
+	// printing the views one after another
+	redraw bool
+	sleep  func(time.Duration)
+	now    func() time.Time
+}
+
+func (m *watchMethod) Process() (string, error) {
+	id := m.id
+	if id == 0 {
+		latest, err := latestPipelineID(m.client, m.project)
+		if err != nil {
+			return "", err
+		}
+		id = latest
+	}
+
+	var view string
+	for {
+		// The pipeline is read before the jobs not to miss the last jobs
+		pipeline, err := m.client.GetPipeline(m.project, id)
+		if err != nil {
+			return "", err
+		}
+		jobs, err := m.pipelineJobs(id)
+		if err != nil {
+			return "", err
+		}
+
+		next := watchOutput(pipeline, jobs, m.now())
+		if next != view {
+			if m.redraw && view != "" {
+				// Move the cursor to the top of the last view and clear it
+				m.ui.Message(fmt.Sprintf("\x1b[%dA\x1b[J", strings.Count(view, "\n")+1) + next)
+			} else {
+				m.ui.Message(next)
+			}
+			view = next
+		}
+
+		if !isPipelineRunning(pipeline.Status) {
+			return "", pipelineError(pipeline)
+		}
+		m.sleep(watchInterval)
+	}
+}
+
+// latestPipelineID returns the id of the latest pipeline of the current
+// branch.
+func latestPipelineID(client api.Pipeline, project string) (int, error) {
+	currentBranch, err := git.CurrentBranch()
+	if err != nil {
+		return 0, err
+	}
+
+	var latest gitlab.PipelineList
+	err = client.ProjectPipelines(
+		project,
+		&gitlab.ListProjectPipelinesOptions{
+			Ref:     gitlab.String(currentBranch),
+			OrderBy: gitlab.String("id"),
+			Sort:    gitlab.String("desc"),
+		},
+		1,
+		func(pipelines gitlab.PipelineList) error {
+			latest = append(latest, pipelines...)
+			return nil
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+	if len(latest) == 0 {
+		return 0, fmt.Errorf("Not found pipeline of branch %s", currentBranch)
+	}
+	return latest[0].ID, nil
+}
+
+func (m *watchMethod) pipelineJobs(id int) ([]*gitlab.Job, error) {
+	var jobs []*gitlab.Job
+	err := m.client.ProjectPipelineJobs(
+		m.project,
+		makeListPiplineJobOptions(),
+		id,
+		0,
+		func(page []*gitlab.Job) error {
+			jobs = append(jobs, page...)
+			return nil
+		},
+		api.WithIncludeRetried(),
+	)
+	return jobs, err
+}
+
+func isPipelineRunning(status string) bool {
+	switch status {
+	case "created", "waiting_for_resource", "preparing", "pending", "running":
+		return true
+	}
+	return false
+}
+
+// pipelineError returns the error with the exit code telling the status of a
+// finished pipeline, or nil for the pipelines which did not fail.
+func pipelineError(pipeline *gitlab.Pipeline) error {
+	switch pipeline.Status {
+	case "failed":
+		return &internal.ExitError{
+			Code: ExitCodePipelineFailed,
+			Err:  fmt.Errorf("Pipeline #%d failed", pipeline.ID),
+		}
+	case "canceled":
+		return &internal.ExitError{
+			Code: ExitCodePipelineCanceled,
+			Err:  fmt.Errorf("Pipeline #%d was canceled", pipeline.ID),
+		}
+	}
+	return nil
+}
+
+// watchJob is the latest run of a job, with the number of the retries.
+type watchJob struct {
+	*gitlab.Job
+	retries int
+}
+
+// watchStages groups the jobs by the stage. The stages are ordered as they
+// are run, and the retried jobs are counted in the latest run.
+func watchStages(jobs []*gitlab.Job) ([]string, map[string][]*watchJob) {
+	sorted := make([]*gitlab.Job, len(jobs))
+	copy(sorted, jobs)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	var stages []string
+	stageJobs := map[string][]*watchJob{}
+	for _, job := range sorted {
+		if _, ok := stageJobs[job.Stage]; !ok {
+			stages = append(stages, job.Stage)
+		}
+
+		retried := false
+		for _, j := range stageJobs[job.Stage] {
+			if j.Name == job.Name {
+				j.Job = job
+				j.retries++
+				retried = true
+			}
+		}
+		if !retried {
+			stageJobs[job.Stage] = append(stageJobs[job.Stage], &watchJob{Job: job})
+		}
+	}
+	return stages, stageJobs
+}
+
+func watchOutput(pipeline *gitlab.Pipeline, jobs []*gitlab.Job, now time.Time) string {
+	header := fmt.Sprintf("Pipeline #%d %s  %s  %s", pipeline.ID, statusColor(pipeline.Status), pipeline.Ref, shortSHA(pipeline.Sha))
+
+	stages, stageJobs := watchStages(jobs)
+	var rows [][]string
+	for _, stage := range stages {
+		for i, job := range stageJobs[stage] {
+			row := []string{"", job.Name, statusColor(job.Status), jobDuration(job.Job, now), ""}
+			if i == 0 {
+				row[0] = stage
+			}
+			if job.retries > 0 {
+				row[4] = fmt.Sprintf("retried %d", job.retries)
+			}
+			rows = append(rows, row)
+		}
+	}
+	if len(rows) == 0 {
+		return header
+	}
+	lines := strings.Split(internal.Columnize(rows), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return header + "\n" + strings.Join(lines, "\n")
+}
+
+func statusColor(status string) string {
+	var attr color.Attribute
+	switch status {
+	case "success":
+		attr = color.FgGreen
+	case "failed":
+		attr = color.FgRed
+	case "running":
+		attr = color.FgBlue
+	case "pending", "created":
+		attr = color.FgYellow
+	default:
+		attr = color.FgWhite
+	}
+	return color.New(attr).Sprint(status)
+}
+
+// jobDuration returns the time the job has been run for.
+func jobDuration(job *gitlab.Job, now time.Time) string {
+	if job.StartedAt == nil {
+		return "-"
+	}
+	end := now
+	if job.FinishedAt != nil {
+		end = *job.FinishedAt
+	}
+	return end.Sub(*job.StartedAt).Round(time.Second).String()
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
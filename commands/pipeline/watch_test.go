@@ -0,0 +1,75 @@
+package pipeline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+	"github.com/lighttiger2505/lab/internal/ui"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func Test_watchMethod_Process(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	now := time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(seconds int) *time.Time {
+		t := now.Add(time.Duration(seconds) * time.Second)
+		return &t
+	}
+	snapshots := [][]*gitlab.Job{
+		{
+			{ID: 1, Stage: "build", Name: "compile", Status: "success", StartedAt: at(-100), FinishedAt: at(-40)},
+			{ID: 2, Stage: "test", Name: "unit", Status: "running", StartedAt: at(-30)},
+		},
+		{
+			{ID: 1, Stage: "build", Name: "compile", Status: "success", StartedAt: at(-100), FinishedAt: at(-40)},
+			{ID: 2, Stage: "test", Name: "unit", Status: "failed", StartedAt: at(-30), FinishedAt: at(-20)},
+			{ID: 3, Stage: "test", Name: "unit", Status: "failed", StartedAt: at(-10), FinishedAt: at(-5)},
+			{ID: 4, Stage: "test", Name: "lint", Status: "success", StartedAt: at(-10), FinishedAt: at(-8)},
+		},
+	}
+	statuses := []string{"running", "failed"}
+
+	call := 0
+	client := &api.MockPipelineClient{
+		MockGetPipeline: func(repositoryName string, pid int) (*gitlab.Pipeline, error) {
+			return &gitlab.Pipeline{ID: pid, Status: statuses[call], Ref: "master", Sha: "1a2b3c4d5e6f"}, nil
+		},
+		MockProjectPipelineJobs: func(repositoryName string, opt *gitlab.ListJobsOptions, pid int) ([]*gitlab.Job, error) {
+			jobs := snapshots[call]
+			call++
+			return jobs, nil
+		},
+	}
+
+	mockUI := ui.NewMockUi()
+	m := &watchMethod{
+		client:  client,
+		ui:      mockUI,
+		project: "group/project",
+		id:      12,
+		sleep:   func(time.Duration) {},
+		now:     func() time.Time { return now },
+	}
+	_, err := m.Process()
+	if got, want := internal.ExitCode(err, ExitCodeError), ExitCodePipelineFailed; got != want {
+		t.Errorf("watchMethod.Process() exit code = %d, want %d (%v)", got, want, err)
+	}
+
+	want := `Pipeline #12 running  master  1a2b3c4d
+build  compile  success  1m0s
+test   unit     running  30s
+Pipeline #12 failed  master  1a2b3c4d
+build  compile  success  1m0s
+test   unit     failed   5s    retried 1
+       lint     success  2s
+`
+	if got := mockUI.Writer.String(); got != want {
+		t.Errorf("watchMethod.Process() printed\n%s\nwant\n%s", got, want)
+	}
+}
@@ -10,18 +10,19 @@ import (
 	"github.com/lighttiger2505/lab/internal/api"
 	"github.com/lighttiger2505/lab/internal/gitutil"
 	"github.com/lighttiger2505/lab/internal/ui"
-	"github.com/ryanuber/columnize"
 	gitlab "github.com/xanzy/go-gitlab"
 )
 
 type ProjectCommnadOption struct {
 	ProjectProfileOption *internal.ProjectProfileOption `group:"Project, Profile Options"`
 	OutputOption         *ListProjectOption             `group:"List Options"`
+	OutputFormatOption   *internal.OutputOption         `group:"Output Options"`
 }
 
 func newProjectCommandParser(opt *ProjectCommnadOption) *flags.Parser {
 	opt.ProjectProfileOption = &internal.ProjectProfileOption{}
 	opt.OutputOption = newListProjectOption()
+	opt.OutputFormatOption = &internal.OutputOption{}
 	parser := flags.NewParser(opt, flags.HelpFlag|flags.PassDoubleDash)
 	parser.Usage = "project [options]"
 	return parser
@@ -29,6 +30,7 @@ func newProjectCommandParser(opt *ProjectCommnadOption) *flags.Parser {
 
 type ListProjectOption struct {
 	Num        int    `short:"n" long:"num" value-name:"<num>" default:"20" default-mask:"20" description:"Limit the number of project to output."`
+	All        bool   `long:"all" description:"Print all projects, ignore the num option."`
 	Sort       string `long:"sort"  value-name:"<sort>" default:"desc" default-mask:"desc" description:"Print project ordered in \"asc\" or \"desc\" order."`
 	OrderBy    string `short:"o" long:"orderby" default:"updated_at" default-mask:"updated_at" description:"ordered by id, name, path, created_at, updated_at, or last_activity_at fields"`
 	Owned      bool   `short:"w" long:"owned" description:"Limit by projects owned by the current user"`
@@ -74,30 +76,41 @@ func (c *ProjectCommand) Run(args []string) int {
 		return ExitCodeError
 	}
 
-	if err := c.ClientFactory.Init(pInfo.ApiUrl(), pInfo.Token); err != nil {
+	if err := c.ClientFactory.Init(pInfo.ApiUrl(), pInfo.Token, pInfo.TLS); err != nil {
 		c.UI.Error(err.Error())
 		return ExitCodeError
 	}
 	client := c.ClientFactory.GetProjectClient()
 
-	projects, err := client.Projects(
+	opt.OutputFormatOption.Stream(c.UI)
+	w := opt.OutputFormatOption.NewListWriter()
+	err = client.Projects(
 		makeProjectOptions(opt.OutputOption),
+		internal.Limit(opt.OutputOption.Num, opt.OutputOption.All),
+		func(projects []*gitlab.Project) error {
+			return w.WritePage(projects, func() [][]string {
+				return projectOutput(projects)
+			})
+		},
 	)
 	if err != nil {
 		c.UI.Error(err.Error())
 		return ExitCodeError
 	}
 
-	result := columnize.SimpleFormat(projectOutput(projects))
-	c.UI.Message(result)
+	result, err := w.Flush()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return ExitCodeError
+	}
+	opt.OutputFormatOption.Write(c.UI, result)
 
 	return ExitCodeOK
 }
 
 func makeProjectOptions(listProjectOption *ListProjectOption) *gitlab.ListProjectsOptions {
 	listOption := &gitlab.ListOptions{
-		Page:    1,
-		PerPage: listProjectOption.Num,
+		Page: 1,
 	}
 	listProjectsOptions := &gitlab.ListProjectsOptions{
 		Archived:    gitlab.Bool(false),
@@ -122,13 +135,13 @@ func removeLineBreak(value string) string {
 	return value
 }
 
-func projectOutput(projects []*gitlab.Project) []string {
-	var outputs []string
+func projectOutput(projects []*gitlab.Project) [][]string {
+	var outputs [][]string
 	for _, project := range projects {
-		output := strings.Join([]string{
+		output := []string{
 			fmt.Sprintf("%s/%s", project.Namespace.Name, project.Name),
 			removeLineBreak(project.Description),
-		}, "|")
+		}
 		outputs = append(outputs, output)
 	}
 	return outputs
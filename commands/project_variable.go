@@ -3,25 +3,25 @@ package commands
 import (
 	"bytes"
 	"fmt"
-	"strings"
 
 	flags "github.com/jessevdk/go-flags"
 	"github.com/lighttiger2505/lab/commands/internal"
 	"github.com/lighttiger2505/lab/internal/api"
 	"github.com/lighttiger2505/lab/internal/gitutil"
 	"github.com/lighttiger2505/lab/internal/ui"
-	"github.com/ryanuber/columnize"
 	gitlab "github.com/xanzy/go-gitlab"
 )
 
 type ProjectVaribleCommandOption struct {
 	ProjectProfileOption *internal.ProjectProfileOption    `group:"Project, Profile Options"`
 	CreateUpdateOption   *CreateUpdateProjectVaribleOption `group:"List Options"`
+	OutputOption         *internal.OutputOption            `group:"Output Options"`
 }
 
 func newProjectVaribleOptionParser(opt *ProjectVaribleCommandOption) *flags.Parser {
 	opt.ProjectProfileOption = &internal.ProjectProfileOption{}
 	opt.CreateUpdateOption = newAddProjectVaribleOption()
+	opt.OutputOption = &internal.OutputOption{}
 	parser := flags.NewParser(opt, flags.HelpFlag|flags.PassDoubleDash)
 	parser.Usage = `project-variable - Create and Edit, list a project variable
 
@@ -111,7 +111,7 @@ func (c *ProjectVariableCommand) Run(args []string) int {
 		return ExitCodeError
 	}
 
-	if err := c.ClientFactory.Init(pInfo.ApiUrl(), pInfo.Token); err != nil {
+	if err := c.ClientFactory.Init(pInfo.ApiUrl(), pInfo.Token, pInfo.TLS); err != nil {
 		c.UI.Error(err.Error())
 		return ExitCodeError
 	}
@@ -156,8 +156,14 @@ func (c *ProjectVariableCommand) Run(args []string) int {
 			c.UI.Error(err.Error())
 			return ExitCodeError
 		}
-		result := columnize.SimpleFormat(projectVariableOutput(variables))
-		c.UI.Message(result)
+		result, err := opt.OutputOption.FormatList(variables, func() [][]string {
+			return projectVariableOutput(variables)
+		})
+		if err != nil {
+			c.UI.Error(err.Error())
+			return ExitCodeError
+		}
+		opt.OutputOption.Write(c.UI, result)
 	}
 
 	return ExitCodeOK
@@ -197,13 +203,13 @@ func makeUpdateProjectVariableOption(key, value string) *gitlab.UpdateVariableOp
 	return opt
 }
 
-func projectVariableOutput(variables []*gitlab.ProjectVariable) []string {
-	var outputs []string
+func projectVariableOutput(variables []*gitlab.ProjectVariable) [][]string {
+	var outputs [][]string
 	for _, variable := range variables {
-		output := strings.Join([]string{
+		output := []string{
 			variable.Key,
 			variable.Value,
-		}, "|")
+		}
 		outputs = append(outputs, output)
 	}
 	return outputs
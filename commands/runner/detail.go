@@ -2,13 +2,17 @@ package runner
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/lighttiger2505/lab/commands/internal"
 	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
 )
 
 type detailMethod struct {
 	runnerClient api.Runner
+	output       *internal.OutputOption
 	id           int
 }
 
@@ -17,6 +21,14 @@ func (m *detailMethod) Process() (string, error) {
 	if err != nil {
 		return "", err
 	}
+	return m.output.FormatDetail(
+		detail,
+		func() string { return runnerDetailOutput(detail) },
+		func() [][]string { return runnerDetailRow(detail) },
+	)
+}
+
+func runnerDetailOutput(detail *gitlab.RunnerDetails) string {
 	template := `%d
 Status: %s
 Description: %s
@@ -37,5 +49,19 @@ MaximumTimeout: %d
 		detail.AccessLevel,
 		detail.MaximumTimeout,
 	)
-	return res, nil
+	return res
+}
+
+func runnerDetailRow(detail *gitlab.RunnerDetails) [][]string {
+	return [][]string{
+		{
+			strconv.Itoa(detail.ID),
+			detail.Status,
+			detail.Description,
+			strings.Join(detail.TagList, ","),
+			detail.Version,
+			detail.AccessLevel,
+			strconv.Itoa(detail.MaximumTimeout),
+		},
+	}
 }
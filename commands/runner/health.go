@@ -0,0 +1,304 @@
+package runner
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+const (
+	runnerOnline  = "online"
+	runnerOffline = "offline"
+	runnerStale   = "stale"
+)
+
+type runnerHealth struct {
+	ID           int        `json:"id"`
+	Description  string     `json:"description"`
+	State        string     `json:"state"`
+	Active       bool       `json:"active"`
+	IsShared     bool       `json:"is_shared"`
+	Version      string     `json:"version"`
+	Architecture string     `json:"architecture"`
+	Platform     string     `json:"platform"`
+	Tags         []string   `json:"tags"`
+	ContactedAt  *time.Time `json:"contacted_at"`
+	LastJobAt    *time.Time `json:"last_job_at"`
+	Idle         bool       `json:"idle"`
+}
+
+type tagCoverage struct {
+	Runners int `json:"runners"`
+	Online  int `json:"online"`
+}
+
+type healthReport struct {
+	Total         int                     `json:"total"`
+	Online        int                     `json:"online"`
+	Offline       int                     `json:"offline"`
+	Stale         int                     `json:"stale"`
+	Paused        int                     `json:"paused"`
+	Idle          int                     `json:"idle"`
+	IdleDays      int                     `json:"idle_days"`
+	Versions      map[string]int          `json:"versions"`
+	Architectures map[string]int          `json:"architectures"`
+	Tags          map[string]*tagCoverage `json:"tags"`
+	Runners       []*runnerHealth         `json:"runners"`
+}
+
+type healthMethod struct {
+	runnerClient api.Runner
+	opt          *HealthOption
+	output       *internal.OutputOption
+	now          func() time.Time
+}
+
+func (m *healthMethod) Process() (string, error) {
+	var runners []*gitlab.Runner
+	err := m.runnerClient.ListAllRunners(
+		makeListRunnerOptions(&ListOption{}),
+		0,
+		func(page []*gitlab.Runner) error {
+			runners = append(runners, page...)
+			return nil
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	now := m.now()
+	var healths []*runnerHealth
+	for _, runner := range runners {
+		detail, err := m.runnerClient.GetRunnerDetails(runner.ID)
+		if err != nil {
+			return "", err
+		}
+		lastJobAt, err := m.lastJobAt(runner.ID)
+		if err != nil {
+			return "", err
+		}
+		healths = append(healths, newRunnerHealth(detail, lastJobAt, m.opt, now))
+	}
+
+	report := newHealthReport(healths, m.opt.IdleDays)
+	return m.output.FormatDetail(
+		report,
+		func() string { return healthOutput(report) },
+		func() [][]string { return healthRows(report.Runners) },
+	)
+}
+
+// lastJobAt returns the time the runner picked its latest job. GitLab lists
+// the jobs of a runner from the latest one.
+func (m *healthMethod) lastJobAt(id int) (*time.Time, error) {
+	opt := makeListRunnerJobsOptions(&JobOption{})
+	opt.PerPage = 1
+	var last *time.Time
+	err := m.runnerClient.ListRunnerJobs(id, opt, 1, func(jobs []*gitlab.Job) error {
+		if len(jobs) == 0 {
+			return nil
+		}
+		last = jobs[0].StartedAt
+		if last == nil {
+			last = jobs[0].CreatedAt
+		}
+		return nil
+	})
+	return last, err
+}
+
+func newRunnerHealth(detail *gitlab.RunnerDetails, lastJobAt *time.Time, opt *HealthOption, now time.Time) *runnerHealth {
+	health := &runnerHealth{
+		ID:           detail.ID,
+		Description:  detail.Description,
+		Active:       detail.Active,
+		IsShared:     detail.IsShared,
+		Version:      detail.Version,
+		Architecture: detail.Architecture,
+		Platform:     detail.Platform,
+		Tags:         detail.TagList,
+		ContactedAt:  detail.ContactedAt,
+		LastJobAt:    lastJobAt,
+	}
+	if health.Tags == nil {
+		health.Tags = []string{}
+	}
+
+	switch {
+	case detail.ContactedAt == nil || detail.ContactedAt.Before(daysAgo(now, opt.StaleDays)):
+		health.State = runnerStale
+	case detail.Online:
+		health.State = runnerOnline
+	default:
+		health.State = runnerOffline
+	}
+	health.Idle = lastJobAt == nil || lastJobAt.Before(daysAgo(now, opt.IdleDays))
+	return health
+}
+
+func daysAgo(now time.Time, days int) time.Time {
+	return now.AddDate(0, 0, -days)
+}
+
+func newHealthReport(healths []*runnerHealth, idleDays int) *healthReport {
+	report := &healthReport{
+		Total:         len(healths),
+		IdleDays:      idleDays,
+		Versions:      map[string]int{},
+		Architectures: map[string]int{},
+		Tags:          map[string]*tagCoverage{},
+		Runners:       healths,
+	}
+	for _, health := range healths {
+		switch health.State {
+		case runnerOnline:
+			report.Online++
+		case runnerOffline:
+			report.Offline++
+		case runnerStale:
+			report.Stale++
+		}
+		if !health.Active {
+			report.Paused++
+		}
+		if health.Idle {
+			report.Idle++
+		}
+		report.Versions[unknownIfEmpty(health.Version)]++
+		report.Architectures[unknownIfEmpty(health.Architecture)]++
+		for _, tag := range health.Tags {
+			coverage, ok := report.Tags[tag]
+			if !ok {
+				coverage = &tagCoverage{}
+				report.Tags[tag] = coverage
+			}
+			coverage.Runners++
+			if health.State == runnerOnline && health.Active {
+				coverage.Online++
+			}
+		}
+	}
+	return report
+}
+
+func unknownIfEmpty(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
+
+func healthOutput(report *healthReport) string {
+	sections := []string{
+		fmt.Sprintf(
+			"Runners: %d (online %d, offline %d, stale %d, paused %d)",
+			report.Total,
+			report.Online,
+			report.Offline,
+			report.Stale,
+			report.Paused,
+		),
+	}
+	if report.Total == 0 {
+		return sections[0]
+	}
+
+	sections = append(sections, "Versions:\n"+indent(internal.Columnize(countRows(report.Versions))))
+	sections = append(sections, "Architectures:\n"+indent(internal.Columnize(countRows(report.Architectures))))
+
+	if len(report.Tags) > 0 {
+		var tags []string
+		for tag := range report.Tags {
+			tags = append(tags, tag)
+		}
+		sort.Strings(tags)
+		var rows [][]string
+		for _, tag := range tags {
+			coverage := report.Tags[tag]
+			rows = append(rows, []string{
+				tag,
+				fmt.Sprintf("%d/%d online", coverage.Online, coverage.Runners),
+			})
+		}
+		sections = append(sections, "Tags:\n"+indent(internal.Columnize(rows)))
+	}
+
+	var idleRows [][]string
+	for _, health := range report.Runners {
+		if health.Idle {
+			idleRows = append(idleRows, []string{
+				strconv.Itoa(health.ID),
+				health.Description,
+				"last job " + formatTime(health.LastJobAt),
+			})
+		}
+	}
+	if len(idleRows) > 0 {
+		sections = append(sections, fmt.Sprintf("No job for %d days:\n", report.IdleDays)+indent(internal.Columnize(idleRows)))
+	}
+
+	sections = append(sections, "Runners:\n"+indent(internal.Columnize(healthRows(report.Runners))))
+	return strings.Join(sections, "\n\n")
+}
+
+// countRows returns the rows of the counts, from the largest one.
+func countRows(counts map[string]int) [][]string {
+	var names []string
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	var rows [][]string
+	for _, name := range names {
+		rows = append(rows, []string{name, strconv.Itoa(counts[name])})
+	}
+	return rows
+}
+
+func healthRows(healths []*runnerHealth) [][]string {
+	var rows [][]string
+	for _, health := range healths {
+		state := health.State
+		if !health.Active {
+			state += " paused"
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(health.ID),
+			state,
+			health.Version,
+			health.Architecture,
+			strings.Join(health.Tags, ","),
+			formatTime(health.ContactedAt),
+			formatTime(health.LastJobAt),
+			health.Description,
+		})
+	}
+	return rows
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "never"
+	}
+	return t.In(time.Local).Format("2006-01-02 15:04")
+}
+
+func indent(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("  "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,104 @@
+package runner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func Test_healthMethod_Process(t *testing.T) {
+	now := time.Date(2018, 4, 10, 12, 0, 0, 0, time.Local)
+	hoursAgo := func(hours int) *time.Time {
+		t := now.Add(-time.Duration(hours) * time.Hour)
+		return &t
+	}
+	details := map[int]*gitlab.RunnerDetails{
+		1: {ID: 1, Description: "docker-1", Active: true, Online: true, ContactedAt: hoursAgo(0), Version: "10.6.0", Architecture: "amd64", TagList: []string{"docker", "linux"}},
+		2: {ID: 2, Description: "docker-2", Active: false, Online: true, ContactedAt: hoursAgo(0), Version: "10.6.0", Architecture: "amd64", TagList: []string{"docker"}},
+		3: {ID: 3, Description: "windows", Active: true, Online: false, ContactedAt: hoursAgo(48), Version: "10.5.0", Architecture: "amd64", TagList: []string{"windows"}},
+		4: {ID: 4, Description: "arm", Active: true, Online: false, ContactedAt: hoursAgo(24 * 100), Version: "10.6.0", Architecture: "arm64"},
+	}
+	lastJobs := map[int][]*gitlab.Job{
+		1: {{ID: 100, StartedAt: hoursAgo(1)}},
+		2: {{ID: 90, CreatedAt: hoursAgo(24 * 3)}},
+		3: {{ID: 80, StartedAt: hoursAgo(24 * 8)}},
+	}
+	client := &api.MockRunnerClient{
+		MockListAllRunners: func(opt *gitlab.ListRunnersOptions) ([]*gitlab.Runner, error) {
+			return []*gitlab.Runner{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}}, nil
+		},
+		MockGetRunnerDetails: func(id int) (*gitlab.RunnerDetails, error) {
+			return details[id], nil
+		},
+		MockListRunnerJobs: func(id int, opt *gitlab.ListRunnerJobsOptions) ([]*gitlab.Job, error) {
+			if opt.PerPage != 1 {
+				t.Errorf("ListRunnerJobs() per page = %d, want 1", opt.PerPage)
+			}
+			return lastJobs[id], nil
+		},
+	}
+
+	m := &healthMethod{
+		runnerClient: client,
+		opt:          &HealthOption{Health: true, StaleDays: 90, IdleDays: 7},
+		output:       &internal.OutputOption{},
+		now:          func() time.Time { return now },
+	}
+	got, err := m.Process()
+	if err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	want := `Runners: 4 (online 2, offline 1, stale 1, paused 1)
+
+Versions:
+  10.6.0  3
+  10.5.0  1
+
+Architectures:
+  amd64  3
+  arm64  1
+
+Tags:
+  docker   1/2 online
+  linux    1/1 online
+  windows  0/1 online
+
+No job for 7 days:
+  3  windows  last job 2018-04-02 12:00
+  4  arm      last job never
+
+Runners:
+  1  online         10.6.0  amd64  docker,linux  2018-04-10 12:00  2018-04-10 11:00  docker-1
+  2  online paused  10.6.0  amd64  docker        2018-04-10 12:00  2018-04-07 12:00  docker-2
+  3  offline        10.5.0  amd64  windows       2018-04-08 12:00  2018-04-02 12:00  windows
+  4  stale          10.6.0  arm64                2017-12-31 12:00  never             arm`
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("invalide arg (-got +want)\n%s", diff)
+	}
+}
+
+func Test_newHealthReport(t *testing.T) {
+	healths := []*runnerHealth{
+		{ID: 1, State: runnerOnline, Active: true, Version: "10.6.0", Tags: []string{"docker"}},
+		{ID: 2, State: runnerOffline, Active: true, Idle: true, Tags: []string{"docker"}},
+	}
+	got := newHealthReport(healths, 7)
+	want := &healthReport{
+		Total:         2,
+		Online:        1,
+		Offline:       1,
+		Idle:          1,
+		IdleDays:      7,
+		Versions:      map[string]int{"10.6.0": 1, "unknown": 1},
+		Architectures: map[string]int{"unknown": 2},
+		Tags:          map[string]*tagCoverage{"docker": {Runners: 2, Online: 1}},
+		Runners:       healths,
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("invalide arg (-got +want)\n%s", diff)
+	}
+}
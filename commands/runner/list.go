@@ -2,35 +2,63 @@ package runner
 
 import (
 	"strconv"
-	"strings"
 
+	"github.com/lighttiger2505/lab/commands/internal"
 	"github.com/lighttiger2505/lab/internal/api"
-	"github.com/ryanuber/columnize"
 	gitlab "github.com/xanzy/go-gitlab"
 )
 
 type listMethod struct {
 	runnerClient api.Runner
 	opt          *ListOption
+	output       *internal.OutputOption
 	project      string
 }
 
 func (m *listMethod) Process() (string, error) {
-	runners, err := m.runnerClient.ListProjectRunners(
+	w := m.output.NewListWriter()
+	err := m.runnerClient.ListProjectRunners(
 		m.project,
 		makeListProjectRunnerOptions(m.opt),
+		internal.Limit(m.opt.Num, m.opt.All),
+		func(runners []*gitlab.Runner) error {
+			return w.WritePage(runners, func() [][]string {
+				return listRunnerOutput(runners)
+			})
+		},
 	)
 	if err != nil {
 		return "", err
 	}
-	result := columnize.SimpleFormat(listRunnerOutput(runners))
-	return result, nil
+	return w.Flush()
+}
+
+type listAllMethod struct {
+	runnerClient api.Runner
+	opt          *ListOption
+	output       *internal.OutputOption
+}
+
+func (m *listAllMethod) Process() (string, error) {
+	w := m.output.NewListWriter()
+	err := m.runnerClient.ListAllRunners(
+		makeListRunnerOptions(m.opt),
+		internal.Limit(m.opt.Num, m.opt.All),
+		func(runners []*gitlab.Runner) error {
+			return w.WritePage(runners, func() [][]string {
+				return listRunnerOutput(runners)
+			})
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	return w.Flush()
 }
 
 func makeListRunnerOptions(opt *ListOption) *gitlab.ListRunnersOptions {
 	listOption := &gitlab.ListOptions{
-		Page:    1,
-		PerPage: opt.Num,
+		Page: 1,
 	}
 	listRunnersOptions := &gitlab.ListRunnersOptions{
 		ListOptions: *listOption,
@@ -43,8 +71,7 @@ func makeListRunnerOptions(opt *ListOption) *gitlab.ListRunnersOptions {
 
 func makeListProjectRunnerOptions(opt *ListOption) *gitlab.ListProjectRunnersOptions {
 	listOption := &gitlab.ListOptions{
-		Page:    1,
-		PerPage: opt.Num,
+		Page: 1,
 	}
 	listProjectRunnersOptions := &gitlab.ListProjectRunnersOptions{
 		ListOptions: *listOption,
@@ -55,15 +82,76 @@ func makeListProjectRunnerOptions(opt *ListOption) *gitlab.ListProjectRunnersOpt
 	return listProjectRunnersOptions
 }
 
-func listRunnerOutput(runners []*gitlab.Runner) []string {
-	var outputs []string
+func listRunnerOutput(runners []*gitlab.Runner) [][]string {
+	var outputs [][]string
 	for _, runner := range runners {
-		output := strings.Join([]string{
+		output := []string{
 			strconv.Itoa(runner.ID),
 			runner.Name,
 			runner.Description,
 			runner.Status,
-		}, "|")
+		}
+		outputs = append(outputs, output)
+	}
+	return outputs
+}
+
+type listJobMethod struct {
+	runnerClient api.Runner
+	listOpt      *ListOption
+	opt          *JobOption
+	output       *internal.OutputOption
+	id           int
+}
+
+func (m *listJobMethod) Process() (string, error) {
+	w := m.output.NewListWriter()
+	err := m.runnerClient.ListRunnerJobs(
+		m.id,
+		makeListRunnerJobsOptions(m.opt),
+		m.listOpt.Num,
+		func(jobs []*gitlab.Job) error {
+			return w.WritePage(jobs, func() [][]string {
+				return runnerJobOutput(jobs)
+			})
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	return w.Flush()
+}
+
+func makeListRunnerJobsOptions(opt *JobOption) *gitlab.ListRunnerJobsOptions {
+	listRunnerJobsOptions := &gitlab.ListRunnerJobsOptions{
+		ListOptions: gitlab.ListOptions{
+			Page: 1,
+		},
+	}
+	if opt.Status != "" {
+		listRunnerJobsOptions.Status = gitlab.String(opt.Status)
+	}
+	return listRunnerJobsOptions
+}
+
+// runnerJobOutput prints the jobs with their web url, which tells the project
+// of the job.
+func runnerJobOutput(jobs []*gitlab.Job) [][]string {
+	var outputs [][]string
+	for _, job := range jobs {
+		commit := ""
+		if job.Commit != nil {
+			commit = job.Commit.ShortID
+		}
+		output := []string{
+			strconv.Itoa(job.ID),
+			job.Status,
+			job.Ref,
+			commit,
+			job.Stage,
+			job.Name,
+			job.WebURL,
+		}
 		outputs = append(outputs, output)
 	}
 	return outputs
@@ -0,0 +1,34 @@
+package runner
+
+import (
+	"strconv"
+
+	"github.com/lighttiger2505/lab/internal/api"
+)
+
+type enableMethod struct {
+	runnerClient api.Runner
+	project      string
+	id           int
+}
+
+func (m *enableMethod) Process() (string, error) {
+	runner, err := m.runnerClient.EnableProjectRunner(m.project, m.id)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(runner.ID), nil
+}
+
+type disableMethod struct {
+	runnerClient api.Runner
+	project      string
+	id           int
+}
+
+func (m *disableMethod) Process() (string, error) {
+	if err := m.runnerClient.DisableProjectRunner(m.project, m.id); err != nil {
+		return "", err
+	}
+	return "", nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"strconv"
+	"time"
 
 	flags "github.com/jessevdk/go-flags"
 	"github.com/lighttiger2505/lab/commands/internal"
@@ -21,22 +22,100 @@ const (
 type Option struct {
 	ProjectProfileOption *internal.ProjectProfileOption `group:"Project, Profile Options"`
 	ListOption           *ListOption                    `group:"List Options"`
+	UpdateOption         *UpdateOption                  `group:"Register, Update Options"`
+	ProjectOption        *ProjectOption                 `group:"Enable, Disable Options"`
+	JobOption            *JobOption                     `group:"Job Options"`
+	HealthOption         *HealthOption                  `group:"Health Options"`
 	DeleteOption         *DeleteOption                  `group:"Delete Options"`
+	OutputOption         *internal.OutputOption         `group:"Output Options"`
 }
 
 func newParser(opt *Option) *flags.Parser {
 	opt.ProjectProfileOption = &internal.ProjectProfileOption{}
 	opt.ListOption = newListRunnerOption()
+	opt.UpdateOption = &UpdateOption{}
+	opt.ProjectOption = &ProjectOption{}
+	opt.JobOption = &JobOption{}
+	opt.HealthOption = &HealthOption{}
+	opt.DeleteOption = &DeleteOption{}
+	opt.OutputOption = &internal.OutputOption{}
 	parser := flags.NewParser(opt, flags.HelpFlag|flags.PassDoubleDash)
-	parser.Usage = "project [options]"
+	parser.Usage = `runner [options]
+
+Synopsis:
+  # List runner of project, or all runner of instance
+  lab runner [-n <num>] [--all] [--scope=<scope>] [--output=<format>]
+
+  # Show runner
+  lab runner <runner id> [--output=<format>]
+
+  # Register runner
+  lab runner --register --registration-token=<token> [--description=<description>] [--tags=<tags>] [--run-untagged] [--locked] [--max-timeout=<seconds>]
+
+  # Update runner
+  lab runner <runner id> [--pause | --resume] [--description=<description>] [--tags=<tags>] [--run-untagged[=false]] [--locked[=false]] [--max-timeout=<seconds>]
+
+  # Enable or disable runner on project
+  lab runner <runner id> --enable | --disable
+
+  # List jobs processed by runner
+  lab runner <runner id> --jobs [--status=<status>]
+
+  # Delete runner
+  lab runner <runner id> -D
+
+  # Report health of all runner
+  lab runner --health [--stale-days=<days>] [--idle-days=<days>] [--output=<format>]`
 	return parser
 }
 
 type ListOption struct {
 	Num   int    `short:"n" long:"num" value-name:"<num>" default:"20" default-mask:"20" description:"Limit the number of runner to output."`
+	All   bool   `long:"all" description:"Print all runners of the GitLab instance instead of the project, ignore the num option. It needs an administrator."`
 	Scope string `long:"scope" value-name:"<scope>" description:"Print only given scope. \"active\", \"paused\", \"online\" \"offline\"."`
 }
 
+type UpdateOption struct {
+	Register          bool   `long:"register" description:"Register a runner, and print its id and token."`
+	RegistrationToken string `long:"registration-token" value-name:"<token>" description:"The registration token of the instance, group or project to register the runner to."`
+	Pause             bool   `long:"pause" description:"Pause the runner not to pick jobs."`
+	Resume            bool   `long:"resume" description:"Resume the paused runner."`
+	Description       string `long:"description" value-name:"<description>" description:"The description of the runner."`
+	Tags              string `long:"tags" value-name:"<tags>" description:"The comma separated tags of the runner, e.g. \"docker,linux\"."`
+	RunUntagged       string `long:"run-untagged" value-name:"<bool>" optional:"yes" optional-value:"true" description:"Let the runner pick the jobs without tags."`
+	Locked            string `long:"locked" value-name:"<bool>" optional:"yes" optional-value:"true" description:"Lock the runner to the current projects."`
+	MaxTimeout        int    `long:"max-timeout" value-name:"<seconds>" description:"The maximum timeout of the jobs run by the runner in seconds."`
+}
+
+func (o *UpdateOption) hasUpdate() bool {
+	if o.Pause ||
+		o.Resume ||
+		o.Description != "" ||
+		o.Tags != "" ||
+		o.RunUntagged != "" ||
+		o.Locked != "" ||
+		o.MaxTimeout > 0 {
+		return true
+	}
+	return false
+}
+
+type ProjectOption struct {
+	Enable  bool `long:"enable" description:"Enable the runner on the project."`
+	Disable bool `long:"disable" description:"Disable the runner on the project."`
+}
+
+type JobOption struct {
+	Jobs   bool   `long:"jobs" description:"Print the jobs processed by the runner."`
+	Status string `long:"status" value-name:"<status>" description:"Print only the jobs of the status. \"running\", \"success\", \"failed\", \"canceled\"."`
+}
+
+type HealthOption struct {
+	Health    bool `long:"health" description:"Report the health of every runner of the GitLab instance. It needs an administrator."`
+	StaleDays int  `long:"stale-days" value-name:"<days>" default:"90" default-mask:"90" description:"Report the runners not contacted for the days as stale."`
+	IdleDays  int  `long:"idle-days" value-name:"<days>" default:"7" default-mask:"7" description:"Report the runners which have not picked a job for the days."`
+}
+
 type DeleteOption struct {
 	Delete bool `short:"D" long:"delete" description:"delete registed runner."`
 }
@@ -52,7 +131,7 @@ type RunnerCommand struct {
 }
 
 func (c *RunnerCommand) Synopsis() string {
-	return "List CI/CD Runner"
+	return "List and Manage CI/CD Runner"
 }
 
 var opt Option
@@ -86,26 +165,41 @@ func (c *RunnerCommand) Run(args []string) int {
 		return ExitCodeError
 	}
 
-	if err := c.ClientFactory.Init(pInfo.ApiUrl(), pInfo.Token); err != nil {
+	if err := c.ClientFactory.Init(pInfo.ApiUrl(), pInfo.Token, pInfo.TLS); err != nil {
 		c.UI.Error(err.Error())
 		return ExitCodeError
 	}
 
 	method := c.createMethod(id, opt, pInfo)
+	opt.OutputOption.Stream(c.UI)
 	res, err := method.Process()
 	if err != nil {
 		c.UI.Error(err.Error())
 		return ExitCodeError
 	}
 
-	if res != "" {
-		c.UI.Message(res)
-	}
+	opt.OutputOption.Write(c.UI, res)
 
 	return ExitCodeOK
 }
 
 func (c *RunnerCommand) createMethod(id int, opt Option, pInfo *gitutil.GitLabProjectInfo) internal.Method {
+	if opt.HealthOption.Health {
+		return &healthMethod{
+			runnerClient: c.ClientFactory.GetRunnerClient(),
+			opt:          opt.HealthOption,
+			output:       opt.OutputOption,
+			now:          time.Now,
+		}
+	}
+
+	if opt.UpdateOption.Register {
+		return &registerMethod{
+			runnerClient: c.ClientFactory.GetRunnerClient(),
+			opt:          opt.UpdateOption,
+		}
+	}
+
 	if id > 0 {
 		if opt.DeleteOption.Delete {
 			return &deleteMethod{
@@ -114,15 +208,54 @@ func (c *RunnerCommand) createMethod(id int, opt Option, pInfo *gitutil.GitLabPr
 				id:           id,
 			}
 		}
+		if opt.ProjectOption.Enable {
+			return &enableMethod{
+				runnerClient: c.ClientFactory.GetRunnerClient(),
+				project:      pInfo.Project,
+				id:           id,
+			}
+		}
+		if opt.ProjectOption.Disable {
+			return &disableMethod{
+				runnerClient: c.ClientFactory.GetRunnerClient(),
+				project:      pInfo.Project,
+				id:           id,
+			}
+		}
+		if opt.JobOption.Jobs {
+			return &listJobMethod{
+				runnerClient: c.ClientFactory.GetRunnerClient(),
+				listOpt:      opt.ListOption,
+				opt:          opt.JobOption,
+				output:       opt.OutputOption,
+				id:           id,
+			}
+		}
+		if opt.UpdateOption.hasUpdate() {
+			return &updateMethod{
+				runnerClient: c.ClientFactory.GetRunnerClient(),
+				opt:          opt.UpdateOption,
+				id:           id,
+			}
+		}
 		return &detailMethod{
 			runnerClient: c.ClientFactory.GetRunnerClient(),
+			output:       opt.OutputOption,
 			id:           id,
 		}
 	}
 
+	if opt.ListOption.All {
+		return &listAllMethod{
+			runnerClient: c.ClientFactory.GetRunnerClient(),
+			opt:          opt.ListOption,
+			output:       opt.OutputOption,
+		}
+	}
 	return &listMethod{
 		runnerClient: c.ClientFactory.GetRunnerClient(),
 		opt:          opt.ListOption,
+		output:       opt.OutputOption,
 		project:      pInfo.Project,
 	}
 }
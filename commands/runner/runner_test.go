@@ -0,0 +1,179 @@
+package runner
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/lighttiger2505/lab/internal/api"
+	"github.com/lighttiger2505/lab/internal/gitutil"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func Test_makeUpdateRunnerDetailsOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    *gitlab.UpdateRunnerDetailsOptions
+		wantErr bool
+	}{
+		{
+			name: "pause",
+			args: []string{"--pause"},
+			want: &gitlab.UpdateRunnerDetailsOptions{Active: gitlab.Bool(false)},
+		},
+		{
+			name: "resume",
+			args: []string{"--resume"},
+			want: &gitlab.UpdateRunnerDetailsOptions{Active: gitlab.Bool(true)},
+		},
+		{
+			name: "all",
+			args: []string{
+				"--description", "docker runner",
+				"--tags", "docker, linux",
+				"--run-untagged",
+				"--locked=false",
+				"--max-timeout", "3600",
+			},
+			want: &gitlab.UpdateRunnerDetailsOptions{
+				Description:    gitlab.String("docker runner"),
+				TagList:        []string{"docker", "linux"},
+				RunUntagged:    gitlab.Bool(true),
+				Locked:         gitlab.Bool(false),
+				MaximumTimeout: gitlab.Int(3600),
+			},
+		},
+		{
+			name:    "pause and resume",
+			args:    []string{"--pause", "--resume"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid bool",
+			args:    []string{"--locked=yes please"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opt Option
+			if _, err := newParser(&opt).ParseArgs(tt.args); err != nil {
+				t.Fatalf("ParseArgs() error = %v", err)
+			}
+			got, err := makeUpdateRunnerDetailsOptions(opt.UpdateOption)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("makeUpdateRunnerDetailsOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("invalide arg (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_registerMethod_Process(t *testing.T) {
+	m := &registerMethod{
+		runnerClient: &api.MockRunnerClient{
+			MockRegisterRunner: func(opt *gitlab.RegisterNewRunnerOptions) (*gitlab.Runner, error) {
+				want := &gitlab.RegisterNewRunnerOptions{
+					Token:   gitlab.String("registration"),
+					TagList: []string{"docker"},
+					Locked:  gitlab.Bool(true),
+				}
+				if diff := cmp.Diff(opt, want); diff != "" {
+					t.Errorf("invalide arg (-got +want)\n%s", diff)
+				}
+				return &gitlab.Runner{ID: 8, Token: "runner-token"}, nil
+			},
+		},
+		opt: &UpdateOption{
+			Register:          true,
+			RegistrationToken: "registration",
+			Tags:              "docker",
+			Locked:            "true",
+		},
+	}
+	got, err := m.Process()
+	if err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if got != "8 runner-token" {
+		t.Errorf("Process() = %q, want %q", got, "8 runner-token")
+	}
+
+	m.opt.RegistrationToken = ""
+	if _, err := m.Process(); err == nil {
+		t.Errorf("Process() want error without registration token")
+	}
+}
+
+func TestRunnerCommand_createMethod(t *testing.T) {
+	pInfo := &gitutil.GitLabProjectInfo{Project: "group/project"}
+	c := &RunnerCommand{
+		ClientFactory: &api.MockAPIClientFactory{
+			MockGetRunnerClient: func() api.Runner { return &api.MockRunnerClient{} },
+		},
+	}
+	tests := []struct {
+		name string
+		args []string
+		id   int
+		want interface{}
+	}{
+		{name: "list", want: &listMethod{}},
+		{name: "list all", args: []string{"--all"}, want: &listAllMethod{}},
+		{name: "detail", id: 8, want: &detailMethod{}},
+		{name: "delete", args: []string{"-D"}, id: 8, want: &deleteMethod{}},
+		{name: "enable", args: []string{"--enable"}, id: 8, want: &enableMethod{}},
+		{name: "disable", args: []string{"--disable"}, id: 8, want: &disableMethod{}},
+		{name: "jobs", args: []string{"--jobs"}, id: 8, want: &listJobMethod{}},
+		{name: "update", args: []string{"--pause"}, id: 8, want: &updateMethod{}},
+		{name: "register", args: []string{"--register"}, want: &registerMethod{}},
+		{name: "health", args: []string{"--health"}, want: &healthMethod{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opt Option
+			if _, err := newParser(&opt).ParseArgs(tt.args); err != nil {
+				t.Fatalf("ParseArgs() error = %v", err)
+			}
+			got := c.createMethod(tt.id, opt, pInfo)
+			if gotType, wantType := typeName(got), typeName(tt.want); gotType != wantType {
+				t.Errorf("createMethod() = %s, want %s", gotType, wantType)
+			}
+		})
+	}
+}
+
+func typeName(v interface{}) string {
+	return fmt.Sprintf("%T", v)
+}
+
+func Test_runnerJobOutput(t *testing.T) {
+	jobs := []*gitlab.Job{
+		{
+			ID:     12,
+			Status: "success",
+			Ref:    "master",
+			Commit: &gitlab.Commit{ShortID: "0ff3ae19"},
+			Stage:  "test",
+			Name:   "unit",
+			WebURL: "https://gitlab.com/group/project/-/jobs/12",
+		},
+		{
+			ID:     13,
+			Status: "running",
+			Ref:    "develop",
+			Stage:  "build",
+			Name:   "image",
+		},
+	}
+	want := [][]string{
+		{"12", "success", "master", "0ff3ae19", "test", "unit", "https://gitlab.com/group/project/-/jobs/12"},
+		{"13", "running", "develop", "", "build", "image", ""},
+	}
+	if diff := cmp.Diff(runnerJobOutput(jobs), want); diff != "" {
+		t.Errorf("invalide arg (-got +want)\n%s", diff)
+	}
+}
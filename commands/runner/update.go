@@ -0,0 +1,126 @@
+package runner
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+type registerMethod struct {
+	runnerClient api.Runner
+	opt          *UpdateOption
+}
+
+func (m *registerMethod) Process() (string, error) {
+	registerOption, err := makeRegisterNewRunnerOptions(m.opt)
+	if err != nil {
+		return "", err
+	}
+	runner, err := m.runnerClient.RegisterRunner(registerOption)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d %s", runner.ID, runner.Token), nil
+}
+
+func makeRegisterNewRunnerOptions(opt *UpdateOption) (*gitlab.RegisterNewRunnerOptions, error) {
+	if opt.RegistrationToken == "" {
+		return nil, fmt.Errorf("Please input the registration token by --registration-token")
+	}
+	if opt.Pause && opt.Resume {
+		return nil, fmt.Errorf("Please input either the pause or the resume option")
+	}
+	registerOption := &gitlab.RegisterNewRunnerOptions{
+		Token: gitlab.String(opt.RegistrationToken),
+	}
+	if opt.Description != "" {
+		registerOption.Description = gitlab.String(opt.Description)
+	}
+	if opt.Pause {
+		registerOption.Active = gitlab.Bool(false)
+	}
+	if opt.Tags != "" {
+		registerOption.TagList = internal.SplitLabels([]string{opt.Tags})
+	}
+	runUntagged, err := parseBool("run-untagged", opt.RunUntagged)
+	if err != nil {
+		return nil, err
+	}
+	registerOption.RunUntagged = runUntagged
+	locked, err := parseBool("locked", opt.Locked)
+	if err != nil {
+		return nil, err
+	}
+	registerOption.Locked = locked
+	if opt.MaxTimeout > 0 {
+		registerOption.MaximumTimeout = gitlab.Int(opt.MaxTimeout)
+	}
+	return registerOption, nil
+}
+
+type updateMethod struct {
+	runnerClient api.Runner
+	opt          *UpdateOption
+	id           int
+}
+
+func (m *updateMethod) Process() (string, error) {
+	updateOption, err := makeUpdateRunnerDetailsOptions(m.opt)
+	if err != nil {
+		return "", err
+	}
+	runner, err := m.runnerClient.UpdateRunner(m.id, updateOption)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(runner.ID), nil
+}
+
+func makeUpdateRunnerDetailsOptions(opt *UpdateOption) (*gitlab.UpdateRunnerDetailsOptions, error) {
+	if opt.Pause && opt.Resume {
+		return nil, fmt.Errorf("Please input either the pause or the resume option")
+	}
+	updateOption := &gitlab.UpdateRunnerDetailsOptions{}
+	if opt.Description != "" {
+		updateOption.Description = gitlab.String(opt.Description)
+	}
+	if opt.Pause {
+		updateOption.Active = gitlab.Bool(false)
+	}
+	if opt.Resume {
+		updateOption.Active = gitlab.Bool(true)
+	}
+	if opt.Tags != "" {
+		updateOption.TagList = internal.SplitLabels([]string{opt.Tags})
+	}
+	runUntagged, err := parseBool("run-untagged", opt.RunUntagged)
+	if err != nil {
+		return nil, err
+	}
+	updateOption.RunUntagged = runUntagged
+	locked, err := parseBool("locked", opt.Locked)
+	if err != nil {
+		return nil, err
+	}
+	updateOption.Locked = locked
+	if opt.MaxTimeout > 0 {
+		updateOption.MaximumTimeout = gitlab.Int(opt.MaxTimeout)
+	}
+	return updateOption, nil
+}
+
+// parseBool parses the value of an option which is true without a value,
+// e.g. "--locked" or "--locked=false". It returns nil for an empty value.
+func parseBool(name, value string) (*bool, error) {
+	if value == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid value of --%s %s, please input true or false", name, value)
+	}
+	return gitlab.Bool(b), nil
+}
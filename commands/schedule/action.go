@@ -0,0 +1,44 @@
+package schedule
+
+import (
+	"github.com/lighttiger2505/lab/internal/api"
+)
+
+type takeOwnershipMethod struct {
+	client  api.PipelineSchedule
+	project string
+	id      int
+}
+
+func (m *takeOwnershipMethod) Process() (string, error) {
+	if _, err := m.client.TakeOwnership(m.project, m.id); err != nil {
+		return "", err
+	}
+	return "", nil
+}
+
+type runMethod struct {
+	client  api.PipelineSchedule
+	project string
+	id      int
+}
+
+func (m *runMethod) Process() (string, error) {
+	if err := m.client.RunSchedule(m.project, m.id); err != nil {
+		return "", err
+	}
+	return "", nil
+}
+
+type deleteMethod struct {
+	client  api.PipelineSchedule
+	project string
+	id      int
+}
+
+func (m *deleteMethod) Process() (string, error) {
+	if err := m.client.DeleteSchedule(m.project, m.id); err != nil {
+		return "", err
+	}
+	return "", nil
+}
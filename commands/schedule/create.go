@@ -0,0 +1,175 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lighttiger2505/lab/git"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+type createMethod struct {
+	client   api.PipelineSchedule
+	opt      *CreateUpdateOption
+	variable *VariableOption
+	project  string
+}
+
+func (m *createMethod) Process() (string, error) {
+	if m.opt.Description == "" || m.opt.Cron == "" {
+		return "", fmt.Errorf("Please input the description and the cron of the pipeline schedule")
+	}
+	variables, err := parseVariables(m.variable.Variables)
+	if err != nil {
+		return "", err
+	}
+
+	ref := m.opt.Ref
+	if ref == "" {
+		// Schedule the pipeline of the current branch when non specific flags
+		currentBranch, err := git.CurrentBranch()
+		if err != nil {
+			return "", err
+		}
+		ref = currentBranch
+	}
+
+	schedule, err := m.client.CreateSchedule(m.project, makeCreatePipelineScheduleOptions(m.opt, ref))
+	if err != nil {
+		return "", err
+	}
+	for _, variable := range variables {
+		_, err := m.client.CreateScheduleVariable(m.project, schedule.ID, &gitlab.CreatePipelineScheduleVariableOptions{
+			Key:   gitlab.String(variable.Key),
+			Value: gitlab.String(variable.Value),
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	return strconv.Itoa(schedule.ID), nil
+}
+
+func makeCreatePipelineScheduleOptions(opt *CreateUpdateOption, ref string) *gitlab.CreatePipelineScheduleOptions {
+	createOption := &gitlab.CreatePipelineScheduleOptions{
+		Description: gitlab.String(opt.Description),
+		Ref:         gitlab.String(ref),
+		Cron:        gitlab.String(opt.Cron),
+	}
+	if opt.Timezone != "" {
+		createOption.CronTimezone = gitlab.String(opt.Timezone)
+	}
+	if opt.Deactivate {
+		createOption.Active = gitlab.Bool(false)
+	}
+	return createOption
+}
+
+type updateMethod struct {
+	client   api.PipelineSchedule
+	opt      *CreateUpdateOption
+	variable *VariableOption
+	project  string
+	id       int
+}
+
+func (m *updateMethod) Process() (string, error) {
+	if m.opt.Activate && m.opt.Deactivate {
+		return "", fmt.Errorf("Please input either the activate or the deactivate option")
+	}
+	variables, err := parseVariables(m.variable.Variables)
+	if err != nil {
+		return "", err
+	}
+
+	if m.opt.hasUpdate() {
+		if _, err := m.client.EditSchedule(m.project, m.id, makeEditPipelineScheduleOptions(m.opt)); err != nil {
+			return "", err
+		}
+	}
+	if m.variable.hasUpdate() {
+		if err := m.updateVariables(variables); err != nil {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+// updateVariables creates the new variables, updates the values of the
+// existing variables, and removes the variables to remove.
+func (m *updateMethod) updateVariables(variables []*gitlab.PipelineVariable) error {
+	schedule, err := m.client.GetSchedule(m.project, m.id)
+	if err != nil {
+		return err
+	}
+	existing := map[string]bool{}
+	for _, variable := range schedule.Variables {
+		existing[variable.Key] = true
+	}
+
+	for _, variable := range variables {
+		if existing[variable.Key] {
+			_, err = m.client.EditScheduleVariable(m.project, m.id, variable.Key, &gitlab.EditPipelineScheduleVariableOptions{
+				Value: gitlab.String(variable.Value),
+			})
+		} else {
+			_, err = m.client.CreateScheduleVariable(m.project, m.id, &gitlab.CreatePipelineScheduleVariableOptions{
+				Key:   gitlab.String(variable.Key),
+				Value: gitlab.String(variable.Value),
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, key := range m.variable.RemoveVariables {
+		if !existing[key] {
+			return fmt.Errorf("Not found variable %s of pipeline schedule %d", key, m.id)
+		}
+		if err := m.client.DeleteScheduleVariable(m.project, m.id, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func makeEditPipelineScheduleOptions(opt *CreateUpdateOption) *gitlab.EditPipelineScheduleOptions {
+	editOption := &gitlab.EditPipelineScheduleOptions{}
+	if opt.Description != "" {
+		editOption.Description = gitlab.String(opt.Description)
+	}
+	if opt.Ref != "" {
+		editOption.Ref = gitlab.String(opt.Ref)
+	}
+	if opt.Cron != "" {
+		editOption.Cron = gitlab.String(opt.Cron)
+	}
+	if opt.Timezone != "" {
+		editOption.CronTimezone = gitlab.String(opt.Timezone)
+	}
+	if opt.Activate {
+		editOption.Active = gitlab.Bool(true)
+	}
+	if opt.Deactivate {
+		editOption.Active = gitlab.Bool(false)
+	}
+	return editOption
+}
+
+// parseVariables parses the variables given as KEY=VALUE.
+func parseVariables(values []string) ([]*gitlab.PipelineVariable, error) {
+	var variables []*gitlab.PipelineVariable
+	for _, value := range values {
+		kv := strings.SplitN(value, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("Invalid variable %s, please input it as KEY=VALUE", value)
+		}
+		variables = append(variables, &gitlab.PipelineVariable{
+			Key:   kv[0],
+			Value: kv[1],
+		})
+	}
+	return variables, nil
+}
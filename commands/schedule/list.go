@@ -0,0 +1,140 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+type listMethod struct {
+	client  api.PipelineSchedule
+	opt     *ListOption
+	output  *internal.OutputOption
+	project string
+}
+
+func (m *listMethod) Process() (string, error) {
+	w := m.output.NewListWriter()
+	err := m.client.ListSchedules(
+		m.project,
+		makeListPipelineSchedulesOptions(),
+		internal.Limit(m.opt.Num, m.opt.All),
+		func(schedules []*gitlab.PipelineSchedule) error {
+			return w.WritePage(schedules, func() [][]string {
+				return listOutput(schedules)
+			})
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	return w.Flush()
+}
+
+func makeListPipelineSchedulesOptions() *gitlab.ListPipelineSchedulesOptions {
+	return &gitlab.ListPipelineSchedulesOptions{
+		Page: 1,
+	}
+}
+
+func listOutput(schedules []*gitlab.PipelineSchedule) [][]string {
+	var outputs [][]string
+	for _, schedule := range schedules {
+		output := []string{
+			strconv.Itoa(schedule.ID),
+			activeState(schedule.Active),
+			schedule.Ref,
+			schedule.Cron,
+			schedule.CronTimezone,
+			nextRun(schedule),
+			schedule.Description,
+		}
+		outputs = append(outputs, output)
+	}
+	return outputs
+}
+
+type showMethod struct {
+	client  api.PipelineSchedule
+	output  *internal.OutputOption
+	project string
+	id      int
+}
+
+func (m *showMethod) Process() (string, error) {
+	schedule, err := m.client.GetSchedule(m.project, m.id)
+	if err != nil {
+		return "", err
+	}
+	return m.output.FormatDetail(
+		schedule,
+		func() string { return detailOutput(schedule) },
+		func() [][]string { return listOutput([]*gitlab.PipelineSchedule{schedule}) },
+	)
+}
+
+func detailOutput(schedule *gitlab.PipelineSchedule) string {
+	base := `%d %s
+State: %s
+Ref: %s
+Cron: %s %s
+Next run: %s
+Owner: %s
+Last pipeline: %s`
+	owner := ""
+	if schedule.Owner != nil {
+		owner = schedule.Owner.Username
+	}
+	lastPipeline := ""
+	if schedule.LastPipeline.ID > 0 {
+		lastPipeline = fmt.Sprintf("%d %s", schedule.LastPipeline.ID, schedule.LastPipeline.Status)
+	}
+	detail := fmt.Sprintf(
+		base,
+		schedule.ID,
+		schedule.Description,
+		activeState(schedule.Active),
+		schedule.Ref,
+		schedule.Cron,
+		schedule.CronTimezone,
+		nextRun(schedule),
+		owner,
+		lastPipeline,
+	)
+
+	if len(schedule.Variables) > 0 {
+		var rows [][]string
+		for _, variable := range schedule.Variables {
+			rows = append(rows, []string{variable.Key, variable.Value})
+		}
+		detail += "\nVariables:\n" + indent(internal.Columnize(rows))
+	}
+	return detail
+}
+
+func activeState(active bool) string {
+	if active {
+		return "active"
+	}
+	return "inactive"
+}
+
+func nextRun(schedule *gitlab.PipelineSchedule) string {
+	if schedule.NextRunAt == nil {
+		return ""
+	}
+	return schedule.NextRunAt.In(time.Local).Format("2006-01-02 15:04")
+}
+
+func indent(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = "  " + line
+	}
+	return strings.Join(lines, "\n")
+}
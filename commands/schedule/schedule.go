@@ -0,0 +1,225 @@
+package schedule
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+	"github.com/lighttiger2505/lab/internal/gitutil"
+	"github.com/lighttiger2505/lab/internal/ui"
+)
+
+const (
+	ExitCodeOK    int = iota //0
+	ExitCodeError int = iota //1
+)
+
+type Option struct {
+	ProjectProfileOption *internal.ProjectProfileOption `group:"Project, Profile Options"`
+	CreateUpdateOption   *CreateUpdateOption            `group:"Create, Update Options"`
+	VariableOption       *VariableOption                `group:"Variable Options"`
+	ActionOption         *ActionOption                  `group:"Take Ownership, Run, Delete Options"`
+	ListOption           *ListOption                    `group:"List Options"`
+	OutputOption         *internal.OutputOption         `group:"Output Options"`
+}
+
+type CreateUpdateOption struct {
+	Create      bool   `short:"c" long:"create" description:"Create the pipeline schedule."`
+	Description string `long:"description" value-name:"<description>" description:"The description of the pipeline schedule."`
+	Cron        string `long:"cron" value-name:"<cron>" description:"The cron of the pipeline schedule, e.g. \"0 1 * * *\"."`
+	Timezone    string `long:"timezone" value-name:"<timezone>" description:"The timezone of the cron, e.g. \"Asia/Tokyo\". UTC by default."`
+	Ref         string `long:"ref" value-name:"<ref>" description:"The branch or tag to run the pipeline for. The current branch by default."`
+	Activate    bool   `long:"activate" description:"Activate the pipeline schedule."`
+	Deactivate  bool   `long:"deactivate" description:"Deactivate the pipeline schedule."`
+}
+
+func (o *CreateUpdateOption) hasUpdate() bool {
+	if o.Description != "" ||
+		o.Cron != "" ||
+		o.Timezone != "" ||
+		o.Ref != "" ||
+		o.Activate ||
+		o.Deactivate {
+		return true
+	}
+	return false
+}
+
+type VariableOption struct {
+	Variables       []string `long:"var" value-name:"<key>=<value>" description:"Set the variable of the pipeline schedule. Repeat to give multiple variables."`
+	RemoveVariables []string `long:"remove-var" value-name:"<key>" description:"Remove the variable of the pipeline schedule. Repeat to give multiple variables."`
+}
+
+func (o *VariableOption) hasUpdate() bool {
+	return len(o.Variables) > 0 || len(o.RemoveVariables) > 0
+}
+
+type ActionOption struct {
+	TakeOwnership bool `long:"take-ownership" description:"Take ownership of the pipeline schedule."`
+	Run           bool `long:"run" description:"Run the pipeline of the schedule now."`
+	Delete        bool `short:"D" long:"delete" description:"Delete the pipeline schedule."`
+}
+
+type ListOption struct {
+	Num int  `short:"n" long:"num" value-name:"<num>" default:"20" default-mask:"20" description:"Limit the number of pipeline schedule to output."`
+	All bool `long:"all" description:"Print all pipeline schedules, ignore the num option."`
+}
+
+func newOptionParser(opt *Option) *flags.Parser {
+	opt.ProjectProfileOption = &internal.ProjectProfileOption{}
+	opt.CreateUpdateOption = &CreateUpdateOption{}
+	opt.VariableOption = &VariableOption{}
+	opt.ActionOption = &ActionOption{}
+	opt.ListOption = &ListOption{}
+	opt.OutputOption = &internal.OutputOption{}
+	parser := flags.NewParser(opt, flags.HelpFlag|flags.PassDoubleDash)
+	parser.Usage = `pipeline-schedule - Create and Edit, list a pipeline schedule
+
+Synopsis:
+  # List pipeline schedule
+  lab pipeline-schedule [-n <num>] [--all] [--output=<format>]
+
+  # Show pipeline schedule
+  lab pipeline-schedule <schedule id> [--output=<format>]
+
+  # Create pipeline schedule
+  lab pipeline-schedule -c --description=<description> --cron=<cron> [--timezone=<timezone>] [--ref=<ref>] [--deactivate] [--var=<key>=<value>...]
+
+  # Update pipeline schedule
+  lab pipeline-schedule <schedule id> [--description=<description>] [--cron=<cron>] [--timezone=<timezone>] [--ref=<ref>] [--activate | --deactivate]
+
+  # Set or remove variables of pipeline schedule
+  lab pipeline-schedule <schedule id> [--var=<key>=<value>...] [--remove-var=<key>...]
+
+  # Take ownership of, run or delete pipeline schedule
+  lab pipeline-schedule <schedule id> --take-ownership | --run | -D`
+	return parser
+}
+
+type PipelineScheduleCommand struct {
+	UI              ui.UI
+	RemoteCollecter gitutil.Collecter
+	ClientFactory   api.APIClientFactory
+}
+
+func (c *PipelineScheduleCommand) Synopsis() string {
+	return "Create and Edit, list a pipeline schedule"
+}
+
+func (c *PipelineScheduleCommand) Help() string {
+	buf := &bytes.Buffer{}
+	var opt Option
+	parser := newOptionParser(&opt)
+	parser.WriteHelp(buf)
+	return buf.String()
+}
+
+func (c *PipelineScheduleCommand) Run(args []string) int {
+	var opt Option
+	parser := newOptionParser(&opt)
+	parseArgs, err := parser.ParseArgs(args)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return ExitCodeError
+	}
+
+	pInfo, err := c.RemoteCollecter.CollectTarget(
+		opt.ProjectProfileOption.Project,
+		opt.ProjectProfileOption.Profile,
+	)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return ExitCodeError
+	}
+
+	if err := c.ClientFactory.Init(pInfo.ApiUrl(), pInfo.Token, pInfo.TLS); err != nil {
+		c.UI.Error(err.Error())
+		return ExitCodeError
+	}
+
+	method, err := c.getMethod(opt, parseArgs, pInfo)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return ExitCodeError
+	}
+
+	opt.OutputOption.Stream(c.UI)
+	res, err := method.Process()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return ExitCodeError
+	}
+
+	opt.OutputOption.Write(c.UI, res)
+
+	return ExitCodeOK
+}
+
+func (c *PipelineScheduleCommand) getMethod(opt Option, args []string, pInfo *gitutil.GitLabProjectInfo) (internal.Method, error) {
+	client := c.ClientFactory.GetPipelineScheduleClient()
+	createUpdateOption := opt.CreateUpdateOption
+
+	if createUpdateOption.Create {
+		return &createMethod{
+			client:   client,
+			opt:      createUpdateOption,
+			variable: opt.VariableOption,
+			project:  pInfo.Project,
+		}, nil
+	}
+
+	if len(args) < 1 {
+		return &listMethod{
+			client:  client,
+			opt:     opt.ListOption,
+			output:  opt.OutputOption,
+			project: pInfo.Project,
+		}, nil
+	}
+
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid args, please input pipeline schedule id.")
+	}
+
+	actionOption := opt.ActionOption
+	if actionOption.Delete {
+		return &deleteMethod{
+			client:  client,
+			project: pInfo.Project,
+			id:      id,
+		}, nil
+	}
+	if actionOption.TakeOwnership {
+		return &takeOwnershipMethod{
+			client:  client,
+			project: pInfo.Project,
+			id:      id,
+		}, nil
+	}
+	if actionOption.Run {
+		return &runMethod{
+			client:  client,
+			project: pInfo.Project,
+			id:      id,
+		}, nil
+	}
+	if createUpdateOption.hasUpdate() || opt.VariableOption.hasUpdate() {
+		return &updateMethod{
+			client:   client,
+			opt:      createUpdateOption,
+			variable: opt.VariableOption,
+			project:  pInfo.Project,
+			id:       id,
+		}, nil
+	}
+	return &showMethod{
+		client:  client,
+		output:  opt.OutputOption,
+		project: pInfo.Project,
+		id:      id,
+	}, nil
+}
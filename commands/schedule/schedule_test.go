@@ -0,0 +1,158 @@
+package schedule
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func Test_createMethod_Process(t *testing.T) {
+	var gotVariables []string
+	client := &api.MockPipelineScheduleClient{
+		MockCreateSchedule: func(project string, opt *gitlab.CreatePipelineScheduleOptions) (*gitlab.PipelineSchedule, error) {
+			want := &gitlab.CreatePipelineScheduleOptions{
+				Description:  gitlab.String("nightly"),
+				Ref:          gitlab.String("develop"),
+				Cron:         gitlab.String("0 1 * * *"),
+				CronTimezone: gitlab.String("Asia/Tokyo"),
+				Active:       gitlab.Bool(false),
+			}
+			if diff := cmp.Diff(opt, want); diff != "" {
+				t.Errorf("invalide arg (-got +want)\n%s", diff)
+			}
+			return &gitlab.PipelineSchedule{ID: 3}, nil
+		},
+		MockCreateScheduleVariable: func(project string, id int, opt *gitlab.CreatePipelineScheduleVariableOptions) (*gitlab.PipelineVariable, error) {
+			gotVariables = append(gotVariables, *opt.Key+"="+*opt.Value)
+			return &gitlab.PipelineVariable{}, nil
+		},
+	}
+
+	m := &createMethod{
+		client: client,
+		opt: &CreateUpdateOption{
+			Create:      true,
+			Description: "nightly",
+			Cron:        "0 1 * * *",
+			Timezone:    "Asia/Tokyo",
+			Ref:         "develop",
+			Deactivate:  true,
+		},
+		variable: &VariableOption{Variables: []string{"DEPLOY=true", "URL=https://example.com/?a=b"}},
+		project:  "group/project",
+	}
+	got, err := m.Process()
+	if err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if got != "3" {
+		t.Errorf("Process() = %q, want %q", got, "3")
+	}
+	if diff := cmp.Diff(gotVariables, []string{"DEPLOY=true", "URL=https://example.com/?a=b"}); diff != "" {
+		t.Errorf("invalide arg (-got +want)\n%s", diff)
+	}
+
+	m.variable.Variables = []string{"DEPLOY"}
+	if _, err := m.Process(); err == nil {
+		t.Errorf("Process() want error of invalid variable")
+	}
+	m.opt.Cron = ""
+	if _, err := m.Process(); err == nil {
+		t.Errorf("Process() want error without cron")
+	}
+}
+
+func Test_updateMethod_Process(t *testing.T) {
+	var calls []string
+	client := &api.MockPipelineScheduleClient{
+		MockEditSchedule: func(project string, id int, opt *gitlab.EditPipelineScheduleOptions) (*gitlab.PipelineSchedule, error) {
+			want := &gitlab.EditPipelineScheduleOptions{
+				Cron:   gitlab.String("0 2 * * *"),
+				Active: gitlab.Bool(true),
+			}
+			if diff := cmp.Diff(opt, want); diff != "" {
+				t.Errorf("invalide arg (-got +want)\n%s", diff)
+			}
+			calls = append(calls, "edit")
+			return &gitlab.PipelineSchedule{ID: id}, nil
+		},
+		MockGetSchedule: func(project string, id int) (*gitlab.PipelineSchedule, error) {
+			return &gitlab.PipelineSchedule{
+				ID: id,
+				Variables: []*gitlab.PipelineVariable{
+					{Key: "DEPLOY", Value: "false"},
+					{Key: "OLD", Value: "old"},
+				},
+			}, nil
+		},
+		MockCreateScheduleVariable: func(project string, id int, opt *gitlab.CreatePipelineScheduleVariableOptions) (*gitlab.PipelineVariable, error) {
+			calls = append(calls, "create "+*opt.Key+"="+*opt.Value)
+			return &gitlab.PipelineVariable{}, nil
+		},
+		MockEditScheduleVariable: func(project string, id int, key string, opt *gitlab.EditPipelineScheduleVariableOptions) (*gitlab.PipelineVariable, error) {
+			calls = append(calls, "edit "+key+"="+*opt.Value)
+			return &gitlab.PipelineVariable{}, nil
+		},
+		MockDeleteScheduleVariable: func(project string, id int, key string) error {
+			calls = append(calls, "delete "+key)
+			return nil
+		},
+	}
+
+	m := &updateMethod{
+		client: client,
+		opt:    &CreateUpdateOption{Cron: "0 2 * * *", Activate: true},
+		variable: &VariableOption{
+			Variables:       []string{"DEPLOY=true", "NEW=new"},
+			RemoveVariables: []string{"OLD"},
+		},
+		project: "group/project",
+		id:      3,
+	}
+	if _, err := m.Process(); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	want := []string{"edit", "edit DEPLOY=true", "create NEW=new", "delete OLD"}
+	if diff := cmp.Diff(calls, want); diff != "" {
+		t.Errorf("invalide arg (-got +want)\n%s", diff)
+	}
+
+	m.variable = &VariableOption{RemoveVariables: []string{"MISSING"}}
+	m.opt = &CreateUpdateOption{}
+	if _, err := m.Process(); err == nil {
+		t.Errorf("Process() want error of missing variable")
+	}
+}
+
+func Test_detailOutput(t *testing.T) {
+	schedule := &gitlab.PipelineSchedule{
+		ID:           3,
+		Description:  "nightly",
+		Ref:          "master",
+		Cron:         "0 1 * * *",
+		CronTimezone: "UTC",
+		Active:       true,
+		Owner:        &gitlab.User{Username: "lighttiger2505"},
+		Variables: []*gitlab.PipelineVariable{
+			{Key: "DEPLOY", Value: "true"},
+		},
+	}
+	schedule.LastPipeline.ID = 12
+	schedule.LastPipeline.Status = "success"
+
+	got := detailOutput(schedule)
+	want := `3 nightly
+State: active
+Ref: master
+Cron: 0 1 * * * UTC
+Next run: 
+Owner: lighttiger2505
+Last pipeline: 12 success
+Variables:
+  DEPLOY  true`
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("invalide arg (-got +want)\n%s", diff)
+	}
+}
@@ -0,0 +1,162 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+	"github.com/lighttiger2505/lab/internal/cache"
+	"github.com/lighttiger2505/lab/internal/gitutil"
+	"github.com/lighttiger2505/lab/internal/ui"
+)
+
+// syncLogFile is the log of the sync run in the background, in the directory
+// of the cache.
+const syncLogFile = "sync.log"
+
+type SyncCommandOption struct {
+	ProjectProfileOption *internal.ProjectProfileOption `group:"Project, Profile Options"`
+	SyncOption           *SyncOption                    `group:"Sync Options"`
+}
+
+type SyncOption struct {
+	All        bool `short:"a" long:"all" description:"Sync every project of the profile in the cache, instead of the current project."`
+	Foreground bool `short:"f" long:"foreground" description:"Sync in the foreground and print the number of the read items, instead of in the background."`
+}
+
+func newSyncOptionParser(opt *SyncCommandOption) *flags.Parser {
+	opt.ProjectProfileOption = &internal.ProjectProfileOption{}
+	opt.SyncOption = &SyncOption{}
+	parser := flags.NewParser(opt, flags.HelpFlag|flags.PassDoubleDash)
+	parser.Usage = `sync - Refresh the cache of issues, merge requests and pipelines
+
+Synopsis:
+  # Refresh the cache of the current project in the background
+  lab sync
+
+  # Refresh the cache of every cached project of the profile, and wait for it
+  lab sync -a -f`
+	return parser
+}
+
+type SyncCommand struct {
+	UI              ui.UI
+	RemoteCollecter gitutil.Collecter
+	ClientFactory   api.APIClientFactory
+	Cache           *cache.Store
+	// Start runs lab with the args in the background, writing the output to
+	// the log. startSync is used when nil
+	Start func(args []string, logPath string) error
+}
+
+func (c *SyncCommand) Synopsis() string {
+	return "Refresh the cache of issues, merge requests and pipelines"
+}
+
+func (c *SyncCommand) Help() string {
+	var opt SyncCommandOption
+	parser := newSyncOptionParser(&opt)
+	buf := &bytes.Buffer{}
+	parser.WriteHelp(buf)
+	return buf.String()
+}
+
+func (c *SyncCommand) Run(args []string) int {
+	var opt SyncCommandOption
+	parser := newSyncOptionParser(&opt)
+	if _, err := parser.ParseArgs(args); err != nil {
+		c.UI.Error(err.Error())
+		return ExitCodeError
+	}
+
+	if !opt.SyncOption.Foreground {
+		start := c.Start
+		if start == nil {
+			start = startSync
+		}
+		logPath := filepath.Join(c.Cache.Dir(), syncLogFile)
+		if err := start(append([]string{"sync", "--foreground"}, args...), logPath); err != nil {
+			c.UI.Error(err.Error())
+			return ExitCodeError
+		}
+		c.UI.Message(fmt.Sprintf("Syncing in the background, see %s", logPath))
+		return ExitCodeOK
+	}
+
+	pInfo, err := c.RemoteCollecter.CollectTarget(
+		opt.ProjectProfileOption.Project,
+		opt.ProjectProfileOption.Profile,
+	)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return ExitCodeError
+	}
+
+	if err := c.ClientFactory.Init(pInfo.ApiUrl(), pInfo.Token, pInfo.TLS); err != nil {
+		c.UI.Error(err.Error())
+		return ExitCodeError
+	}
+
+	projects := []string{pInfo.Project}
+	if opt.SyncOption.All {
+		projects, err = c.Cache.Projects(pInfo.Domain)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return ExitCodeError
+		}
+		if len(projects) == 0 {
+			c.UI.Message(fmt.Sprintf("No project of %s in the cache", pInfo.Domain))
+			return ExitCodeOK
+		}
+	}
+
+	// A project failed to sync does not stop the others
+	exitCode := ExitCodeOK
+	syncer := cache.NewSyncer(c.ClientFactory, c.Cache, pInfo.Domain)
+	for _, project := range projects {
+		result, err := syncer.Sync(project)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("%s: %s", project, err))
+			exitCode = ExitCodeError
+			continue
+		}
+		c.UI.Message(fmt.Sprintf(
+			"%s: %d issues, %d merge requests, %d pipelines",
+			project,
+			result.Issues,
+			result.MergeRequests,
+			result.Pipelines,
+		))
+	}
+	return exitCode
+}
+
+// startSync runs lab with the args in a process left running after lab
+// exits.
+func startSync(args []string, logPath string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("Failed start sync. %s", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(logPath), 0700); err != nil {
+		return fmt.Errorf("Failed start sync. %s", err)
+	}
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed start sync. %s", err)
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(exe, args...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("Failed start sync. %s", err)
+	}
+	return cmd.Process.Release()
+}
@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/lighttiger2505/lab/internal/api"
+	"github.com/lighttiger2505/lab/internal/cache"
+	"github.com/lighttiger2505/lab/internal/gitutil"
+	"github.com/lighttiger2505/lab/internal/ui"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func TestSyncCommandRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lab-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	factory := &api.MockAPIClientFactory{
+		MockGetIssueClient: func() api.Issue {
+			return &api.MockLabIssueClient{
+				MockGetProjectIssues: func(opt *gitlab.ListProjectIssuesOptions, repositoryName string) ([]*gitlab.Issue, error) {
+					return []*gitlab.Issue{{IID: 1}, {IID: 2}}, nil
+				},
+			}
+		},
+		MockGetMergeRequestClient: func() api.MergeRequest {
+			return &api.MockLabMergeRequestClient{
+				MockGetProjectMargeRequest: func(opt *gitlab.ListProjectMergeRequestsOptions, repositoryName string) ([]*gitlab.MergeRequest, error) {
+					return []*gitlab.MergeRequest{{IID: 1}}, nil
+				},
+			}
+		},
+		MockGetPipelineClient: func() api.Pipeline {
+			return &api.MockPipelineClient{
+				MockProjectPipelines: func(repositoryName string, opt *gitlab.ListProjectPipelinesOptions) (gitlab.PipelineList, error) {
+					return gitlab.PipelineList{}, nil
+				},
+			}
+		},
+	}
+
+	mockUI := ui.NewMockUi()
+	c := SyncCommand{
+		UI:              mockUI,
+		RemoteCollecter: &gitutil.MockCollecter{},
+		ClientFactory:   factory,
+		Cache:           cache.NewStore(dir),
+	}
+	if code := c.Run([]string{"--foreground"}); code != ExitCodeOK {
+		t.Fatalf("wrong exit code. errors: \n%s", mockUI.ErrorWriter.String())
+	}
+	if diff := cmp.Diff(mockUI.Writer.String(), "project: 2 issues, 1 merge requests, 0 pipelines\n"); diff != "" {
+		t.Errorf("invalide arg (-got +want)\n%s", diff)
+	}
+
+	projects, err := c.Cache.Projects("domain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(projects, []string{"project"}); diff != "" {
+		t.Errorf("invalide arg (-got +want)\n%s", diff)
+	}
+}
+
+func TestSyncCommandRun_background(t *testing.T) {
+	var gotArgs []string
+	var gotLog string
+	mockUI := ui.NewMockUi()
+	c := SyncCommand{
+		UI:              mockUI,
+		RemoteCollecter: &gitutil.MockCollecter{},
+		ClientFactory:   &api.MockAPIClientFactory{},
+		Cache:           cache.NewStore("/cache"),
+		Start: func(args []string, logPath string) error {
+			gotArgs = args
+			gotLog = logPath
+			return nil
+		},
+	}
+	if code := c.Run([]string{"-a"}); code != ExitCodeOK {
+		t.Fatalf("wrong exit code. errors: \n%s", mockUI.ErrorWriter.String())
+	}
+	if diff := cmp.Diff(gotArgs, []string{"sync", "--foreground", "-a"}); diff != "" {
+		t.Errorf("invalide arg (-got +want)\n%s", diff)
+	}
+	if want := filepath.Join("/cache", syncLogFile); gotLog != want {
+		t.Errorf("log = %q, want %q", gotLog, want)
+	}
+}
@@ -0,0 +1,43 @@
+package trigger
+
+import (
+	"fmt"
+
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+type createMethod struct {
+	client  api.PipelineTrigger
+	opt     *CreateOption
+	project string
+}
+
+func (m *createMethod) Process() (string, error) {
+	if m.opt.Description == "" {
+		return "", fmt.Errorf("Please input the description of the trigger token by --description.")
+	}
+	trigger, err := m.client.CreateTrigger(
+		m.project,
+		&gitlab.AddPipelineTriggerOptions{
+			Description: gitlab.String(m.opt.Description),
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	return trigger.Token, nil
+}
+
+type deleteMethod struct {
+	client  api.PipelineTrigger
+	project string
+	id      int
+}
+
+func (m *deleteMethod) Process() (string, error) {
+	if err := m.client.DeleteTrigger(m.project, m.id); err != nil {
+		return "", err
+	}
+	return "", nil
+}
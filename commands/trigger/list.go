@@ -0,0 +1,69 @@
+package trigger
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+type listMethod struct {
+	client  api.PipelineTrigger
+	opt     *ListOption
+	output  *internal.OutputOption
+	project string
+}
+
+func (m *listMethod) Process() (string, error) {
+	w := m.output.NewListWriter()
+	err := m.client.ListTriggers(
+		m.project,
+		makeListPipelineTriggersOptions(),
+		internal.Limit(m.opt.Num, m.opt.All),
+		func(triggers []*gitlab.PipelineTrigger) error {
+			return w.WritePage(triggers, func() [][]string {
+				return listOutput(triggers)
+			})
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	return w.Flush()
+}
+
+func makeListPipelineTriggersOptions() *gitlab.ListPipelineTriggersOptions {
+	return &gitlab.ListPipelineTriggersOptions{
+		Page: 1,
+	}
+}
+
+// listOutput prints the tokens as GitLab returns them. GitLab only returns
+// the whole token to its owner.
+func listOutput(triggers []*gitlab.PipelineTrigger) [][]string {
+	var outputs [][]string
+	for _, trigger := range triggers {
+		owner := ""
+		if trigger.Owner != nil {
+			owner = trigger.Owner.Username
+		}
+		output := []string{
+			strconv.Itoa(trigger.ID),
+			trigger.Token,
+			owner,
+			lastUsed(trigger),
+			trigger.Description,
+		}
+		outputs = append(outputs, output)
+	}
+	return outputs
+}
+
+func lastUsed(trigger *gitlab.PipelineTrigger) string {
+	if trigger.LastUsed == nil {
+		return "never"
+	}
+	return trigger.LastUsed.In(time.Local).Format("2006-01-02 15:04")
+}
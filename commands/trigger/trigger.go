@@ -0,0 +1,156 @@
+package trigger
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	flags "github.com/jessevdk/go-flags"
+	"github.com/lighttiger2505/lab/commands/internal"
+	"github.com/lighttiger2505/lab/internal/api"
+	"github.com/lighttiger2505/lab/internal/gitutil"
+	"github.com/lighttiger2505/lab/internal/ui"
+)
+
+const (
+	ExitCodeOK    int = iota //0
+	ExitCodeError int = iota //1
+)
+
+type Option struct {
+	ProjectProfileOption *internal.ProjectProfileOption `group:"Project, Profile Options"`
+	CreateOption         *CreateOption                  `group:"Create, Revoke Options"`
+	ListOption           *ListOption                    `group:"List Options"`
+	OutputOption         *internal.OutputOption         `group:"Output Options"`
+}
+
+type CreateOption struct {
+	Create      bool   `short:"c" long:"create" description:"Create the trigger token, and print it."`
+	Description string `long:"description" value-name:"<description>" description:"The description of the trigger token."`
+	Delete      bool   `short:"D" long:"delete" description:"Revoke the trigger token."`
+}
+
+type ListOption struct {
+	Num int  `short:"n" long:"num" value-name:"<num>" default:"20" default-mask:"20" description:"Limit the number of trigger token to output."`
+	All bool `long:"all" description:"Print all trigger tokens, ignore the num option."`
+}
+
+func newOptionParser(opt *Option) *flags.Parser {
+	opt.ProjectProfileOption = &internal.ProjectProfileOption{}
+	opt.CreateOption = &CreateOption{}
+	opt.ListOption = &ListOption{}
+	opt.OutputOption = &internal.OutputOption{}
+	parser := flags.NewParser(opt, flags.HelpFlag|flags.PassDoubleDash)
+	parser.Usage = `trigger - Create and Revoke, list a pipeline trigger token
+
+Synopsis:
+  # List trigger token
+  lab trigger [-n <num>] [--all] [--output=<format>]
+
+  # Create trigger token
+  lab trigger -c --description=<description>
+
+  # Revoke trigger token
+  lab trigger <trigger id> -D
+
+  # Run pipeline with trigger token
+  lab pipeline run --trigger-token=<token> [--ref=<ref>] [--var=<key>=<value>...]`
+	return parser
+}
+
+type TriggerCommand struct {
+	UI              ui.UI
+	RemoteCollecter gitutil.Collecter
+	ClientFactory   api.APIClientFactory
+}
+
+func (c *TriggerCommand) Synopsis() string {
+	return "Create and Revoke, list a pipeline trigger token"
+}
+
+func (c *TriggerCommand) Help() string {
+	buf := &bytes.Buffer{}
+	var opt Option
+	parser := newOptionParser(&opt)
+	parser.WriteHelp(buf)
+	return buf.String()
+}
+
+func (c *TriggerCommand) Run(args []string) int {
+	var opt Option
+	parser := newOptionParser(&opt)
+	parseArgs, err := parser.ParseArgs(args)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return ExitCodeError
+	}
+
+	pInfo, err := c.RemoteCollecter.CollectTarget(
+		opt.ProjectProfileOption.Project,
+		opt.ProjectProfileOption.Profile,
+	)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return ExitCodeError
+	}
+
+	if err := c.ClientFactory.Init(pInfo.ApiUrl(), pInfo.Token, pInfo.TLS); err != nil {
+		c.UI.Error(err.Error())
+		return ExitCodeError
+	}
+
+	method, err := c.getMethod(opt, parseArgs, pInfo)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return ExitCodeError
+	}
+
+	opt.OutputOption.Stream(c.UI)
+	res, err := method.Process()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return ExitCodeError
+	}
+
+	opt.OutputOption.Write(c.UI, res)
+
+	return ExitCodeOK
+}
+
+func (c *TriggerCommand) getMethod(opt Option, args []string, pInfo *gitutil.GitLabProjectInfo) (internal.Method, error) {
+	client := c.ClientFactory.GetPipelineTriggerClient()
+	createOption := opt.CreateOption
+
+	if createOption.Create {
+		return &createMethod{
+			client:  client,
+			opt:     createOption,
+			project: pInfo.Project,
+		}, nil
+	}
+
+	if len(args) < 1 {
+		if createOption.Delete {
+			return nil, fmt.Errorf("Invalid args, please input trigger id.")
+		}
+		return &listMethod{
+			client:  client,
+			opt:     opt.ListOption,
+			output:  opt.OutputOption,
+			project: pInfo.Project,
+		}, nil
+	}
+
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid args, please input trigger id.")
+	}
+	if !createOption.Delete {
+		return nil, fmt.Errorf("Invalid args, please input -D to revoke the trigger token.")
+	}
+	return &deleteMethod{
+		client:  client,
+		project: pInfo.Project,
+		id:      id,
+	}, nil
+}
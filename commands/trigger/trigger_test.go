@@ -0,0 +1,65 @@
+package trigger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func Test_createMethod_Process(t *testing.T) {
+	client := &api.MockPipelineTriggerClient{
+		MockCreateTrigger: func(project string, opt *gitlab.AddPipelineTriggerOptions) (*gitlab.PipelineTrigger, error) {
+			want := &gitlab.AddPipelineTriggerOptions{Description: gitlab.String("deploy")}
+			if diff := cmp.Diff(opt, want); diff != "" {
+				t.Errorf("invalide arg (-got +want)\n%s", diff)
+			}
+			return &gitlab.PipelineTrigger{ID: 10, Token: "6d056f63e50fe6f8c5f8f4aa10edb7"}, nil
+		},
+	}
+	m := &createMethod{
+		client:  client,
+		opt:     &CreateOption{Create: true, Description: "deploy"},
+		project: "group/project",
+	}
+	got, err := m.Process()
+	if err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if got != "6d056f63e50fe6f8c5f8f4aa10edb7" {
+		t.Errorf("Process() = %q, want the token", got)
+	}
+
+	m.opt.Description = ""
+	if _, err := m.Process(); err == nil {
+		t.Errorf("Process() want error without description")
+	}
+}
+
+func Test_listOutput(t *testing.T) {
+	lastUsed := time.Date(2018, 4, 1, 9, 30, 0, 0, time.Local)
+	triggers := []*gitlab.PipelineTrigger{
+		{
+			ID:          10,
+			Token:       "6d056f63e50fe6f8c5f8f4aa10edb7",
+			Description: "deploy",
+			Owner:       &gitlab.User{Username: "lighttiger2505"},
+			LastUsed:    &lastUsed,
+		},
+		{
+			ID:          11,
+			Token:       "4a31",
+			Description: "docs",
+		},
+	}
+	got := listOutput(triggers)
+	want := [][]string{
+		{"10", "6d056f63e50fe6f8c5f8f4aa10edb7", "lighttiger2505", "2018-04-01 09:30", "deploy"},
+		{"11", "4a31", "", "never", "docs"},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("invalide arg (-got +want)\n%s", diff)
+	}
+}
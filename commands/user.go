@@ -3,25 +3,25 @@ package commands
 import (
 	"bytes"
 	"strconv"
-	"strings"
 
 	flags "github.com/jessevdk/go-flags"
 	"github.com/lighttiger2505/lab/commands/internal"
 	"github.com/lighttiger2505/lab/internal/api"
 	"github.com/lighttiger2505/lab/internal/gitutil"
 	"github.com/lighttiger2505/lab/internal/ui"
-	"github.com/ryanuber/columnize"
 	gitlab "github.com/xanzy/go-gitlab"
 )
 
 type UserCommandOption struct {
 	ProjectProfileOption *internal.ProjectProfileOption `group:"Project, Profile Options"`
 	ListOption           *ListUserOption                `group:"List Options"`
+	OutputOption         *internal.OutputOption         `group:"Output Options"`
 }
 
 func newUserOptionParser(opt *UserCommandOption) *flags.Parser {
 	opt.ProjectProfileOption = &internal.ProjectProfileOption{}
 	opt.ListOption = newListUserOption()
+	opt.OutputOption = &internal.OutputOption{}
 	parser := flags.NewParser(opt, flags.HelpFlag|flags.PassDoubleDash)
 	parser.Usage = `user - list a user
 
@@ -33,6 +33,7 @@ Synopsis:
 
 type ListUserOption struct {
 	Num        int    `short:"n" long:"num" value-name:"<num>" default:"20" default-mask:"20" description:"Limit the number of search to output."`
+	All        bool   `long:"all" description:"Print all users, ignore the num option."`
 	Search     string `short:"s" long:"search" value-name:"<search word>" description:"Search for specific users"`
 	AllProject bool   `short:"A" long:"all-project" description:"Print the user of all projects"`
 }
@@ -76,44 +77,56 @@ func (c *UserCommand) Run(args []string) int {
 		return ExitCodeError
 	}
 
-	if err := c.ClientFactory.Init(pInfo.ApiUrl(), pInfo.Token); err != nil {
+	if err := c.ClientFactory.Init(pInfo.ApiUrl(), pInfo.Token, pInfo.TLS); err != nil {
 		c.UI.Error(err.Error())
 		return ExitCodeError
 	}
 	client := c.ClientFactory.GetUserClient()
 
 	listOpt := opt.ListOption
-	var result string
+	limit := internal.Limit(listOpt.Num, listOpt.All)
+	opt.OutputOption.Stream(c.UI)
+	w := opt.OutputOption.NewListWriter()
 	if opt.ListOption.AllProject {
-		users, err := client.Users(
+		err = client.Users(
 			makeUsersOption(listOpt),
+			limit,
+			func(users []*gitlab.User) error {
+				return w.WritePage(users, func() [][]string {
+					return userOutput(users)
+				})
+			},
 		)
-		if err != nil {
-			c.UI.Error(err.Error())
-			return ExitCodeError
-		}
-		result = columnize.SimpleFormat(userOutput(users))
 	} else {
-		users, err := client.ProjectUsers(
+		err = client.ProjectUsers(
 			pInfo.Project,
 			makeProjectUsersOption(listOpt),
+			limit,
+			func(users []*gitlab.ProjectUser) error {
+				return w.WritePage(users, func() [][]string {
+					return projectUserOutput(users)
+				})
+			},
 		)
-		if err != nil {
-			c.UI.Error(err.Error())
-			return ExitCodeError
-		}
-		result = columnize.SimpleFormat(projectUserOutput(users))
+	}
+	if err != nil {
+		c.UI.Error(err.Error())
+		return ExitCodeError
 	}
 
-	c.UI.Message(result)
+	result, err := w.Flush()
+	if err != nil {
+		c.UI.Error(err.Error())
+		return ExitCodeError
+	}
+	opt.OutputOption.Write(c.UI, result)
 
 	return ExitCodeOK
 }
 
 func makeProjectUsersOption(opt *ListUserOption) *gitlab.ListProjectUserOptions {
 	listOption := &gitlab.ListOptions{
-		Page:    1,
-		PerPage: opt.Num,
+		Page: 1,
 	}
 	listUserOption := &gitlab.ListProjectUserOptions{
 		ListOptions: *listOption,
@@ -124,8 +137,7 @@ func makeProjectUsersOption(opt *ListUserOption) *gitlab.ListProjectUserOptions
 
 func makeUsersOption(opt *ListUserOption) *gitlab.ListUsersOptions {
 	listOption := &gitlab.ListOptions{
-		Page:    1,
-		PerPage: opt.Num,
+		Page: 1,
 	}
 	listProjectUserOptions := &gitlab.ListUsersOptions{
 		ListOptions: *listOption,
@@ -134,26 +146,26 @@ func makeUsersOption(opt *ListUserOption) *gitlab.ListUsersOptions {
 	return listProjectUserOptions
 }
 
-func userOutput(users []*gitlab.User) []string {
-	var outputs []string
+func userOutput(users []*gitlab.User) [][]string {
+	var outputs [][]string
 	for _, user := range users {
-		output := strings.Join([]string{
+		output := []string{
 			strconv.Itoa(user.ID),
 			user.Name,
 			user.Username,
-		}, "|")
+		}
 		outputs = append(outputs, output)
 	}
 	return outputs
 }
-func projectUserOutput(users []*gitlab.ProjectUser) []string {
-	var outputs []string
+func projectUserOutput(users []*gitlab.ProjectUser) [][]string {
+	var outputs [][]string
 	for _, user := range users {
-		output := strings.Join([]string{
+		output := []string{
 			strconv.Itoa(user.ID),
 			user.Name,
 			user.Username,
-		}, "|")
+		}
 		outputs = append(outputs, output)
 	}
 	return outputs
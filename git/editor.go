@@ -73,6 +73,17 @@ func (e *Editor) EditTitleAndDescription() (title, body string, err error) {
 	return
 }
 
+// EditContent returns the whole edited content, for the messages without a
+// title like comments.
+func (e *Editor) EditContent() (content string, err error) {
+	b, err := e.openAndEdit()
+	if err != nil {
+		return
+	}
+	content = string(bytes.TrimSpace(b))
+	return
+}
+
 func (e *Editor) openAndEdit() (content []byte, err error) {
 	err = e.writeContent()
 	if err != nil {
@@ -12,6 +12,12 @@ import (
 type Client interface {
 	RemoteInfos() ([]*RemoteInfo, error)
 	CurrentRemoteBranch() (string, error)
+	RemoteURL(remote string) (string, error)
+	AddRemote(name, url string) error
+	Fetch(remote string, refspecs ...string) error
+	CheckoutNewBranch(branch, startPoint string) error
+	CheckoutDetach(startPoint string) error
+	SetUpstream(branch, remote, remoteBranch string) error
 }
 
 type GitClient struct {
@@ -68,6 +74,56 @@ func (g *GitClient) CurrentRemoteBranch() (string, error) {
 
 }
 
+func (g *GitClient) RemoteURL(remote string) (string, error) {
+	url, err := gitOutput("remote", "get-url", remote)
+	if err != nil {
+		return "", fmt.Errorf("Failed get git remote url. %s", err)
+	}
+	return url[0], nil
+}
+
+func (g *GitClient) AddRemote(name, url string) error {
+	if _, err := gitOutput("remote", "add", name, url); err != nil {
+		return fmt.Errorf("Failed add git remote. %s", err)
+	}
+	return nil
+}
+
+func (g *GitClient) Fetch(remote string, refspecs ...string) error {
+	args := append([]string{"fetch", remote}, refspecs...)
+	if _, err := gitOutput(args...); err != nil {
+		return fmt.Errorf("Failed fetch from %s. %s", remote, err)
+	}
+	return nil
+}
+
+func (g *GitClient) CheckoutNewBranch(branch, startPoint string) error {
+	if _, err := gitOutput("checkout", "-b", branch, startPoint); err != nil {
+		return fmt.Errorf("Failed checkout branch %s. %s", branch, err)
+	}
+	return nil
+}
+
+func (g *GitClient) CheckoutDetach(startPoint string) error {
+	if _, err := gitOutput("checkout", "--detach", startPoint); err != nil {
+		return fmt.Errorf("Failed checkout %s. %s", startPoint, err)
+	}
+	return nil
+}
+
+// SetUpstream makes the branch track the remote branch. Unlike
+// "git branch --set-upstream-to", the remote branch does not have to be
+// fetched yet.
+func (g *GitClient) SetUpstream(branch, remote, remoteBranch string) error {
+	if _, err := gitOutput("config", fmt.Sprintf("branch.%s.remote", branch), remote); err != nil {
+		return fmt.Errorf("Failed set upstream of %s. %s", branch, err)
+	}
+	if _, err := gitOutput("config", fmt.Sprintf("branch.%s.merge", branch), "refs/heads/"+remoteBranch); err != nil {
+		return fmt.Errorf("Failed set upstream of %s. %s", branch, err)
+	}
+	return nil
+}
+
 func IsGitDirReverseTop() (bool, error) {
 	pos, err := os.Getwd()
 	if err != nil {
@@ -116,6 +172,16 @@ func CurrentBranch() (string, error) {
 	return currentBranch, nil
 }
 
+// DefaultBranch returns the default branch of the origin remote, which git
+// knows by the HEAD of the remote.
+func DefaultBranch() (string, error) {
+	outputs, err := gitOutput("symbolic-ref", "--short", "refs/remotes/origin/HEAD")
+	if err != nil {
+		return "", fmt.Errorf("Failed get default branch. %s", err)
+	}
+	return strings.TrimPrefix(outputs[0], "origin/"), nil
+}
+
 func GitEditor() (string, error) {
 	outputs, err := gitOutput("var", "GIT_EDITOR")
 	if err != nil {
@@ -234,6 +300,12 @@ func gitConfigCommand(args []string) []string {
 type MockClient struct {
 	MockRemoteInfos         func() ([]*RemoteInfo, error)
 	MockCurrentRemoteBranch func() (string, error)
+	MockRemoteURL           func(remote string) (string, error)
+	MockAddRemote           func(name, url string) error
+	MockFetch               func(remote string, refspecs ...string) error
+	MockCheckoutNewBranch   func(branch, startPoint string) error
+	MockCheckoutDetach      func(startPoint string) error
+	MockSetUpstream         func(branch, remote, remoteBranch string) error
 }
 
 func (m *MockClient) RemoteInfos() ([]*RemoteInfo, error) {
@@ -243,3 +315,27 @@ func (m *MockClient) RemoteInfos() ([]*RemoteInfo, error) {
 func (m *MockClient) CurrentRemoteBranch() (string, error) {
 	return m.MockCurrentRemoteBranch()
 }
+
+func (m *MockClient) RemoteURL(remote string) (string, error) {
+	return m.MockRemoteURL(remote)
+}
+
+func (m *MockClient) AddRemote(name, url string) error {
+	return m.MockAddRemote(name, url)
+}
+
+func (m *MockClient) Fetch(remote string, refspecs ...string) error {
+	return m.MockFetch(remote, refspecs...)
+}
+
+func (m *MockClient) CheckoutNewBranch(branch, startPoint string) error {
+	return m.MockCheckoutNewBranch(branch, startPoint)
+}
+
+func (m *MockClient) CheckoutDetach(startPoint string) error {
+	return m.MockCheckoutDetach(startPoint)
+}
+
+func (m *MockClient) SetUpstream(branch, remote, remoteBranch string) error {
+	return m.MockSetUpstream(branch, remote, remoteBranch)
+}
@@ -2,6 +2,8 @@ package git
 
 import (
 	"fmt"
+	"net"
+	"net/url"
 	"regexp"
 	"strings"
 )
@@ -12,13 +14,32 @@ type RemoteInfo struct {
 	Group      string
 	SubGroup   string
 	Repository string
+	// Scheme and Port are taken from the http remotes. The web page of GitLab
+	// is on "https" and the default port when they are empty.
+	Scheme string
+	Port   string
 }
 
-func NewRemoteInfo(remote, url string) *RemoteInfo {
-	url = strings.TrimPrefix(url, "ssh://")
-	url = strings.TrimPrefix(url, "https://")
-	url = strings.TrimPrefix(url, "git@")
-	splitUrl := regexp.MustCompile("/|:").Split(url, -1)
+func NewRemoteInfo(remote, rawurl string) *RemoteInfo {
+	var scheme, port string
+	path := rawurl
+	if i := strings.Index(rawurl, "://"); i >= 0 {
+		path = rawurl[i+len("://"):]
+		if u, err := url.Parse(rawurl); err == nil {
+			// The user info is left out, the remotes of CI carry a token in it.
+			// The port of ssh has nothing to do with the web page.
+			if u.Scheme == "http" {
+				scheme = "http"
+			}
+			if u.Scheme == "http" || u.Scheme == "https" {
+				port = u.Port()
+			}
+			path = u.Hostname() + u.Path
+		}
+	}
+	path = strings.TrimPrefix(path, "git@")
+
+	splitUrl := regexp.MustCompile("/|:").Split(path, -1)
 	if len(splitUrl) > 3 {
 		// Apply subgroup
 		return &RemoteInfo{
@@ -27,6 +48,8 @@ func NewRemoteInfo(remote, url string) *RemoteInfo {
 			SubGroup:   strings.Join(splitUrl[2:len(splitUrl)-1], "/"),
 			Group:      splitUrl[1],
 			Domain:     splitUrl[0],
+			Scheme:     scheme,
+			Port:       port,
 		}
 	}
 	return &RemoteInfo{
@@ -34,6 +57,8 @@ func NewRemoteInfo(remote, url string) *RemoteInfo {
 		Repository: strings.TrimSuffix(splitUrl[len(splitUrl)-1], ".git"),
 		Group:      splitUrl[len(splitUrl)-2],
 		Domain:     splitUrl[len(splitUrl)-3],
+		Scheme:     scheme,
+		Port:       port,
 	}
 }
 
@@ -45,7 +70,15 @@ func (r *RemoteInfo) RepositoryFullName() string {
 }
 
 func (r *RemoteInfo) BaseUrl() string {
-	return "https://" + r.Domain
+	scheme := "https"
+	if r.Scheme != "" {
+		scheme = r.Scheme
+	}
+	host := r.Domain
+	if r.Port != "" {
+		host = net.JoinHostPort(host, r.Port)
+	}
+	return scheme + "://" + host
 }
 
 func (r *RemoteInfo) RepositoryUrl() string {
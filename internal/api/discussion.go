@@ -0,0 +1,88 @@
+package api
+
+import (
+	"fmt"
+
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+type Discussion interface {
+	GetMergeRequestDiscussions(repositoryName string, iid int) ([]*gitlab.Discussion, error)
+	CreateMergeRequestDiscussion(repositoryName string, iid int, opt *gitlab.CreateMergeRequestDiscussionOptions) (*gitlab.Discussion, error)
+	AddMergeRequestDiscussionNote(repositoryName string, iid int, discussion string, opt *gitlab.AddMergeRequestDiscussionNoteOptions) (*gitlab.Note, error)
+	ResolveMergeRequestDiscussion(repositoryName string, iid int, discussion string, resolved bool) (*gitlab.Discussion, error)
+}
+
+type DiscussionClient struct {
+	Discussion
+	Client *gitlab.Client
+}
+
+func NewDiscussionClient(client *gitlab.Client) *DiscussionClient {
+	return &DiscussionClient{Client: client}
+}
+
+// GetMergeRequestDiscussions reads the discussions of a merge request from
+// every page.
+func (c *DiscussionClient) GetMergeRequestDiscussions(repositoryName string, iid int) ([]*gitlab.Discussion, error) {
+	opt := &gitlab.ListMergeRequestDiscussionsOptions{Page: 1}
+	var discussions []*gitlab.Discussion
+	it := newPageIterator((*gitlab.ListOptions)(opt), 0)
+	for it.Next() {
+		page, res, err := c.Client.Discussions.ListMergeRequestDiscussions(repositoryName, iid, opt)
+		if err != nil {
+			return nil, fmt.Errorf("Failed get merge request discussions. %s", err.Error())
+		}
+		discussions = append(discussions, page[:it.Read(len(page), res)]...)
+	}
+	return discussions, nil
+}
+
+func (c *DiscussionClient) CreateMergeRequestDiscussion(repositoryName string, iid int, opt *gitlab.CreateMergeRequestDiscussionOptions) (*gitlab.Discussion, error) {
+	discussion, _, err := c.Client.Discussions.CreateMergeRequestDiscussion(repositoryName, iid, opt)
+	if err != nil {
+		return nil, fmt.Errorf("Failed create merge request discussion. %s", err.Error())
+	}
+	return discussion, nil
+}
+
+func (c *DiscussionClient) AddMergeRequestDiscussionNote(repositoryName string, iid int, discussion string, opt *gitlab.AddMergeRequestDiscussionNoteOptions) (*gitlab.Note, error) {
+	note, _, err := c.Client.Discussions.AddMergeRequestDiscussionNote(repositoryName, iid, discussion, opt)
+	if err != nil {
+		return nil, fmt.Errorf("Failed reply to merge request discussion. %s", err.Error())
+	}
+	return note, nil
+}
+
+func (c *DiscussionClient) ResolveMergeRequestDiscussion(repositoryName string, iid int, discussion string, resolved bool) (*gitlab.Discussion, error) {
+	opt := &gitlab.ResolveMergeRequestDiscussionOptions{Resolved: gitlab.Bool(resolved)}
+	d, _, err := c.Client.Discussions.ResolveMergeRequestDiscussion(repositoryName, iid, discussion, opt)
+	if err != nil {
+		return nil, fmt.Errorf("Failed resolve merge request discussion. %s", err.Error())
+	}
+	return d, nil
+}
+
+type MockDiscussionClient struct {
+	Discussion
+	MockGetMergeRequestDiscussions    func(repositoryName string, iid int) ([]*gitlab.Discussion, error)
+	MockCreateMergeRequestDiscussion  func(repositoryName string, iid int, opt *gitlab.CreateMergeRequestDiscussionOptions) (*gitlab.Discussion, error)
+	MockAddMergeRequestDiscussionNote func(repositoryName string, iid int, discussion string, opt *gitlab.AddMergeRequestDiscussionNoteOptions) (*gitlab.Note, error)
+	MockResolveMergeRequestDiscussion func(repositoryName string, iid int, discussion string, resolved bool) (*gitlab.Discussion, error)
+}
+
+func (m *MockDiscussionClient) GetMergeRequestDiscussions(repositoryName string, iid int) ([]*gitlab.Discussion, error) {
+	return m.MockGetMergeRequestDiscussions(repositoryName, iid)
+}
+
+func (m *MockDiscussionClient) CreateMergeRequestDiscussion(repositoryName string, iid int, opt *gitlab.CreateMergeRequestDiscussionOptions) (*gitlab.Discussion, error) {
+	return m.MockCreateMergeRequestDiscussion(repositoryName, iid, opt)
+}
+
+func (m *MockDiscussionClient) AddMergeRequestDiscussionNote(repositoryName string, iid int, discussion string, opt *gitlab.AddMergeRequestDiscussionNoteOptions) (*gitlab.Note, error) {
+	return m.MockAddMergeRequestDiscussionNote(repositoryName, iid, discussion, opt)
+}
+
+func (m *MockDiscussionClient) ResolveMergeRequestDiscussion(repositoryName string, iid int, discussion string, resolved bool) (*gitlab.Discussion, error) {
+	return m.MockResolveMergeRequestDiscussion(repositoryName, iid, discussion, resolved)
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// ResponseError is an error answered by GitLab. The commands tell the
+// reasons of a failure apart by the status code.
+type ResponseError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *ResponseError) Error() string {
+	return e.Message
+}
+
+// newResponseError returns the error with the status code of the response.
+// A plain error is returned when the request has no response.
+func newResponseError(message string, res *gitlab.Response, err error) error {
+	msg := fmt.Sprintf("%s. %s", message, err.Error())
+	if res == nil || res.Response == nil {
+		return errors.New(msg)
+	}
+	return &ResponseError{StatusCode: res.StatusCode, Message: msg}
+}
+
+// StatusCode returns the status code of a ResponseError, or 0 for other
+// errors.
+func StatusCode(err error) int {
+	if e, ok := err.(*ResponseError); ok {
+		return e.StatusCode
+	}
+	return 0
+}
@@ -1,13 +1,21 @@
 package api
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/lighttiger2505/lab/internal/config"
 	gitlab "github.com/xanzy/go-gitlab"
 )
 
 type APIClientFactory interface {
-	Init(url, token string) error
+	Init(url, token string, tlsSetting config.TLS) error
 	GetJobClient() Job
 	GetIssueClient() Issue
 	GetMergeRequestClient() MergeRequest
@@ -21,14 +29,18 @@ type APIClientFactory interface {
 	GetRunnerClient() Runner
 	GetMilestoneClient() Milestone
 	GetBranchClient() Branch
+	GetDiscussionClient() Discussion
+	GetLabelClient() Label
+	GetPipelineScheduleClient() PipelineSchedule
+	GetPipelineTriggerClient() PipelineTrigger
 }
 
 type GitlabClientFactory struct {
 	gitlabClient *gitlab.Client
 }
 
-func NewGitlabClientFactory(url, token string) (APIClientFactory, error) {
-	gitlabClient, err := getGitlabClient(url, token)
+func NewGitlabClientFactory(url, token string, tlsSetting config.TLS) (APIClientFactory, error) {
+	gitlabClient, err := getGitlabClient(url, token, tlsSetting)
 	if err != nil {
 		return nil, err
 	}
@@ -36,8 +48,8 @@ func NewGitlabClientFactory(url, token string) (APIClientFactory, error) {
 	return factory, nil
 }
 
-func (f *GitlabClientFactory) Init(url, token string) error {
-	gitlabClient, err := getGitlabClient(url, token)
+func (f *GitlabClientFactory) Init(url, token string, tlsSetting config.TLS) error {
+	gitlabClient, err := getGitlabClient(url, token, tlsSetting)
 	if err != nil {
 		return err
 	}
@@ -97,31 +109,104 @@ func (f *GitlabClientFactory) GetBranchClient() Branch {
 	return NewBranchClient(f.gitlabClient)
 }
 
-func getGitlabClient(url, token string) (*gitlab.Client, error) {
-	client := gitlab.NewClient(nil, token)
+func (f *GitlabClientFactory) GetDiscussionClient() Discussion {
+	return NewDiscussionClient(f.gitlabClient)
+}
+
+func (f *GitlabClientFactory) GetLabelClient() Label {
+	return NewLabelClient(f.gitlabClient)
+}
+
+func (f *GitlabClientFactory) GetPipelineScheduleClient() PipelineSchedule {
+	return NewPipelineScheduleClient(f.gitlabClient)
+}
+
+func (f *GitlabClientFactory) GetPipelineTriggerClient() PipelineTrigger {
+	return NewPipelineTriggerClient(f.gitlabClient)
+}
+
+func getGitlabClient(url, token string, tlsSetting config.TLS) (*gitlab.Client, error) {
+	httpClient, err := newHTTPClient(tlsSetting)
+	if err != nil {
+		return nil, err
+	}
+	client := gitlab.NewClient(httpClient, token)
 	if err := client.SetBaseURL(url); err != nil {
 		return nil, fmt.Errorf("Invalid base url for call GitLab API. %s", err.Error())
 	}
 	return client, nil
 }
 
+// newHTTPClient returns the http client applying the TLS setting of a
+// profile. It returns nil to use the default client when nothing is set.
+func newHTTPClient(tlsSetting config.TLS) (*http.Client, error) {
+	if tlsSetting == (config.TLS{}) {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: tlsSetting.InsecureSkipVerify,
+	}
+
+	if tlsSetting.CACert != "" {
+		pem, err := ioutil.ReadFile(expandHome(tlsSetting.CACert))
+		if err != nil {
+			return nil, fmt.Errorf("Failed read CA certificate. %s", err.Error())
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("Failed read CA certificate. No certificate in %s", tlsSetting.CACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if tlsSetting.ClientCert != "" || tlsSetting.ClientKey != "" {
+		cert, err := tls.LoadX509KeyPair(expandHome(tlsSetting.ClientCert), expandHome(tlsSetting.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("Failed read client certificate. %s", err.Error())
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+	}, nil
+}
+
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		return filepath.Join(os.Getenv("HOME"), path[2:])
+	}
+	return path
+}
+
 type MockAPIClientFactory struct {
-	MockGetJobClient             func() Job
-	MockGetIssueClient           func() Issue
-	MockGetMergeRequestClient    func() MergeRequest
-	MockGetProjectVariableClient func() ProjectVariable
-	MockGetRepositoryClient      func() Repository
-	MockGetNoteClient            func() Note
-	MockGetPipelineClient        func() Pipeline
-	MockGetProjectClient         func() Project
-	MockGetUserClient            func() User
-	MockGetLintClient            func() Lint
-	MockGetRunnerClient          func() Runner
-	MockGetMilestoneClient       func() Milestone
-	MockGetBranchClient          func() Branch
-}
-
-func (m *MockAPIClientFactory) Init(url, token string) error {
+	MockGetJobClient              func() Job
+	MockGetIssueClient            func() Issue
+	MockGetMergeRequestClient     func() MergeRequest
+	MockGetProjectVariableClient  func() ProjectVariable
+	MockGetRepositoryClient       func() Repository
+	MockGetNoteClient             func() Note
+	MockGetPipelineClient         func() Pipeline
+	MockGetProjectClient          func() Project
+	MockGetUserClient             func() User
+	MockGetLintClient             func() Lint
+	MockGetRunnerClient           func() Runner
+	MockGetMilestoneClient        func() Milestone
+	MockGetBranchClient           func() Branch
+	MockGetDiscussionClient       func() Discussion
+	MockGetLabelClient            func() Label
+	MockGetPipelineScheduleClient func() PipelineSchedule
+	MockGetPipelineTriggerClient  func() PipelineTrigger
+}
+
+func (m *MockAPIClientFactory) Init(url, token string, tlsSetting config.TLS) error {
 	return nil
 }
 
@@ -176,3 +261,19 @@ func (m *MockAPIClientFactory) GetMilestoneClient() Milestone {
 func (m *MockAPIClientFactory) GetBranchClient() Branch {
 	return m.MockGetBranchClient()
 }
+
+func (m *MockAPIClientFactory) GetDiscussionClient() Discussion {
+	return m.MockGetDiscussionClient()
+}
+
+func (m *MockAPIClientFactory) GetLabelClient() Label {
+	return m.MockGetLabelClient()
+}
+
+func (m *MockAPIClientFactory) GetPipelineScheduleClient() PipelineSchedule {
+	return m.MockGetPipelineScheduleClient()
+}
+
+func (m *MockAPIClientFactory) GetPipelineTriggerClient() PipelineTrigger {
+	return m.MockGetPipelineTriggerClient()
+}
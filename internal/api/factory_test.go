@@ -0,0 +1,75 @@
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/lighttiger2505/lab/internal/config"
+)
+
+func Test_newHTTPClient(t *testing.T) {
+	invalidPEM, err := ioutil.TempFile("", "ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(invalidPEM.Name())
+	invalidPEM.WriteString("not a certificate")
+	invalidPEM.Close()
+
+	tests := []struct {
+		name         string
+		tlsSetting   config.TLS
+		wantDefault  bool
+		wantInsecure bool
+		wantErr      bool
+	}{
+		{
+			name:        "default client",
+			tlsSetting:  config.TLS{},
+			wantDefault: true,
+		},
+		{
+			name:         "insecure skip verify",
+			tlsSetting:   config.TLS{InsecureSkipVerify: true},
+			wantInsecure: true,
+		},
+		{
+			name:       "missing CA certificate",
+			tlsSetting: config.TLS{CACert: "/not/found/ca.pem"},
+			wantErr:    true,
+		},
+		{
+			name:       "invalid CA certificate",
+			tlsSetting: config.TLS{CACert: invalidPEM.Name()},
+			wantErr:    true,
+		},
+		{
+			name:       "missing client key",
+			tlsSetting: config.TLS{ClientCert: invalidPEM.Name()},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newHTTPClient(tt.tlsSetting)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newHTTPClient() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if (got == nil) != tt.wantDefault {
+				t.Fatalf("newHTTPClient() = %v, want default client %v", got, tt.wantDefault)
+			}
+			if tt.wantDefault {
+				return
+			}
+			transport := got.Transport.(*http.Transport)
+			if transport.TLSClientConfig.InsecureSkipVerify != tt.wantInsecure {
+				t.Errorf("InsecureSkipVerify = %v, want %v", transport.TLSClientConfig.InsecureSkipVerify, tt.wantInsecure)
+			}
+		})
+	}
+}
@@ -9,8 +9,8 @@ import (
 
 type Issue interface {
 	GetIssue(pid int, repositoryName string) (*gitlab.Issue, error)
-	GetAllProjectIssues(opt *gitlab.ListIssuesOptions) ([]*gitlab.Issue, error)
-	GetProjectIssues(opt *gitlab.ListProjectIssuesOptions, repositoryName string) ([]*gitlab.Issue, error)
+	GetAllProjectIssues(opt *gitlab.ListIssuesOptions, limit int, f func([]*gitlab.Issue) error, options ...gitlab.OptionFunc) error
+	GetProjectIssues(opt *gitlab.ListProjectIssuesOptions, repositoryName string, limit int, f func([]*gitlab.Issue) error, options ...gitlab.OptionFunc) error
 	CreateIssue(opt *gitlab.CreateIssueOptions, repositoryName string) (*gitlab.Issue, error)
 	UpdateIssue(opt *gitlab.UpdateIssueOptions, pid int, repositoryName string) (*gitlab.Issue, error)
 }
@@ -32,20 +32,36 @@ func (c *IssueClient) GetIssue(pid int, repositoryName string) (*gitlab.Issue, e
 	return issue, nil
 }
 
-func (c *IssueClient) GetAllProjectIssues(opt *gitlab.ListIssuesOptions) ([]*gitlab.Issue, error) {
-	issues, _, err := c.Client.Issues.ListIssues(opt)
-	if err != nil {
-		return nil, fmt.Errorf("Failed list issue. %s", err.Error())
+// GetAllProjectIssues passes the issues to f page by page until limit issues
+// are read. Every page is read when limit is zero or less.
+func (c *IssueClient) GetAllProjectIssues(opt *gitlab.ListIssuesOptions, limit int, f func([]*gitlab.Issue) error, options ...gitlab.OptionFunc) error {
+	it := newPageIterator(&opt.ListOptions, limit)
+	for it.Next() {
+		issues, res, err := c.Client.Issues.ListIssues(opt, options...)
+		if err != nil {
+			return fmt.Errorf("Failed list issue. %s", err.Error())
+		}
+		if err := f(issues[:it.Read(len(issues), res)]); err != nil {
+			return err
+		}
 	}
-	return issues, nil
+	return nil
 }
 
-func (c *IssueClient) GetProjectIssues(opt *gitlab.ListProjectIssuesOptions, repositoryName string) ([]*gitlab.Issue, error) {
-	issues, _, err := c.Client.Issues.ListProjectIssues(repositoryName, opt)
-	if err != nil {
-		return nil, fmt.Errorf("Failed list project issue. %s", err.Error())
+// GetProjectIssues passes the issues of a project to f page by page until
+// limit issues are read. Every page is read when limit is zero or less.
+func (c *IssueClient) GetProjectIssues(opt *gitlab.ListProjectIssuesOptions, repositoryName string, limit int, f func([]*gitlab.Issue) error, options ...gitlab.OptionFunc) error {
+	it := newPageIterator(&opt.ListOptions, limit)
+	for it.Next() {
+		issues, res, err := c.Client.Issues.ListProjectIssues(repositoryName, opt, options...)
+		if err != nil {
+			return fmt.Errorf("Failed list project issue. %s", err.Error())
+		}
+		if err := f(issues[:it.Read(len(issues), res)]); err != nil {
+			return err
+		}
 	}
-	return issues, nil
+	return nil
 }
 
 func (c *IssueClient) CreateIssue(opt *gitlab.CreateIssueOptions, repositoryName string) (*gitlab.Issue, error) {
@@ -81,12 +97,20 @@ func (m *MockLabIssueClient) GetIssue(pid int, repositoryName string) (*gitlab.I
 	return m.MockGetIssue(pid, repositoryName)
 }
 
-func (m *MockLabIssueClient) GetAllProjectIssues(opt *gitlab.ListIssuesOptions) ([]*gitlab.Issue, error) {
-	return m.MockGetAllProjectIssues(opt)
+func (m *MockLabIssueClient) GetAllProjectIssues(opt *gitlab.ListIssuesOptions, limit int, f func([]*gitlab.Issue) error, options ...gitlab.OptionFunc) error {
+	issues, err := m.MockGetAllProjectIssues(opt)
+	if err != nil {
+		return err
+	}
+	return f(issues)
 }
 
-func (m *MockLabIssueClient) GetProjectIssues(opt *gitlab.ListProjectIssuesOptions, repositoryName string) ([]*gitlab.Issue, error) {
-	return m.MockGetProjectIssues(opt, repositoryName)
+func (m *MockLabIssueClient) GetProjectIssues(opt *gitlab.ListProjectIssuesOptions, repositoryName string, limit int, f func([]*gitlab.Issue) error, options ...gitlab.OptionFunc) error {
+	issues, err := m.MockGetProjectIssues(opt, repositoryName)
+	if err != nil {
+		return err
+	}
+	return f(issues)
 }
 
 func (m *MockLabIssueClient) CreateIssue(opt *gitlab.CreateIssueOptions, repositoryName string) (*gitlab.Issue, error) {
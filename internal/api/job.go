@@ -3,14 +3,26 @@ package api
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
 
 	gitlab "github.com/xanzy/go-gitlab"
 )
 
 type Job interface {
-	GetProjectJobs(opt *gitlab.ListJobsOptions, repositoryName string) ([]gitlab.Job, error)
+	GetProjectJobs(opt *gitlab.ListJobsOptions, repositoryName string, limit int, f func([]gitlab.Job) error) error
 	GetJob(repositoryName string, jobID int) (*gitlab.Job, error)
 	GetTraceFile(repositoryName string, jobID int) (io.Reader, error)
+	GetTraceFileFrom(repositoryName string, jobID int, offset int) ([]byte, error)
+	PlayJob(repositoryName string, jobID int) (*gitlab.Job, error)
+	RetryJob(repositoryName string, jobID int) (*gitlab.Job, error)
+	CancelJob(repositoryName string, jobID int) (*gitlab.Job, error)
+	EraseJob(repositoryName string, jobID int) (*gitlab.Job, error)
+	DownloadArtifacts(repositoryName string, jobID int, w io.Writer) error
+	DownloadArtifactsByRef(repositoryName string, ref string, jobName string, w io.Writer) error
+	DownloadArtifactFile(repositoryName string, jobID int, artifactPath string, w io.Writer) error
 }
 
 type JobClient struct {
@@ -22,12 +34,20 @@ func NewJobClient(client *gitlab.Client) *JobClient {
 	return &JobClient{Client: client}
 }
 
-func (c *JobClient) GetProjectJobs(opt *gitlab.ListJobsOptions, repositoryName string) ([]gitlab.Job, error) {
-	jobs, _, err := c.Client.Jobs.ListProjectJobs(repositoryName, opt)
-	if err != nil {
-		return nil, fmt.Errorf("Failed list project jobs. %s", err.Error())
+// GetProjectJobs passes the jobs of a project to f page by page until limit
+// jobs are read. Every page is read when limit is zero or less.
+func (c *JobClient) GetProjectJobs(opt *gitlab.ListJobsOptions, repositoryName string, limit int, f func([]gitlab.Job) error) error {
+	it := newPageIterator(&opt.ListOptions, limit)
+	for it.Next() {
+		jobs, res, err := c.Client.Jobs.ListProjectJobs(repositoryName, opt)
+		if err != nil {
+			return fmt.Errorf("Failed list project jobs. %s", err.Error())
+		}
+		if err := f(jobs[:it.Read(len(jobs), res)]); err != nil {
+			return err
+		}
 	}
-	return jobs, nil
+	return nil
 }
 
 func (c *JobClient) GetJob(repositoryName string, jobID int) (*gitlab.Job, error) {
@@ -46,11 +66,177 @@ func (c *JobClient) GetTraceFile(repositoryName string, jobID int) (io.Reader, e
 	return trace, nil
 }
 
+// GetTraceFileFrom returns the trace of a job after offset bytes. GitLab is
+// asked for the rest by the Range header, and the head is skipped here when
+// GitLab answers the whole trace.
+func (c *JobClient) GetTraceFileFrom(repositoryName string, jobID int, offset int) ([]byte, error) {
+	trace, res, err := c.Client.Jobs.GetTraceFile(repositoryName, jobID, withRange(offset))
+	if err != nil {
+		if res != nil && res.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+			// Nothing is written after offset yet
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Failed get trace file. %s", err.Error())
+	}
+
+	b, err := ioutil.ReadAll(trace)
+	if err != nil {
+		return nil, fmt.Errorf("Failed read trace file. %s", err.Error())
+	}
+	if res.StatusCode == http.StatusPartialContent {
+		return b, nil
+	}
+	if offset > len(b) {
+		return nil, nil
+	}
+	return b[offset:], nil
+}
+
+func (c *JobClient) PlayJob(repositoryName string, jobID int) (*gitlab.Job, error) {
+	job, _, err := c.Client.Jobs.PlayJob(repositoryName, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed play job. %s", err.Error())
+	}
+	return job, nil
+}
+
+func (c *JobClient) RetryJob(repositoryName string, jobID int) (*gitlab.Job, error) {
+	job, _, err := c.Client.Jobs.RetryJob(repositoryName, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed retry job. %s", err.Error())
+	}
+	return job, nil
+}
+
+func (c *JobClient) CancelJob(repositoryName string, jobID int) (*gitlab.Job, error) {
+	job, _, err := c.Client.Jobs.CancelJob(repositoryName, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed cancel job. %s", err.Error())
+	}
+	return job, nil
+}
+
+// EraseJob removes the trace and the artifacts of a job.
+func (c *JobClient) EraseJob(repositoryName string, jobID int) (*gitlab.Job, error) {
+	job, _, err := c.Client.Jobs.EraseJob(repositoryName, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed erase job. %s", err.Error())
+	}
+	return job, nil
+}
+
+// DownloadArtifacts writes the artifacts archive of a job to w.
+func (c *JobClient) DownloadArtifacts(repositoryName string, jobID int, w io.Writer) error {
+	u := fmt.Sprintf("projects/%s/jobs/%d/artifacts", url.QueryEscape(repositoryName), jobID)
+	if err := c.download(u, nil, w); err != nil {
+		return fmt.Errorf("Failed download artifacts. %s", err.Error())
+	}
+	return nil
+}
+
+// DownloadArtifactsByRef writes the artifacts archive of the latest
+// successful job of the name on ref to w.
+func (c *JobClient) DownloadArtifactsByRef(repositoryName string, ref string, jobName string, w io.Writer) error {
+	u := fmt.Sprintf("projects/%s/jobs/artifacts/%s/download", url.QueryEscape(repositoryName), url.PathEscape(ref))
+	opt := &downloadArtifactsOptions{Job: jobName}
+	if err := c.download(u, opt, w); err != nil {
+		return fmt.Errorf("Failed download artifacts. %s", err.Error())
+	}
+	return nil
+}
+
+// DownloadArtifactFile writes a single file in the artifacts archive of a
+// job to w.
+func (c *JobClient) DownloadArtifactFile(repositoryName string, jobID int, artifactPath string, w io.Writer) error {
+	var segments []string
+	for _, segment := range strings.Split(strings.Trim(artifactPath, "/"), "/") {
+		segments = append(segments, url.PathEscape(segment))
+	}
+	u := fmt.Sprintf("projects/%s/jobs/%d/artifacts/%s", url.QueryEscape(repositoryName), jobID, strings.Join(segments, "/"))
+	if err := c.download(u, nil, w); err != nil {
+		return fmt.Errorf("Failed download artifact file. %s", err.Error())
+	}
+	return nil
+}
+
+type downloadArtifactsOptions struct {
+	Job string `url:"job"`
+}
+
+// download streams the response body to w. go-gitlab buffers the whole
+// archive before returning it, and does not escape the ref and the path.
+func (c *JobClient) download(u string, opt interface{}, w io.Writer) error {
+	req, err := c.Client.NewRequest("GET", u, opt, nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.Client.Do(req, w)
+	return err
+}
+
+func withRange(offset int) gitlab.OptionFunc {
+	return func(req *http.Request) error {
+		if offset > 0 {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		}
+		return nil
+	}
+}
+
 type MockLabJobClient struct {
 	Job
-	MockGetProjectJobs func(opt *gitlab.ListJobsOptions, repositoryName string) ([]*gitlab.Job, error)
+	MockGetProjectJobs         func(opt *gitlab.ListJobsOptions, repositoryName string) ([]gitlab.Job, error)
+	MockGetJob                 func(repositoryName string, jobID int) (*gitlab.Job, error)
+	MockGetTraceFileFrom       func(repositoryName string, jobID int, offset int) ([]byte, error)
+	MockPlayJob                func(repositoryName string, jobID int) (*gitlab.Job, error)
+	MockRetryJob               func(repositoryName string, jobID int) (*gitlab.Job, error)
+	MockCancelJob              func(repositoryName string, jobID int) (*gitlab.Job, error)
+	MockEraseJob               func(repositoryName string, jobID int) (*gitlab.Job, error)
+	MockDownloadArtifacts      func(repositoryName string, jobID int, w io.Writer) error
+	MockDownloadArtifactsByRef func(repositoryName string, ref string, jobName string, w io.Writer) error
+	MockDownloadArtifactFile   func(repositoryName string, jobID int, artifactPath string, w io.Writer) error
+}
+
+func (m *MockLabJobClient) GetProjectJobs(opt *gitlab.ListJobsOptions, repositoryName string, limit int, f func([]gitlab.Job) error) error {
+	jobs, err := m.MockGetProjectJobs(opt, repositoryName)
+	if err != nil {
+		return err
+	}
+	return f(jobs)
+}
+
+func (m *MockLabJobClient) GetJob(repositoryName string, jobID int) (*gitlab.Job, error) {
+	return m.MockGetJob(repositoryName, jobID)
+}
+
+func (m *MockLabJobClient) GetTraceFileFrom(repositoryName string, jobID int, offset int) ([]byte, error) {
+	return m.MockGetTraceFileFrom(repositoryName, jobID, offset)
+}
+
+func (m *MockLabJobClient) PlayJob(repositoryName string, jobID int) (*gitlab.Job, error) {
+	return m.MockPlayJob(repositoryName, jobID)
+}
+
+func (m *MockLabJobClient) RetryJob(repositoryName string, jobID int) (*gitlab.Job, error) {
+	return m.MockRetryJob(repositoryName, jobID)
+}
+
+func (m *MockLabJobClient) CancelJob(repositoryName string, jobID int) (*gitlab.Job, error) {
+	return m.MockCancelJob(repositoryName, jobID)
+}
+
+func (m *MockLabJobClient) EraseJob(repositoryName string, jobID int) (*gitlab.Job, error) {
+	return m.MockEraseJob(repositoryName, jobID)
+}
+
+func (m *MockLabJobClient) DownloadArtifacts(repositoryName string, jobID int, w io.Writer) error {
+	return m.MockDownloadArtifacts(repositoryName, jobID, w)
+}
+
+func (m *MockLabJobClient) DownloadArtifactsByRef(repositoryName string, ref string, jobName string, w io.Writer) error {
+	return m.MockDownloadArtifactsByRef(repositoryName, ref, jobName, w)
 }
 
-func (m *MockLabJobClient) GetProjectJobs(opt *gitlab.ListJobsOptions, repositoryName string) ([]*gitlab.Job, error) {
-	return m.MockGetProjectJobs(opt, repositoryName)
+func (m *MockLabJobClient) DownloadArtifactFile(repositoryName string, jobID int, artifactPath string, w io.Writer) error {
+	return m.MockDownloadArtifactFile(repositoryName, jobID, artifactPath, w)
 }
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+type Label interface {
+	ListLabels(project string, opt *gitlab.ListLabelsOptions, limit int, f func([]*gitlab.Label) error) error
+	ListGroupLabels(group string, opt *gitlab.ListLabelsOptions, limit int, f func([]*gitlab.Label) error) error
+	CreateLabel(project string, opt *gitlab.CreateLabelOptions) (*gitlab.Label, error)
+	CreateGroupLabel(group string, opt *gitlab.CreateLabelOptions) (*gitlab.Label, error)
+	UpdateLabel(project string, opt *gitlab.UpdateLabelOptions) (*gitlab.Label, error)
+	UpdateGroupLabel(group string, opt *gitlab.UpdateLabelOptions) (*gitlab.Label, error)
+	DeleteLabel(project string, name string) error
+	DeleteGroupLabel(group string, name string) error
+}
+
+// WithNotLabels excludes the issues or merge requests having any of the
+// labels from a list, which the list options of go-gitlab can not do yet.
+func WithNotLabels(labels []string) gitlab.OptionFunc {
+	return func(req *http.Request) error {
+		q := req.URL.Query()
+		q.Set("not[labels]", strings.Join(labels, ","))
+		req.URL.RawQuery = q.Encode()
+		return nil
+	}
+}
+
+type LabelClient struct {
+	Client *gitlab.Client
+}
+
+func NewLabelClient(client *gitlab.Client) *LabelClient {
+	return &LabelClient{Client: client}
+}
+
+// ListLabels passes the labels of a project to f page by page until limit
+// labels are read. Every page is read when limit is zero or less.
+func (c *LabelClient) ListLabels(project string, opt *gitlab.ListLabelsOptions, limit int, f func([]*gitlab.Label) error) error {
+	it := newPageIterator((*gitlab.ListOptions)(opt), limit)
+	for it.Next() {
+		labels, res, err := c.Client.Labels.ListLabels(project, opt)
+		if err != nil {
+			return fmt.Errorf("Failed list label. %s", err.Error())
+		}
+		if err := f(labels[:it.Read(len(labels), res)]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListGroupLabels passes the labels of a group to f page by page like
+// ListLabels. go-gitlab has no group labels yet, so the requests are made
+// here.
+func (c *LabelClient) ListGroupLabels(group string, opt *gitlab.ListLabelsOptions, limit int, f func([]*gitlab.Label) error) error {
+	it := newPageIterator((*gitlab.ListOptions)(opt), limit)
+	for it.Next() {
+		var labels []*gitlab.Label
+		res, err := c.doGroupLabels("GET", group, opt, &labels)
+		if err != nil {
+			return fmt.Errorf("Failed list group label. %s", err.Error())
+		}
+		if err := f(labels[:it.Read(len(labels), res)]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *LabelClient) CreateLabel(project string, opt *gitlab.CreateLabelOptions) (*gitlab.Label, error) {
+	label, _, err := c.Client.Labels.CreateLabel(project, opt)
+	if err != nil {
+		return nil, fmt.Errorf("Failed create label. %s", err.Error())
+	}
+	return label, nil
+}
+
+func (c *LabelClient) CreateGroupLabel(group string, opt *gitlab.CreateLabelOptions) (*gitlab.Label, error) {
+	label := new(gitlab.Label)
+	if _, err := c.doGroupLabels("POST", group, opt, label); err != nil {
+		return nil, fmt.Errorf("Failed create group label. %s", err.Error())
+	}
+	return label, nil
+}
+
+func (c *LabelClient) UpdateLabel(project string, opt *gitlab.UpdateLabelOptions) (*gitlab.Label, error) {
+	label, _, err := c.Client.Labels.UpdateLabel(project, opt)
+	if err != nil {
+		return nil, fmt.Errorf("Failed update label. %s", err.Error())
+	}
+	return label, nil
+}
+
+func (c *LabelClient) UpdateGroupLabel(group string, opt *gitlab.UpdateLabelOptions) (*gitlab.Label, error) {
+	label := new(gitlab.Label)
+	if _, err := c.doGroupLabels("PUT", group, opt, label); err != nil {
+		return nil, fmt.Errorf("Failed update group label. %s", err.Error())
+	}
+	return label, nil
+}
+
+func (c *LabelClient) DeleteLabel(project string, name string) error {
+	opt := &gitlab.DeleteLabelOptions{Name: gitlab.String(name)}
+	if _, err := c.Client.Labels.DeleteLabel(project, opt); err != nil {
+		return fmt.Errorf("Failed delete label. %s", err.Error())
+	}
+	return nil
+}
+
+func (c *LabelClient) DeleteGroupLabel(group string, name string) error {
+	opt := &gitlab.DeleteLabelOptions{Name: gitlab.String(name)}
+	if _, err := c.doGroupLabels("DELETE", group, opt, nil); err != nil {
+		return fmt.Errorf("Failed delete group label. %s", err.Error())
+	}
+	return nil
+}
+
+// doGroupLabels requests the labels of a group. The group labels take the
+// same options as the project labels.
+func (c *LabelClient) doGroupLabels(method, group string, opt interface{}, v interface{}) (*gitlab.Response, error) {
+	u := fmt.Sprintf("groups/%s/labels", url.QueryEscape(group))
+	req, err := c.Client.NewRequest(method, u, opt, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req, v)
+}
+
+type MockLabelClient struct {
+	Label
+	MockListLabels       func(project string, opt *gitlab.ListLabelsOptions) ([]*gitlab.Label, error)
+	MockListGroupLabels  func(group string, opt *gitlab.ListLabelsOptions) ([]*gitlab.Label, error)
+	MockCreateLabel      func(project string, opt *gitlab.CreateLabelOptions) (*gitlab.Label, error)
+	MockCreateGroupLabel func(group string, opt *gitlab.CreateLabelOptions) (*gitlab.Label, error)
+	MockUpdateLabel      func(project string, opt *gitlab.UpdateLabelOptions) (*gitlab.Label, error)
+	MockUpdateGroupLabel func(group string, opt *gitlab.UpdateLabelOptions) (*gitlab.Label, error)
+	MockDeleteLabel      func(project string, name string) error
+	MockDeleteGroupLabel func(group string, name string) error
+}
+
+func (m *MockLabelClient) ListLabels(project string, opt *gitlab.ListLabelsOptions, limit int, f func([]*gitlab.Label) error) error {
+	labels, err := m.MockListLabels(project, opt)
+	if err != nil {
+		return err
+	}
+	return f(labels)
+}
+
+func (m *MockLabelClient) ListGroupLabels(group string, opt *gitlab.ListLabelsOptions, limit int, f func([]*gitlab.Label) error) error {
+	labels, err := m.MockListGroupLabels(group, opt)
+	if err != nil {
+		return err
+	}
+	return f(labels)
+}
+
+func (m *MockLabelClient) CreateLabel(project string, opt *gitlab.CreateLabelOptions) (*gitlab.Label, error) {
+	return m.MockCreateLabel(project, opt)
+}
+
+func (m *MockLabelClient) CreateGroupLabel(group string, opt *gitlab.CreateLabelOptions) (*gitlab.Label, error) {
+	return m.MockCreateGroupLabel(group, opt)
+}
+
+func (m *MockLabelClient) UpdateLabel(project string, opt *gitlab.UpdateLabelOptions) (*gitlab.Label, error) {
+	return m.MockUpdateLabel(project, opt)
+}
+
+func (m *MockLabelClient) UpdateGroupLabel(group string, opt *gitlab.UpdateLabelOptions) (*gitlab.Label, error) {
+	return m.MockUpdateGroupLabel(group, opt)
+}
+
+func (m *MockLabelClient) DeleteLabel(project string, name string) error {
+	return m.MockDeleteLabel(project, name)
+}
+
+func (m *MockLabelClient) DeleteGroupLabel(group string, name string) error {
+	return m.MockDeleteGroupLabel(group, name)
+}
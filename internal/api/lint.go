@@ -2,12 +2,47 @@ package api
 
 import (
 	"fmt"
+	"net/url"
 
 	gitlab "github.com/xanzy/go-gitlab"
 )
 
+// LintResult is the result of linting a CI config. The lint of the instance
+// answers the status, and the lint of a project answers valid.
+type LintResult struct {
+	Status     string     `json:"status"`
+	Valid      bool       `json:"valid"`
+	Errors     []string   `json:"errors"`
+	Warnings   []string   `json:"warnings"`
+	MergedYaml string     `json:"merged_yaml"`
+	Jobs       []*LintJob `json:"jobs"`
+}
+
+// IsValid returns whether the CI config has no errors.
+func (r *LintResult) IsValid() bool {
+	return r.Valid || r.Status == "valid"
+}
+
+// LintJob is a job that the pipeline of the linted CI config would run.
+type LintJob struct {
+	Name         string `json:"name"`
+	Stage        string `json:"stage"`
+	When         string `json:"when"`
+	AllowFailure bool   `json:"allow_failure"`
+}
+
+// ProjectLintOptions are the options to lint the CI config in the context of
+// a project.
+type ProjectLintOptions struct {
+	Content     string `url:"content,omitempty" json:"content,omitempty"`
+	Ref         string `url:"ref,omitempty" json:"ref,omitempty"`
+	DryRun      bool   `url:"dry_run,omitempty" json:"dry_run,omitempty"`
+	IncludeJobs bool   `url:"include_jobs,omitempty" json:"include_jobs,omitempty"`
+}
+
 type Lint interface {
-	Lint(content string) (*gitlab.LintResult, error)
+	Lint(content string) (*LintResult, error)
+	ProjectLint(repositoryName string, opt *ProjectLintOptions) (*LintResult, error)
 }
 
 type LintClient struct {
@@ -18,18 +53,58 @@ func NewLintClient(client *gitlab.Client) *LintClient {
 	return &LintClient{Client: client}
 }
 
-func (c *LintClient) Lint(content string) (*gitlab.LintResult, error) {
-	lintResult, _, err := c.Client.Validate.Lint(content)
+// Lint validates the content of a CI config, and answers it with the
+// includes merged. go-gitlab does not read the warnings and the merged YAML.
+func (c *LintClient) Lint(content string) (*LintResult, error) {
+	opt := struct {
+		Content           string `json:"content"`
+		IncludeMergedYaml bool   `json:"include_merged_yaml"`
+	}{
+		Content:           content,
+		IncludeMergedYaml: true,
+	}
+	req, err := c.Client.NewRequest("POST", "ci/lint", &opt, nil)
 	if err != nil {
 		return nil, fmt.Errorf("Failed lint. Error: %s", err.Error())
 	}
-	return lintResult, nil
+
+	result := new(LintResult)
+	if _, err := c.Client.Do(req, result); err != nil {
+		return nil, fmt.Errorf("Failed lint. Error: %s", err.Error())
+	}
+	return result, nil
+}
+
+// ProjectLint validates a CI config in the context of a project, so that
+// local includes and variables of the project are used. The CI config of the
+// project at the ref is validated when no content is given.
+func (c *LintClient) ProjectLint(repositoryName string, opt *ProjectLintOptions) (*LintResult, error) {
+	u := fmt.Sprintf("projects/%s/ci/lint", url.QueryEscape(repositoryName))
+	method := "GET"
+	if opt.Content != "" {
+		method = "POST"
+	}
+	req, err := c.Client.NewRequest(method, u, opt, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed lint. Error: %s", err.Error())
+	}
+
+	result := new(LintResult)
+	if _, err := c.Client.Do(req, result); err != nil {
+		return nil, fmt.Errorf("Failed lint. Error: %s", err.Error())
+	}
+	return result, nil
 }
 
 type MockLintClient struct {
-	MockLint func(content string) (*gitlab.LintResult, error)
+	MockLint        func(content string) (*LintResult, error)
+	MockProjectLint func(repositoryName string, opt *ProjectLintOptions) (*LintResult, error)
 }
 
-func (m *MockLintClient) Lint(content string) (*gitlab.LintResult, error) {
+func (m *MockLintClient) Lint(content string) (*LintResult, error) {
 	return m.MockLint(content)
 }
+
+func (m *MockLintClient) ProjectLint(repositoryName string, opt *ProjectLintOptions) (*LintResult, error) {
+	return m.MockProjectLint(repositoryName, opt)
+}
@@ -2,16 +2,31 @@ package api
 
 import (
 	"fmt"
+	"net/url"
 
 	gitlab "github.com/xanzy/go-gitlab"
 )
 
 type MergeRequest interface {
 	GetMergeRequest(pid int, repositoryName string) (*gitlab.MergeRequest, error)
-	GetAllProjectMergeRequest(opt *gitlab.ListMergeRequestsOptions) ([]*gitlab.MergeRequest, error)
-	GetProjectMargeRequest(opt *gitlab.ListProjectMergeRequestsOptions, repositoryName string) ([]*gitlab.MergeRequest, error)
+	GetMergeRequestChanges(pid int, repositoryName string) (*gitlab.MergeRequest, error)
+	GetAllProjectMergeRequest(opt *gitlab.ListMergeRequestsOptions, limit int, f func([]*gitlab.MergeRequest) error, options ...gitlab.OptionFunc) error
+	GetProjectMargeRequest(opt *gitlab.ListProjectMergeRequestsOptions, repositoryName string, limit int, f func([]*gitlab.MergeRequest) error, options ...gitlab.OptionFunc) error
 	CreateMergeRequest(opt *gitlab.CreateMergeRequestOptions, repositoryName string) (*gitlab.MergeRequest, error)
 	UpdateMergeRequest(opt *gitlab.UpdateMergeRequestOptions, pid int, repositoryName string) (*gitlab.MergeRequest, error)
+	AcceptMergeRequest(opt *AcceptMergeRequestOptions, pid int, repositoryName string) (*gitlab.MergeRequest, error)
+	GetMergeRequestApprovals(pid int, repositoryName string) (*gitlab.MergeRequestApprovals, error)
+	ApproveMergeRequest(opt *gitlab.ApproveMergeRequestOptions, pid int, repositoryName string) (*gitlab.MergeRequestApprovals, error)
+	UnapproveMergeRequest(pid int, repositoryName string) error
+	GetMergeRequestDiffVersions(pid int, repositoryName string) ([]*gitlab.MergeRequestDiffVersion, error)
+}
+
+// AcceptMergeRequestOptions adds the squash options, which go-gitlab does not
+// have yet, to the options of accepting a merge request.
+type AcceptMergeRequestOptions struct {
+	gitlab.AcceptMergeRequestOptions
+	Squash              *bool   `url:"squash,omitempty" json:"squash,omitempty"`
+	SquashCommitMessage *string `url:"squash_commit_message,omitempty" json:"squash_commit_message,omitempty"`
 }
 
 type MergeRequestClient struct {
@@ -31,22 +46,47 @@ func (l *MergeRequestClient) GetMergeRequest(pid int, repositoryName string) (*g
 	return mergeRequest, nil
 }
 
-func (l *MergeRequestClient) GetAllProjectMergeRequest(opt *gitlab.ListMergeRequestsOptions) ([]*gitlab.MergeRequest, error) {
-	mergeRequests, _, err := l.Client.MergeRequests.ListMergeRequests(opt)
+// GetMergeRequestChanges returns the merge request with the changes of its
+// files.
+func (l *MergeRequestClient) GetMergeRequestChanges(pid int, repositoryName string) (*gitlab.MergeRequest, error) {
+	mergeRequest, _, err := l.Client.MergeRequests.GetMergeRequestChanges(repositoryName, pid)
 	if err != nil {
-		return nil, fmt.Errorf("Failed list merge requests. %s", err.Error())
+		return nil, fmt.Errorf("Failed get merge request changes. %s", err.Error())
 	}
-
-	return mergeRequests, nil
+	return mergeRequest, nil
 }
 
-func (l *MergeRequestClient) GetProjectMargeRequest(opt *gitlab.ListProjectMergeRequestsOptions, repositoryName string) ([]*gitlab.MergeRequest, error) {
-	mergeRequests, _, err := l.Client.MergeRequests.ListProjectMergeRequests(repositoryName, opt)
-	if err != nil {
-		return nil, fmt.Errorf("Failed list project merge requests. %s", err.Error())
+// GetAllProjectMergeRequest passes the merge requests to f page by page until
+// limit merge requests are read. Every page is read when limit is zero or less.
+func (l *MergeRequestClient) GetAllProjectMergeRequest(opt *gitlab.ListMergeRequestsOptions, limit int, f func([]*gitlab.MergeRequest) error, options ...gitlab.OptionFunc) error {
+	it := newPageIterator(&opt.ListOptions, limit)
+	for it.Next() {
+		mergeRequests, res, err := l.Client.MergeRequests.ListMergeRequests(opt, options...)
+		if err != nil {
+			return fmt.Errorf("Failed list merge requests. %s", err.Error())
+		}
+		if err := f(mergeRequests[:it.Read(len(mergeRequests), res)]); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	return mergeRequests, nil
+// GetProjectMargeRequest passes the merge requests of a project to f page by
+// page until limit merge requests are read. Every page is read when limit is
+// zero or less.
+func (l *MergeRequestClient) GetProjectMargeRequest(opt *gitlab.ListProjectMergeRequestsOptions, repositoryName string, limit int, f func([]*gitlab.MergeRequest) error, options ...gitlab.OptionFunc) error {
+	it := newPageIterator(&opt.ListOptions, limit)
+	for it.Next() {
+		mergeRequests, res, err := l.Client.MergeRequests.ListProjectMergeRequests(repositoryName, opt, options...)
+		if err != nil {
+			return fmt.Errorf("Failed list project merge requests. %s", err.Error())
+		}
+		if err := f(mergeRequests[:it.Read(len(mergeRequests), res)]); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (l *MergeRequestClient) CreateMergeRequest(opt *gitlab.CreateMergeRequestOptions, repositoryName string) (*gitlab.MergeRequest, error) {
@@ -68,25 +108,96 @@ func (l *MergeRequestClient) UpdateMergeRequest(opt *gitlab.UpdateMergeRequestOp
 	return mergeRequest, nil
 }
 
+// AcceptMergeRequest merges the merge request. The error is a ResponseError
+// when GitLab refuses to merge it.
+func (l *MergeRequestClient) AcceptMergeRequest(opt *AcceptMergeRequestOptions, pid int, repositoryName string) (*gitlab.MergeRequest, error) {
+	u := fmt.Sprintf("projects/%s/merge_requests/%d/merge", url.QueryEscape(repositoryName), pid)
+	req, err := l.Client.NewRequest("PUT", u, opt, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed accept merge request. %s", err.Error())
+	}
+
+	mergeRequest := new(gitlab.MergeRequest)
+	res, err := l.Client.Do(req, mergeRequest)
+	if err != nil {
+		return nil, newResponseError("Failed accept merge request", res, err)
+	}
+	return mergeRequest, nil
+}
+
+// GetMergeRequestApprovals returns the approvals of the merge request. The
+// error is a ResponseError when GitLab has no approvals.
+func (l *MergeRequestClient) GetMergeRequestApprovals(pid int, repositoryName string) (*gitlab.MergeRequestApprovals, error) {
+	approvals, res, err := l.Client.MergeRequests.GetMergeRequestApprovals(repositoryName, pid)
+	if err != nil {
+		return nil, newResponseError("Failed get merge request approvals", res, err)
+	}
+	return approvals, nil
+}
+
+func (l *MergeRequestClient) ApproveMergeRequest(opt *gitlab.ApproveMergeRequestOptions, pid int, repositoryName string) (*gitlab.MergeRequestApprovals, error) {
+	approvals, res, err := l.Client.MergeRequestApprovals.ApproveMergeRequest(repositoryName, pid, opt)
+	if err != nil {
+		return nil, newResponseError("Failed approve merge request", res, err)
+	}
+	return approvals, nil
+}
+
+func (l *MergeRequestClient) UnapproveMergeRequest(pid int, repositoryName string) error {
+	res, err := l.Client.MergeRequestApprovals.UnapproveMergeRequest(repositoryName, pid)
+	if err != nil {
+		return newResponseError("Failed unapprove merge request", res, err)
+	}
+	return nil
+}
+
+// GetMergeRequestDiffVersions returns the versions of the diff of the merge
+// request, the latest first.
+func (l *MergeRequestClient) GetMergeRequestDiffVersions(pid int, repositoryName string) ([]*gitlab.MergeRequestDiffVersion, error) {
+	versions, _, err := l.Client.MergeRequests.GetMergeRequestDiffVersions(repositoryName, pid, &gitlab.GetMergeRequestDiffVersionsOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("Failed get merge request diff versions. %s", err.Error())
+	}
+	return versions, nil
+}
+
 type MockLabMergeRequestClient struct {
 	MergeRequest
-	MockGetMergeRequest           func(pid int, repositoryName string) (*gitlab.MergeRequest, error)
-	MockGetAllProjectMergeRequest func(opt *gitlab.ListMergeRequestsOptions) ([]*gitlab.MergeRequest, error)
-	MockGetProjectMargeRequest    func(opt *gitlab.ListProjectMergeRequestsOptions, repositoryName string) ([]*gitlab.MergeRequest, error)
-	MockCreateMergeRequest        func(opt *gitlab.CreateMergeRequestOptions, repositoryName string) (*gitlab.MergeRequest, error)
-	MockUpdateMergeRequest        func(opt *gitlab.UpdateMergeRequestOptions, pid int, repositoryName string) (*gitlab.MergeRequest, error)
+	MockGetMergeRequest             func(pid int, repositoryName string) (*gitlab.MergeRequest, error)
+	MockGetMergeRequestChanges      func(pid int, repositoryName string) (*gitlab.MergeRequest, error)
+	MockGetAllProjectMergeRequest   func(opt *gitlab.ListMergeRequestsOptions) ([]*gitlab.MergeRequest, error)
+	MockGetProjectMargeRequest      func(opt *gitlab.ListProjectMergeRequestsOptions, repositoryName string) ([]*gitlab.MergeRequest, error)
+	MockCreateMergeRequest          func(opt *gitlab.CreateMergeRequestOptions, repositoryName string) (*gitlab.MergeRequest, error)
+	MockUpdateMergeRequest          func(opt *gitlab.UpdateMergeRequestOptions, pid int, repositoryName string) (*gitlab.MergeRequest, error)
+	MockAcceptMergeRequest          func(opt *AcceptMergeRequestOptions, pid int, repositoryName string) (*gitlab.MergeRequest, error)
+	MockGetMergeRequestApprovals    func(pid int, repositoryName string) (*gitlab.MergeRequestApprovals, error)
+	MockApproveMergeRequest         func(opt *gitlab.ApproveMergeRequestOptions, pid int, repositoryName string) (*gitlab.MergeRequestApprovals, error)
+	MockUnapproveMergeRequest       func(pid int, repositoryName string) error
+	MockGetMergeRequestDiffVersions func(pid int, repositoryName string) ([]*gitlab.MergeRequestDiffVersion, error)
 }
 
 func (m *MockLabMergeRequestClient) GetMergeRequest(pid int, repositoryName string) (*gitlab.MergeRequest, error) {
 	return m.MockGetMergeRequest(pid, repositoryName)
 }
 
-func (m *MockLabMergeRequestClient) GetAllProjectMergeRequest(opt *gitlab.ListMergeRequestsOptions) ([]*gitlab.MergeRequest, error) {
-	return m.MockGetAllProjectMergeRequest(opt)
+func (m *MockLabMergeRequestClient) GetMergeRequestChanges(pid int, repositoryName string) (*gitlab.MergeRequest, error) {
+	return m.MockGetMergeRequestChanges(pid, repositoryName)
+}
+
+func (m *MockLabMergeRequestClient) GetAllProjectMergeRequest(opt *gitlab.ListMergeRequestsOptions, limit int, f func([]*gitlab.MergeRequest) error, options ...gitlab.OptionFunc) error {
+	mergeRequests, err := m.MockGetAllProjectMergeRequest(opt)
+	if err != nil {
+		return err
+	}
+	return f(mergeRequests)
 }
 
-func (m *MockLabMergeRequestClient) GetProjectMargeRequest(opt *gitlab.ListProjectMergeRequestsOptions, repositoryName string) ([]*gitlab.MergeRequest, error) {
-	return m.MockGetProjectMargeRequest(opt, repositoryName)
+func (m *MockLabMergeRequestClient) GetProjectMargeRequest(opt *gitlab.ListProjectMergeRequestsOptions, repositoryName string, limit int, f func([]*gitlab.MergeRequest) error, options ...gitlab.OptionFunc) error {
+	mergeRequests, err := m.MockGetProjectMargeRequest(opt, repositoryName)
+	if err != nil {
+		return err
+	}
+	return f(mergeRequests)
 }
 
 func (m *MockLabMergeRequestClient) CreateMergeRequest(opt *gitlab.CreateMergeRequestOptions, repositoryName string) (*gitlab.MergeRequest, error) {
@@ -96,3 +207,23 @@ func (m *MockLabMergeRequestClient) CreateMergeRequest(opt *gitlab.CreateMergeRe
 func (m *MockLabMergeRequestClient) UpdateMergeRequest(opt *gitlab.UpdateMergeRequestOptions, pid int, repositoryName string) (*gitlab.MergeRequest, error) {
 	return m.MockUpdateMergeRequest(opt, pid, repositoryName)
 }
+
+func (m *MockLabMergeRequestClient) AcceptMergeRequest(opt *AcceptMergeRequestOptions, pid int, repositoryName string) (*gitlab.MergeRequest, error) {
+	return m.MockAcceptMergeRequest(opt, pid, repositoryName)
+}
+
+func (m *MockLabMergeRequestClient) GetMergeRequestApprovals(pid int, repositoryName string) (*gitlab.MergeRequestApprovals, error) {
+	return m.MockGetMergeRequestApprovals(pid, repositoryName)
+}
+
+func (m *MockLabMergeRequestClient) ApproveMergeRequest(opt *gitlab.ApproveMergeRequestOptions, pid int, repositoryName string) (*gitlab.MergeRequestApprovals, error) {
+	return m.MockApproveMergeRequest(opt, pid, repositoryName)
+}
+
+func (m *MockLabMergeRequestClient) UnapproveMergeRequest(pid int, repositoryName string) error {
+	return m.MockUnapproveMergeRequest(pid, repositoryName)
+}
+
+func (m *MockLabMergeRequestClient) GetMergeRequestDiffVersions(pid int, repositoryName string) ([]*gitlab.MergeRequestDiffVersion, error) {
+	return m.MockGetMergeRequestDiffVersions(pid, repositoryName)
+}
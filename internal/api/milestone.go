@@ -7,7 +7,7 @@ import (
 )
 
 type Milestone interface {
-	ListMilestones(project string, opt *gitlab.ListMilestonesOptions) ([]*gitlab.Milestone, error)
+	ListMilestones(project string, opt *gitlab.ListMilestonesOptions, limit int, f func([]*gitlab.Milestone) error) error
 }
 
 type MilestoneClient struct {
@@ -18,18 +18,30 @@ func NewMilestoneClient(client *gitlab.Client) *MilestoneClient {
 	return &MilestoneClient{Client: client}
 }
 
-func (c *MilestoneClient) ListMilestones(project string, opt *gitlab.ListMilestonesOptions) ([]*gitlab.Milestone, error) {
-	milestones, _, err := c.Client.Milestones.ListMilestones(project, opt)
-	if err != nil {
-		return nil, fmt.Errorf("Failed list milestone, %s", err.Error())
+// ListMilestones passes the milestones of a project to f page by page until
+// limit milestones are read. Every page is read when limit is zero or less.
+func (c *MilestoneClient) ListMilestones(project string, opt *gitlab.ListMilestonesOptions, limit int, f func([]*gitlab.Milestone) error) error {
+	it := newPageIterator(&opt.ListOptions, limit)
+	for it.Next() {
+		milestones, res, err := c.Client.Milestones.ListMilestones(project, opt)
+		if err != nil {
+			return fmt.Errorf("Failed list milestone, %s", err.Error())
+		}
+		if err := f(milestones[:it.Read(len(milestones), res)]); err != nil {
+			return err
+		}
 	}
-	return milestones, nil
+	return nil
 }
 
 type MockMilestoneClient struct {
 	MockListMilestones func(project string, opt *gitlab.ListMilestonesOptions) ([]*gitlab.Milestone, error)
 }
 
-func (m *MockMilestoneClient) ListMilestones(project string, opt *gitlab.ListMilestonesOptions) ([]*gitlab.Milestone, error) {
-	return m.MockListMilestones(project, opt)
+func (m *MockMilestoneClient) ListMilestones(project string, opt *gitlab.ListMilestonesOptions, limit int, f func([]*gitlab.Milestone) error) error {
+	milestones, err := m.MockListMilestones(project, opt)
+	if err != nil {
+		return err
+	}
+	return f(milestones)
 }
@@ -9,6 +9,14 @@ import (
 type Note interface {
 	GetIssueNotes(repositoryName string, iid int, opt *gitlab.ListIssueNotesOptions) ([]*gitlab.Note, error)
 	GetMergeRequestNotes(repositoryName string, iid int, opt *gitlab.ListMergeRequestNotesOptions) ([]*gitlab.Note, error)
+	GetIssueNote(repositoryName string, iid, noteID int) (*gitlab.Note, error)
+	CreateIssueNote(repositoryName string, iid int, opt *gitlab.CreateIssueNoteOptions) (*gitlab.Note, error)
+	UpdateIssueNote(repositoryName string, iid, noteID int, opt *gitlab.UpdateIssueNoteOptions) (*gitlab.Note, error)
+	DeleteIssueNote(repositoryName string, iid, noteID int) error
+	GetMergeRequestNote(repositoryName string, iid, noteID int) (*gitlab.Note, error)
+	CreateMergeRequestNote(repositoryName string, iid int, opt *gitlab.CreateMergeRequestNoteOptions) (*gitlab.Note, error)
+	UpdateMergeRequestNote(repositoryName string, iid, noteID int, opt *gitlab.UpdateMergeRequestNoteOptions) (*gitlab.Note, error)
+	DeleteMergeRequestNote(repositoryName string, iid, noteID int) error
 }
 
 type NoteClient struct {
@@ -20,26 +28,108 @@ func NewNoteClient(client *gitlab.Client) *NoteClient {
 	return &NoteClient{Client: client}
 }
 
+// GetIssueNotes reads the notes of an issue from every page.
 func (c *NoteClient) GetIssueNotes(repositoryName string, iid int, opt *gitlab.ListIssueNotesOptions) ([]*gitlab.Note, error) {
-	notes, _, err := c.Client.Notes.ListIssueNotes(repositoryName, iid, opt)
-	if err != nil {
-		return nil, fmt.Errorf("Failed get issue notes. %s", err.Error())
+	var notes []*gitlab.Note
+	it := newPageIterator(&opt.ListOptions, 0)
+	for it.Next() {
+		page, res, err := c.Client.Notes.ListIssueNotes(repositoryName, iid, opt)
+		if err != nil {
+			return nil, fmt.Errorf("Failed get issue notes. %s", err.Error())
+		}
+		notes = append(notes, page[:it.Read(len(page), res)]...)
 	}
 	return notes, nil
 }
 
+// GetMergeRequestNotes reads the notes of a merge request from every page.
 func (c *NoteClient) GetMergeRequestNotes(repositoryName string, iid int, opt *gitlab.ListMergeRequestNotesOptions) ([]*gitlab.Note, error) {
-	notes, _, err := c.Client.Notes.ListMergeRequestNotes(repositoryName, iid, opt)
-	if err != nil {
-		return nil, fmt.Errorf("Failed get merge request notes. %s", err.Error())
+	var notes []*gitlab.Note
+	it := newPageIterator((*gitlab.ListOptions)(opt), 0)
+	for it.Next() {
+		page, res, err := c.Client.Notes.ListMergeRequestNotes(repositoryName, iid, opt)
+		if err != nil {
+			return nil, fmt.Errorf("Failed get merge request notes. %s", err.Error())
+		}
+		notes = append(notes, page[:it.Read(len(page), res)]...)
 	}
 	return notes, nil
 }
 
+func (c *NoteClient) GetIssueNote(repositoryName string, iid, noteID int) (*gitlab.Note, error) {
+	note, _, err := c.Client.Notes.GetIssueNote(repositoryName, iid, noteID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed get issue note. %s", err.Error())
+	}
+	return note, nil
+}
+
+func (c *NoteClient) CreateIssueNote(repositoryName string, iid int, opt *gitlab.CreateIssueNoteOptions) (*gitlab.Note, error) {
+	note, _, err := c.Client.Notes.CreateIssueNote(repositoryName, iid, opt)
+	if err != nil {
+		return nil, fmt.Errorf("Failed create issue note. %s", err.Error())
+	}
+	return note, nil
+}
+
+func (c *NoteClient) UpdateIssueNote(repositoryName string, iid, noteID int, opt *gitlab.UpdateIssueNoteOptions) (*gitlab.Note, error) {
+	note, _, err := c.Client.Notes.UpdateIssueNote(repositoryName, iid, noteID, opt)
+	if err != nil {
+		return nil, fmt.Errorf("Failed update issue note. %s", err.Error())
+	}
+	return note, nil
+}
+
+func (c *NoteClient) DeleteIssueNote(repositoryName string, iid, noteID int) error {
+	if _, err := c.Client.Notes.DeleteIssueNote(repositoryName, iid, noteID); err != nil {
+		return fmt.Errorf("Failed delete issue note. %s", err.Error())
+	}
+	return nil
+}
+
+func (c *NoteClient) GetMergeRequestNote(repositoryName string, iid, noteID int) (*gitlab.Note, error) {
+	note, _, err := c.Client.Notes.GetMergeRequestNote(repositoryName, iid, noteID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed get merge request note. %s", err.Error())
+	}
+	return note, nil
+}
+
+func (c *NoteClient) CreateMergeRequestNote(repositoryName string, iid int, opt *gitlab.CreateMergeRequestNoteOptions) (*gitlab.Note, error) {
+	note, _, err := c.Client.Notes.CreateMergeRequestNote(repositoryName, iid, opt)
+	if err != nil {
+		return nil, fmt.Errorf("Failed create merge request note. %s", err.Error())
+	}
+	return note, nil
+}
+
+func (c *NoteClient) UpdateMergeRequestNote(repositoryName string, iid, noteID int, opt *gitlab.UpdateMergeRequestNoteOptions) (*gitlab.Note, error) {
+	note, _, err := c.Client.Notes.UpdateMergeRequestNote(repositoryName, iid, noteID, opt)
+	if err != nil {
+		return nil, fmt.Errorf("Failed update merge request note. %s", err.Error())
+	}
+	return note, nil
+}
+
+func (c *NoteClient) DeleteMergeRequestNote(repositoryName string, iid, noteID int) error {
+	if _, err := c.Client.Notes.DeleteMergeRequestNote(repositoryName, iid, noteID); err != nil {
+		return fmt.Errorf("Failed delete merge request note. %s", err.Error())
+	}
+	return nil
+}
+
 type MockNoteClient struct {
 	Note
-	MockGetIssueNotes        func(repositoryName string, iid int, opt *gitlab.ListIssueNotesOptions) ([]*gitlab.Note, error)
-	MockGetMergeRequestNotes func(repositoryName string, iid int, opt *gitlab.ListMergeRequestNotesOptions) ([]*gitlab.Note, error)
+	MockGetIssueNotes          func(repositoryName string, iid int, opt *gitlab.ListIssueNotesOptions) ([]*gitlab.Note, error)
+	MockGetMergeRequestNotes   func(repositoryName string, iid int, opt *gitlab.ListMergeRequestNotesOptions) ([]*gitlab.Note, error)
+	MockGetIssueNote           func(repositoryName string, iid, noteID int) (*gitlab.Note, error)
+	MockCreateIssueNote        func(repositoryName string, iid int, opt *gitlab.CreateIssueNoteOptions) (*gitlab.Note, error)
+	MockUpdateIssueNote        func(repositoryName string, iid, noteID int, opt *gitlab.UpdateIssueNoteOptions) (*gitlab.Note, error)
+	MockDeleteIssueNote        func(repositoryName string, iid, noteID int) error
+	MockGetMergeRequestNote    func(repositoryName string, iid, noteID int) (*gitlab.Note, error)
+	MockCreateMergeRequestNote func(repositoryName string, iid int, opt *gitlab.CreateMergeRequestNoteOptions) (*gitlab.Note, error)
+	MockUpdateMergeRequestNote func(repositoryName string, iid, noteID int, opt *gitlab.UpdateMergeRequestNoteOptions) (*gitlab.Note, error)
+	MockDeleteMergeRequestNote func(repositoryName string, iid, noteID int) error
 }
 
 func (m *MockNoteClient) GetIssueNotes(repositoryName string, iid int, opt *gitlab.ListIssueNotesOptions) ([]*gitlab.Note, error) {
@@ -49,3 +139,35 @@ func (m *MockNoteClient) GetIssueNotes(repositoryName string, iid int, opt *gitl
 func (m *MockNoteClient) GetMergeRequestNotes(repositoryName string, iid int, opt *gitlab.ListMergeRequestNotesOptions) ([]*gitlab.Note, error) {
 	return m.MockGetMergeRequestNotes(repositoryName, iid, opt)
 }
+
+func (m *MockNoteClient) GetIssueNote(repositoryName string, iid, noteID int) (*gitlab.Note, error) {
+	return m.MockGetIssueNote(repositoryName, iid, noteID)
+}
+
+func (m *MockNoteClient) CreateIssueNote(repositoryName string, iid int, opt *gitlab.CreateIssueNoteOptions) (*gitlab.Note, error) {
+	return m.MockCreateIssueNote(repositoryName, iid, opt)
+}
+
+func (m *MockNoteClient) UpdateIssueNote(repositoryName string, iid, noteID int, opt *gitlab.UpdateIssueNoteOptions) (*gitlab.Note, error) {
+	return m.MockUpdateIssueNote(repositoryName, iid, noteID, opt)
+}
+
+func (m *MockNoteClient) DeleteIssueNote(repositoryName string, iid, noteID int) error {
+	return m.MockDeleteIssueNote(repositoryName, iid, noteID)
+}
+
+func (m *MockNoteClient) GetMergeRequestNote(repositoryName string, iid, noteID int) (*gitlab.Note, error) {
+	return m.MockGetMergeRequestNote(repositoryName, iid, noteID)
+}
+
+func (m *MockNoteClient) CreateMergeRequestNote(repositoryName string, iid int, opt *gitlab.CreateMergeRequestNoteOptions) (*gitlab.Note, error) {
+	return m.MockCreateMergeRequestNote(repositoryName, iid, opt)
+}
+
+func (m *MockNoteClient) UpdateMergeRequestNote(repositoryName string, iid, noteID int, opt *gitlab.UpdateMergeRequestNoteOptions) (*gitlab.Note, error) {
+	return m.MockUpdateMergeRequestNote(repositoryName, iid, noteID, opt)
+}
+
+func (m *MockNoteClient) DeleteMergeRequestNote(repositoryName string, iid, noteID int) error {
+	return m.MockDeleteMergeRequestNote(repositoryName, iid, noteID)
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// MaxPerPage is the largest page size that GitLab accepts.
+const MaxPerPage = 100
+
+// pageIterator follows the pages of a list request. The next page is taken
+// from the X-Next-Page header, or from the Link header when GitLab omits it.
+//
+//	it := newPageIterator(&opt.ListOptions, limit)
+//	for it.Next() {
+//		items, res, err := list(opt)
+//		...
+//		items = items[:it.Read(len(items), res)]
+//	}
+type pageIterator struct {
+	opt   *gitlab.ListOptions
+	limit int
+	read  int
+	more  bool
+}
+
+// newPageIterator returns an iterator reading limit items starting from the
+// page set in opt. Every page is read when limit is zero or less.
+func newPageIterator(opt *gitlab.ListOptions, limit int) *pageIterator {
+	if opt.Page < 1 {
+		opt.Page = 1
+	}
+	opt.PerPage = MaxPerPage
+	if limit > 0 && limit < MaxPerPage {
+		opt.PerPage = limit
+	}
+	return &pageIterator{opt: opt, limit: limit, more: true}
+}
+
+// Next reports whether another page has to be requested.
+func (it *pageIterator) Next() bool {
+	return it.more
+}
+
+// Read records a page of n items and moves the options to the next page.
+// It returns the number of items of the page to keep within the limit.
+func (it *pageIterator) Read(n int, res *gitlab.Response) int {
+	if it.limit > 0 && it.read+n > it.limit {
+		n = it.limit - it.read
+	}
+	it.read += n
+
+	next := nextPage(res)
+	it.more = n > 0 && next > it.opt.Page && (it.limit <= 0 || it.read < it.limit)
+	it.opt.Page = next
+	return n
+}
+
+func nextPage(res *gitlab.Response) int {
+	if res == nil {
+		return 0
+	}
+	if res.NextPage > 0 {
+		return res.NextPage
+	}
+	if res.Response == nil {
+		return 0
+	}
+	return linkNextPage(res.Header.Get("Link"))
+}
+
+// linkNextPage returns the page number of the "next" relation of a Link
+// header, or 0 when there is none.
+func linkNextPage(link string) int {
+	for _, part := range strings.Split(link, ",") {
+		segments := strings.Split(part, ";")
+		if len(segments) < 2 {
+			continue
+		}
+		isNext := false
+		for _, param := range segments[1:] {
+			if strings.TrimSpace(param) == `rel="next"` {
+				isNext = true
+			}
+		}
+		if !isNext {
+			continue
+		}
+
+		rawurl := strings.Trim(strings.TrimSpace(segments[0]), "<>")
+		u, err := url.Parse(rawurl)
+		if err != nil {
+			return 0
+		}
+		page, err := strconv.Atoi(u.Query().Get("page"))
+		if err != nil {
+			return 0
+		}
+		return page
+	}
+	return 0
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func newResponse(nextPage int, link string) *gitlab.Response {
+	header := http.Header{}
+	if link != "" {
+		header.Set("Link", link)
+	}
+	return &gitlab.Response{
+		Response: &http.Response{Header: header},
+		NextPage: nextPage,
+	}
+}
+
+func Test_pageIterator(t *testing.T) {
+	type page struct {
+		n   int
+		res *gitlab.Response
+	}
+	tests := []struct {
+		name        string
+		limit       int
+		pages       []page
+		wantPerPage int
+		wantRead    []int
+	}{
+		{
+			name:        "limit within a page",
+			limit:       20,
+			pages:       []page{{20, newResponse(2, "")}},
+			wantPerPage: 20,
+			wantRead:    []int{20},
+		},
+		{
+			name:  "limit over the page size",
+			limit: 250,
+			pages: []page{
+				{100, newResponse(2, "")},
+				{100, newResponse(3, "")},
+				{100, newResponse(4, "")},
+			},
+			wantPerPage: MaxPerPage,
+			wantRead:    []int{100, 100, 50},
+		},
+		{
+			name:  "all pages",
+			limit: 0,
+			pages: []page{
+				{100, newResponse(2, "")},
+				{30, newResponse(0, "")},
+			},
+			wantPerPage: MaxPerPage,
+			wantRead:    []int{100, 30},
+		},
+		{
+			name:  "link header",
+			limit: 0,
+			pages: []page{
+				{100, newResponse(0, `<https://gitlab.com/api/v4/projects?page=1&per_page=100>; rel="first", <https://gitlab.com/api/v4/projects?page=2&per_page=100>; rel="next"`)},
+				{10, newResponse(0, `<https://gitlab.com/api/v4/projects?page=1&per_page=100>; rel="first"`)},
+			},
+			wantPerPage: MaxPerPage,
+			wantRead:    []int{100, 10},
+		},
+		{
+			name:        "empty page",
+			limit:       0,
+			pages:       []page{{0, newResponse(2, "")}},
+			wantPerPage: MaxPerPage,
+			wantRead:    []int{0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt := &gitlab.ListOptions{}
+			it := newPageIterator(opt, tt.limit)
+			if opt.PerPage != tt.wantPerPage {
+				t.Errorf("PerPage = %d, want %d", opt.PerPage, tt.wantPerPage)
+			}
+
+			var got []int
+			for i := 0; it.Next(); i++ {
+				if i >= len(tt.pages) {
+					t.Fatalf("requested page %d, want %d pages", opt.Page, len(tt.pages))
+				}
+				if opt.Page != i+1 {
+					t.Errorf("Page = %d, want %d", opt.Page, i+1)
+				}
+				got = append(got, it.Read(tt.pages[i].n, tt.pages[i].res))
+			}
+			if len(got) != len(tt.wantRead) {
+				t.Fatalf("read %v, want %v", got, tt.wantRead)
+			}
+			for i := range got {
+				if got[i] != tt.wantRead[i] {
+					t.Errorf("read %v, want %v", got, tt.wantRead)
+				}
+			}
+		})
+	}
+}
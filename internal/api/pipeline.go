@@ -2,13 +2,20 @@ package api
 
 import (
 	"fmt"
+	"net/http"
+	"net/url"
+	"time"
 
 	gitlab "github.com/xanzy/go-gitlab"
 )
 
 type Pipeline interface {
-	ProjectPipelines(repositoryName string, opt *gitlab.ListProjectPipelinesOptions) (gitlab.PipelineList, error)
-	ProjectPipelineJobs(repositoryName string, opt *gitlab.ListJobsOptions, pid int) ([]*gitlab.Job, error)
+	ProjectPipelines(repositoryName string, opt *gitlab.ListProjectPipelinesOptions, limit int, f func(gitlab.PipelineList) error, options ...gitlab.OptionFunc) error
+	ProjectPipelineJobs(repositoryName string, opt *gitlab.ListJobsOptions, pid int, limit int, f func([]*gitlab.Job) error, options ...gitlab.OptionFunc) error
+	GetPipeline(repositoryName string, pid int) (*gitlab.Pipeline, error)
+	CreatePipeline(repositoryName string, opt *gitlab.CreatePipelineOptions) (*gitlab.Pipeline, error)
+	RetryPipeline(repositoryName string, pid int) (*gitlab.Pipeline, error)
+	CancelPipeline(repositoryName string, pid int) (*gitlab.Pipeline, error)
 }
 
 type PipelineClient struct {
@@ -19,31 +26,146 @@ func NewPipelineClient(client *gitlab.Client) Pipeline {
 	return &PipelineClient{Client: client}
 }
 
-func (c *PipelineClient) ProjectPipelines(repositoryName string, opt *gitlab.ListProjectPipelinesOptions) (gitlab.PipelineList, error) {
-	pipelines, _, err := c.Client.Pipelines.ListProjectPipelines(repositoryName, opt)
+// ProjectPipelines passes the pipelines of a project to f page by page until
+// limit pipelines are read. Every page is read when limit is zero or less.
+func (c *PipelineClient) ProjectPipelines(repositoryName string, opt *gitlab.ListProjectPipelinesOptions, limit int, f func(gitlab.PipelineList) error, options ...gitlab.OptionFunc) error {
+	it := newPageIterator(&opt.ListOptions, limit)
+	for it.Next() {
+		pipelines, res, err := c.Client.Pipelines.ListProjectPipelines(repositoryName, opt, options...)
+		if err != nil {
+			return fmt.Errorf("Failed list pipelines. Error: %s", err.Error())
+		}
+		if err := f(pipelines[:it.Read(len(pipelines), res)]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ProjectPipelineJobs passes the jobs of a pipeline to f page by page until
+// limit jobs are read. Every page is read when limit is zero or less.
+func (c *PipelineClient) ProjectPipelineJobs(repositoryName string, opt *gitlab.ListJobsOptions, pid int, limit int, f func([]*gitlab.Job) error, options ...gitlab.OptionFunc) error {
+	it := newPageIterator(&opt.ListOptions, limit)
+	for it.Next() {
+		jobs, res, err := c.Client.Jobs.ListPipelineJobs(repositoryName, pid, opt, options...)
+		if err != nil {
+			return fmt.Errorf("Failed list pipeline jobs. Error: %s", err.Error())
+		}
+		if err := f(jobs[:it.Read(len(jobs), res)]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WithIncludeRetried lists the retried jobs of a pipeline too, which the
+// list options of go-gitlab can not do yet.
+func WithIncludeRetried() gitlab.OptionFunc {
+	return func(req *http.Request) error {
+		q := req.URL.Query()
+		q.Set("include_retried", "true")
+		req.URL.RawQuery = q.Encode()
+		return nil
+	}
+}
+
+// WithUpdatedAfter lists the pipelines updated after t, which the list
+// options of go-gitlab can not do yet.
+func WithUpdatedAfter(t time.Time) gitlab.OptionFunc {
+	return func(req *http.Request) error {
+		q := req.URL.Query()
+		q.Set("updated_after", t.Format(time.RFC3339))
+		req.URL.RawQuery = q.Encode()
+		return nil
+	}
+}
+
+func (c *PipelineClient) GetPipeline(repositoryName string, pid int) (*gitlab.Pipeline, error) {
+	pipeline, _, err := c.Client.Pipelines.GetPipeline(repositoryName, pid)
+	if err != nil {
+		return nil, fmt.Errorf("Failed get pipeline. Error: %s", err.Error())
+	}
+	return pipeline, nil
+}
+
+func (c *PipelineClient) CreatePipeline(repositoryName string, opt *gitlab.CreatePipelineOptions) (*gitlab.Pipeline, error) {
+	pipeline, _, err := c.Client.Pipelines.CreatePipeline(repositoryName, opt)
+	if err != nil {
+		return nil, fmt.Errorf("Failed create pipeline. Error: %s", err.Error())
+	}
+	return pipeline, nil
+}
+
+// RetryPipeline retries the failed and canceled jobs of a pipeline. The
+// request is made here, go-gitlab does not escape the project name of it.
+func (c *PipelineClient) RetryPipeline(repositoryName string, pid int) (*gitlab.Pipeline, error) {
+	pipeline, err := c.postPipeline(repositoryName, pid, "retry")
+	if err != nil {
+		return nil, fmt.Errorf("Failed retry pipeline. Error: %s", err.Error())
+	}
+	return pipeline, nil
+}
+
+// CancelPipeline cancels the running jobs of a pipeline like RetryPipeline.
+func (c *PipelineClient) CancelPipeline(repositoryName string, pid int) (*gitlab.Pipeline, error) {
+	pipeline, err := c.postPipeline(repositoryName, pid, "cancel")
 	if err != nil {
-		return nil, fmt.Errorf("Failed list pipelines. Error: %s", err.Error())
+		return nil, fmt.Errorf("Failed cancel pipeline. Error: %s", err.Error())
 	}
-	return pipelines, nil
+	return pipeline, nil
 }
 
-func (c *PipelineClient) ProjectPipelineJobs(repositoryName string, opt *gitlab.ListJobsOptions, pid int) ([]*gitlab.Job, error) {
-	jobs, _, err := c.Client.Jobs.ListPipelineJobs(repositoryName, pid, opt)
+func (c *PipelineClient) postPipeline(repositoryName string, pid int, action string) (*gitlab.Pipeline, error) {
+	u := fmt.Sprintf("projects/%s/pipelines/%d/%s", url.QueryEscape(repositoryName), pid, action)
+	req, err := c.Client.NewRequest("POST", u, nil, nil)
 	if err != nil {
-		return nil, fmt.Errorf("Failed list pipeline jobs. Error: %s", err.Error())
+		return nil, err
 	}
-	return jobs, nil
+
+	pipeline := new(gitlab.Pipeline)
+	if _, err := c.Client.Do(req, pipeline); err != nil {
+		return nil, err
+	}
+	return pipeline, nil
 }
 
 type MockPipelineClient struct {
 	MockProjectPipelines    func(repositoryName string, opt *gitlab.ListProjectPipelinesOptions) (gitlab.PipelineList, error)
 	MockProjectPipelineJobs func(repositoryName string, opt *gitlab.ListJobsOptions, pid int) ([]*gitlab.Job, error)
+	MockGetPipeline         func(repositoryName string, pid int) (*gitlab.Pipeline, error)
+	MockCreatePipeline      func(repositoryName string, opt *gitlab.CreatePipelineOptions) (*gitlab.Pipeline, error)
+	MockRetryPipeline       func(repositoryName string, pid int) (*gitlab.Pipeline, error)
+	MockCancelPipeline      func(repositoryName string, pid int) (*gitlab.Pipeline, error)
+}
+
+func (m *MockPipelineClient) ProjectPipelines(repositoryName string, opt *gitlab.ListProjectPipelinesOptions, limit int, f func(gitlab.PipelineList) error, options ...gitlab.OptionFunc) error {
+	pipelines, err := m.MockProjectPipelines(repositoryName, opt)
+	if err != nil {
+		return err
+	}
+	return f(pipelines)
+}
+
+func (m *MockPipelineClient) ProjectPipelineJobs(repositoryName string, opt *gitlab.ListJobsOptions, pid int, limit int, f func([]*gitlab.Job) error, options ...gitlab.OptionFunc) error {
+	jobs, err := m.MockProjectPipelineJobs(repositoryName, opt, pid)
+	if err != nil {
+		return err
+	}
+	return f(jobs)
+}
+
+func (m *MockPipelineClient) GetPipeline(repositoryName string, pid int) (*gitlab.Pipeline, error) {
+	return m.MockGetPipeline(repositoryName, pid)
+}
+
+func (m *MockPipelineClient) CreatePipeline(repositoryName string, opt *gitlab.CreatePipelineOptions) (*gitlab.Pipeline, error) {
+	return m.MockCreatePipeline(repositoryName, opt)
 }
 
-func (m *MockPipelineClient) ProjectPipelines(repositoryName string, opt *gitlab.ListProjectPipelinesOptions) (gitlab.PipelineList, error) {
-	return m.MockProjectPipelines(repositoryName, opt)
+func (m *MockPipelineClient) RetryPipeline(repositoryName string, pid int) (*gitlab.Pipeline, error) {
+	return m.MockRetryPipeline(repositoryName, pid)
 }
 
-func (m *MockPipelineClient) ProjectPipelineJobs(repositoryName string, opt *gitlab.ListJobsOptions, pid int) ([]*gitlab.Job, error) {
-	return m.MockProjectPipelineJobs(repositoryName, opt, pid)
+func (m *MockPipelineClient) CancelPipeline(repositoryName string, pid int) (*gitlab.Pipeline, error) {
+	return m.MockCancelPipeline(repositoryName, pid)
 }
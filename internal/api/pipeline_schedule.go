@@ -0,0 +1,182 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+type PipelineSchedule interface {
+	ListSchedules(project string, opt *gitlab.ListPipelineSchedulesOptions, limit int, f func([]*gitlab.PipelineSchedule) error) error
+	GetSchedule(project string, id int) (*gitlab.PipelineSchedule, error)
+	CreateSchedule(project string, opt *gitlab.CreatePipelineScheduleOptions) (*gitlab.PipelineSchedule, error)
+	EditSchedule(project string, id int, opt *gitlab.EditPipelineScheduleOptions) (*gitlab.PipelineSchedule, error)
+	TakeOwnership(project string, id int) (*gitlab.PipelineSchedule, error)
+	RunSchedule(project string, id int) error
+	DeleteSchedule(project string, id int) error
+	CreateScheduleVariable(project string, id int, opt *gitlab.CreatePipelineScheduleVariableOptions) (*gitlab.PipelineVariable, error)
+	EditScheduleVariable(project string, id int, key string, opt *gitlab.EditPipelineScheduleVariableOptions) (*gitlab.PipelineVariable, error)
+	DeleteScheduleVariable(project string, id int, key string) error
+}
+
+type PipelineScheduleClient struct {
+	Client *gitlab.Client
+}
+
+func NewPipelineScheduleClient(client *gitlab.Client) *PipelineScheduleClient {
+	return &PipelineScheduleClient{Client: client}
+}
+
+// ListSchedules passes the pipeline schedules of a project to f page by page
+// until limit schedules are read. Every page is read when limit is zero or
+// less.
+func (c *PipelineScheduleClient) ListSchedules(project string, opt *gitlab.ListPipelineSchedulesOptions, limit int, f func([]*gitlab.PipelineSchedule) error) error {
+	it := newPageIterator((*gitlab.ListOptions)(opt), limit)
+	for it.Next() {
+		schedules, res, err := c.Client.PipelineSchedules.ListPipelineSchedules(project, opt)
+		if err != nil {
+			return fmt.Errorf("Failed list pipeline schedule. %s", err.Error())
+		}
+		if err := f(schedules[:it.Read(len(schedules), res)]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *PipelineScheduleClient) GetSchedule(project string, id int) (*gitlab.PipelineSchedule, error) {
+	schedule, _, err := c.Client.PipelineSchedules.GetPipelineSchedule(project, id)
+	if err != nil {
+		return nil, fmt.Errorf("Failed get pipeline schedule. %s", err.Error())
+	}
+	return schedule, nil
+}
+
+func (c *PipelineScheduleClient) CreateSchedule(project string, opt *gitlab.CreatePipelineScheduleOptions) (*gitlab.PipelineSchedule, error) {
+	schedule, _, err := c.Client.PipelineSchedules.CreatePipelineSchedule(project, opt)
+	if err != nil {
+		return nil, fmt.Errorf("Failed create pipeline schedule. %s", err.Error())
+	}
+	return schedule, nil
+}
+
+func (c *PipelineScheduleClient) EditSchedule(project string, id int, opt *gitlab.EditPipelineScheduleOptions) (*gitlab.PipelineSchedule, error) {
+	schedule, _, err := c.Client.PipelineSchedules.EditPipelineSchedule(project, id, opt)
+	if err != nil {
+		return nil, fmt.Errorf("Failed edit pipeline schedule. %s", err.Error())
+	}
+	return schedule, nil
+}
+
+func (c *PipelineScheduleClient) TakeOwnership(project string, id int) (*gitlab.PipelineSchedule, error) {
+	schedule, _, err := c.Client.PipelineSchedules.TakeOwnershipOfPipelineSchedule(project, id)
+	if err != nil {
+		return nil, fmt.Errorf("Failed take ownership of pipeline schedule. %s", err.Error())
+	}
+	return schedule, nil
+}
+
+// RunSchedule runs the pipeline of a schedule now. go-gitlab has no play
+// of the schedules yet, so the request is made here.
+func (c *PipelineScheduleClient) RunSchedule(project string, id int) error {
+	u := fmt.Sprintf("projects/%s/pipeline_schedules/%d/play", url.QueryEscape(project), id)
+	req, err := c.Client.NewRequest("POST", u, nil, nil)
+	if err != nil {
+		return fmt.Errorf("Failed run pipeline schedule. %s", err.Error())
+	}
+	if _, err := c.Client.Do(req, nil); err != nil {
+		return fmt.Errorf("Failed run pipeline schedule. %s", err.Error())
+	}
+	return nil
+}
+
+func (c *PipelineScheduleClient) DeleteSchedule(project string, id int) error {
+	if _, _, err := c.Client.PipelineSchedules.DeletePipelineSchedule(project, id); err != nil {
+		return fmt.Errorf("Failed delete pipeline schedule. %s", err.Error())
+	}
+	return nil
+}
+
+func (c *PipelineScheduleClient) CreateScheduleVariable(project string, id int, opt *gitlab.CreatePipelineScheduleVariableOptions) (*gitlab.PipelineVariable, error) {
+	variable, _, err := c.Client.PipelineSchedules.CreatePipelineScheduleVariable(project, id, opt)
+	if err != nil {
+		return nil, fmt.Errorf("Failed create pipeline schedule variable. %s", err.Error())
+	}
+	return variable, nil
+}
+
+// EditScheduleVariable updates the value of a variable. The key is escaped
+// here, which go-gitlab does not do.
+func (c *PipelineScheduleClient) EditScheduleVariable(project string, id int, key string, opt *gitlab.EditPipelineScheduleVariableOptions) (*gitlab.PipelineVariable, error) {
+	variable, _, err := c.Client.PipelineSchedules.EditPipelineScheduleVariable(project, id, url.PathEscape(key), opt)
+	if err != nil {
+		return nil, fmt.Errorf("Failed edit pipeline schedule variable. %s", err.Error())
+	}
+	return variable, nil
+}
+
+func (c *PipelineScheduleClient) DeleteScheduleVariable(project string, id int, key string) error {
+	if _, _, err := c.Client.PipelineSchedules.DeletePipelineScheduleVariable(project, id, url.PathEscape(key)); err != nil {
+		return fmt.Errorf("Failed delete pipeline schedule variable. %s", err.Error())
+	}
+	return nil
+}
+
+type MockPipelineScheduleClient struct {
+	PipelineSchedule
+	MockListSchedules          func(project string, opt *gitlab.ListPipelineSchedulesOptions) ([]*gitlab.PipelineSchedule, error)
+	MockGetSchedule            func(project string, id int) (*gitlab.PipelineSchedule, error)
+	MockCreateSchedule         func(project string, opt *gitlab.CreatePipelineScheduleOptions) (*gitlab.PipelineSchedule, error)
+	MockEditSchedule           func(project string, id int, opt *gitlab.EditPipelineScheduleOptions) (*gitlab.PipelineSchedule, error)
+	MockTakeOwnership          func(project string, id int) (*gitlab.PipelineSchedule, error)
+	MockRunSchedule            func(project string, id int) error
+	MockDeleteSchedule         func(project string, id int) error
+	MockCreateScheduleVariable func(project string, id int, opt *gitlab.CreatePipelineScheduleVariableOptions) (*gitlab.PipelineVariable, error)
+	MockEditScheduleVariable   func(project string, id int, key string, opt *gitlab.EditPipelineScheduleVariableOptions) (*gitlab.PipelineVariable, error)
+	MockDeleteScheduleVariable func(project string, id int, key string) error
+}
+
+func (m *MockPipelineScheduleClient) ListSchedules(project string, opt *gitlab.ListPipelineSchedulesOptions, limit int, f func([]*gitlab.PipelineSchedule) error) error {
+	schedules, err := m.MockListSchedules(project, opt)
+	if err != nil {
+		return err
+	}
+	return f(schedules)
+}
+
+func (m *MockPipelineScheduleClient) GetSchedule(project string, id int) (*gitlab.PipelineSchedule, error) {
+	return m.MockGetSchedule(project, id)
+}
+
+func (m *MockPipelineScheduleClient) CreateSchedule(project string, opt *gitlab.CreatePipelineScheduleOptions) (*gitlab.PipelineSchedule, error) {
+	return m.MockCreateSchedule(project, opt)
+}
+
+func (m *MockPipelineScheduleClient) EditSchedule(project string, id int, opt *gitlab.EditPipelineScheduleOptions) (*gitlab.PipelineSchedule, error) {
+	return m.MockEditSchedule(project, id, opt)
+}
+
+func (m *MockPipelineScheduleClient) TakeOwnership(project string, id int) (*gitlab.PipelineSchedule, error) {
+	return m.MockTakeOwnership(project, id)
+}
+
+func (m *MockPipelineScheduleClient) RunSchedule(project string, id int) error {
+	return m.MockRunSchedule(project, id)
+}
+
+func (m *MockPipelineScheduleClient) DeleteSchedule(project string, id int) error {
+	return m.MockDeleteSchedule(project, id)
+}
+
+func (m *MockPipelineScheduleClient) CreateScheduleVariable(project string, id int, opt *gitlab.CreatePipelineScheduleVariableOptions) (*gitlab.PipelineVariable, error) {
+	return m.MockCreateScheduleVariable(project, id, opt)
+}
+
+func (m *MockPipelineScheduleClient) EditScheduleVariable(project string, id int, key string, opt *gitlab.EditPipelineScheduleVariableOptions) (*gitlab.PipelineVariable, error) {
+	return m.MockEditScheduleVariable(project, id, key, opt)
+}
+
+func (m *MockPipelineScheduleClient) DeleteScheduleVariable(project string, id int, key string) error {
+	return m.MockDeleteScheduleVariable(project, id, key)
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"fmt"
+
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+type PipelineTrigger interface {
+	ListTriggers(project string, opt *gitlab.ListPipelineTriggersOptions, limit int, f func([]*gitlab.PipelineTrigger) error) error
+	CreateTrigger(project string, opt *gitlab.AddPipelineTriggerOptions) (*gitlab.PipelineTrigger, error)
+	DeleteTrigger(project string, id int) error
+	RunTrigger(project string, opt *gitlab.RunPipelineTriggerOptions) (*gitlab.Pipeline, error)
+}
+
+type PipelineTriggerClient struct {
+	Client *gitlab.Client
+}
+
+func NewPipelineTriggerClient(client *gitlab.Client) *PipelineTriggerClient {
+	return &PipelineTriggerClient{Client: client}
+}
+
+// ListTriggers passes the trigger tokens of a project to f page by page until
+// limit tokens are read. Every page is read when limit is zero or less.
+func (c *PipelineTriggerClient) ListTriggers(project string, opt *gitlab.ListPipelineTriggersOptions, limit int, f func([]*gitlab.PipelineTrigger) error) error {
+	it := newPageIterator((*gitlab.ListOptions)(opt), limit)
+	for it.Next() {
+		triggers, res, err := c.Client.PipelineTriggers.ListPipelineTriggers(project, opt)
+		if err != nil {
+			return fmt.Errorf("Failed list pipeline trigger. %s", err.Error())
+		}
+		if err := f(triggers[:it.Read(len(triggers), res)]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *PipelineTriggerClient) CreateTrigger(project string, opt *gitlab.AddPipelineTriggerOptions) (*gitlab.PipelineTrigger, error) {
+	trigger, _, err := c.Client.PipelineTriggers.AddPipelineTrigger(project, opt)
+	if err != nil {
+		return nil, fmt.Errorf("Failed create pipeline trigger. %s", err.Error())
+	}
+	return trigger, nil
+}
+
+func (c *PipelineTriggerClient) DeleteTrigger(project string, id int) error {
+	if _, err := c.Client.PipelineTriggers.DeletePipelineTrigger(project, id); err != nil {
+		return fmt.Errorf("Failed delete pipeline trigger. %s", err.Error())
+	}
+	return nil
+}
+
+// RunTrigger runs a pipeline with a trigger token, as the owner of the token.
+func (c *PipelineTriggerClient) RunTrigger(project string, opt *gitlab.RunPipelineTriggerOptions) (*gitlab.Pipeline, error) {
+	pipeline, _, err := c.Client.PipelineTriggers.RunPipelineTrigger(project, opt)
+	if err != nil {
+		return nil, fmt.Errorf("Failed run pipeline trigger. %s", err.Error())
+	}
+	return pipeline, nil
+}
+
+type MockPipelineTriggerClient struct {
+	PipelineTrigger
+	MockListTriggers  func(project string, opt *gitlab.ListPipelineTriggersOptions) ([]*gitlab.PipelineTrigger, error)
+	MockCreateTrigger func(project string, opt *gitlab.AddPipelineTriggerOptions) (*gitlab.PipelineTrigger, error)
+	MockDeleteTrigger func(project string, id int) error
+	MockRunTrigger    func(project string, opt *gitlab.RunPipelineTriggerOptions) (*gitlab.Pipeline, error)
+}
+
+func (m *MockPipelineTriggerClient) ListTriggers(project string, opt *gitlab.ListPipelineTriggersOptions, limit int, f func([]*gitlab.PipelineTrigger) error) error {
+	triggers, err := m.MockListTriggers(project, opt)
+	if err != nil {
+		return err
+	}
+	return f(triggers)
+}
+
+func (m *MockPipelineTriggerClient) CreateTrigger(project string, opt *gitlab.AddPipelineTriggerOptions) (*gitlab.PipelineTrigger, error) {
+	return m.MockCreateTrigger(project, opt)
+}
+
+func (m *MockPipelineTriggerClient) DeleteTrigger(project string, id int) error {
+	return m.MockDeleteTrigger(project, id)
+}
+
+func (m *MockPipelineTriggerClient) RunTrigger(project string, opt *gitlab.RunPipelineTriggerOptions) (*gitlab.Pipeline, error) {
+	return m.MockRunTrigger(project, opt)
+}
@@ -7,7 +7,8 @@ import (
 )
 
 type Project interface {
-	Projects(opt *gitlab.ListProjectsOptions) ([]*gitlab.Project, error)
+	GetProject(pid int) (*gitlab.Project, error)
+	Projects(opt *gitlab.ListProjectsOptions, limit int, f func([]*gitlab.Project) error) error
 }
 
 type ProjectClient struct {
@@ -18,18 +19,43 @@ func NewProjectClient(client *gitlab.Client) *ProjectClient {
 	return &ProjectClient{Client: client}
 }
 
-func (c *ProjectClient) Projects(opt *gitlab.ListProjectsOptions) ([]*gitlab.Project, error) {
-	projects, _, err := c.Client.Projects.ListProjects(opt)
+func (c *ProjectClient) GetProject(pid int) (*gitlab.Project, error) {
+	project, _, err := c.Client.Projects.GetProject(pid)
 	if err != nil {
-		return nil, fmt.Errorf("Failed list projects. Error: %s", err.Error())
+		return nil, fmt.Errorf("Failed get project. Error: %s", err.Error())
 	}
-	return projects, nil
+	return project, nil
+}
+
+// Projects passes the projects to f page by page until limit projects are
+// read. Every page is read when limit is zero or less.
+func (c *ProjectClient) Projects(opt *gitlab.ListProjectsOptions, limit int, f func([]*gitlab.Project) error) error {
+	it := newPageIterator(&opt.ListOptions, limit)
+	for it.Next() {
+		projects, res, err := c.Client.Projects.ListProjects(opt)
+		if err != nil {
+			return fmt.Errorf("Failed list projects. Error: %s", err.Error())
+		}
+		if err := f(projects[:it.Read(len(projects), res)]); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 type MockProjectClient struct {
-	MockProjects func(opt *gitlab.ListProjectsOptions) ([]*gitlab.Project, error)
+	MockGetProject func(pid int) (*gitlab.Project, error)
+	MockProjects   func(opt *gitlab.ListProjectsOptions) ([]*gitlab.Project, error)
+}
+
+func (m *MockProjectClient) Projects(opt *gitlab.ListProjectsOptions, limit int, f func([]*gitlab.Project) error) error {
+	projects, err := m.MockProjects(opt)
+	if err != nil {
+		return err
+	}
+	return f(projects)
 }
 
-func (m *MockProjectClient) Projects(opt *gitlab.ListProjectsOptions) ([]*gitlab.Project, error) {
-	return m.MockProjects(opt)
+func (m *MockProjectClient) GetProject(pid int) (*gitlab.Project, error) {
+	return m.MockGetProject(pid)
 }
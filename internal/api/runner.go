@@ -7,10 +7,15 @@ import (
 )
 
 type Runner interface {
-	ListAllRunners(opt *gitlab.ListRunnersOptions) ([]*gitlab.Runner, error)
-	ListProjectRunners(pid string, opt *gitlab.ListProjectRunnersOptions) ([]*gitlab.Runner, error)
+	ListAllRunners(opt *gitlab.ListRunnersOptions, limit int, f func([]*gitlab.Runner) error) error
+	ListProjectRunners(pid string, opt *gitlab.ListProjectRunnersOptions, limit int, f func([]*gitlab.Runner) error) error
 	GetRunnerDetails(id int) (*gitlab.RunnerDetails, error)
 	RemoveRunner(iid int) error
+	UpdateRunner(id int, opt *gitlab.UpdateRunnerDetailsOptions) (*gitlab.RunnerDetails, error)
+	RegisterRunner(opt *gitlab.RegisterNewRunnerOptions) (*gitlab.Runner, error)
+	ListRunnerJobs(id int, opt *gitlab.ListRunnerJobsOptions, limit int, f func([]*gitlab.Job) error) error
+	EnableProjectRunner(pid string, id int) (*gitlab.Runner, error)
+	DisableProjectRunner(pid string, id int) error
 }
 
 type RunnerClient struct {
@@ -21,20 +26,36 @@ func NewRunnerClient(client *gitlab.Client) Runner {
 	return &RunnerClient{Client: client}
 }
 
-func (c *RunnerClient) ListAllRunners(opt *gitlab.ListRunnersOptions) ([]*gitlab.Runner, error) {
-	res, _, err := c.Client.Runners.ListAllRunners(opt)
-	if err != nil {
-		return nil, fmt.Errorf("failed list runners. %s", err.Error())
+// ListAllRunners passes the runners to f page by page until limit runners are
+// read. Every page is read when limit is zero or less.
+func (c *RunnerClient) ListAllRunners(opt *gitlab.ListRunnersOptions, limit int, f func([]*gitlab.Runner) error) error {
+	it := newPageIterator(&opt.ListOptions, limit)
+	for it.Next() {
+		runners, res, err := c.Client.Runners.ListAllRunners(opt)
+		if err != nil {
+			return fmt.Errorf("failed list runners. %s", err.Error())
+		}
+		if err := f(runners[:it.Read(len(runners), res)]); err != nil {
+			return err
+		}
 	}
-	return res, nil
+	return nil
 }
 
-func (c *RunnerClient) ListProjectRunners(pid string, opt *gitlab.ListProjectRunnersOptions) ([]*gitlab.Runner, error) {
-	res, _, err := c.Client.Runners.ListProjectRunners(pid, opt)
-	if err != nil {
-		return nil, fmt.Errorf("failed list project runners. %s", err.Error())
+// ListProjectRunners passes the runners of a project to f page by page until
+// limit runners are read. Every page is read when limit is zero or less.
+func (c *RunnerClient) ListProjectRunners(pid string, opt *gitlab.ListProjectRunnersOptions, limit int, f func([]*gitlab.Runner) error) error {
+	it := newPageIterator(&opt.ListOptions, limit)
+	for it.Next() {
+		runners, res, err := c.Client.Runners.ListProjectRunners(pid, opt)
+		if err != nil {
+			return fmt.Errorf("failed list project runners. %s", err.Error())
+		}
+		if err := f(runners[:it.Read(len(runners), res)]); err != nil {
+			return err
+		}
 	}
-	return res, nil
+	return nil
 }
 
 func (c *RunnerClient) RemoveRunner(iid int) error {
@@ -52,3 +73,114 @@ func (c *RunnerClient) GetRunnerDetails(id int) (*gitlab.RunnerDetails, error) {
 	}
 	return res, nil
 }
+
+func (c *RunnerClient) UpdateRunner(id int, opt *gitlab.UpdateRunnerDetailsOptions) (*gitlab.RunnerDetails, error) {
+	res, _, err := c.Client.Runners.UpdateRunnerDetails(id, opt)
+	if err != nil {
+		return nil, fmt.Errorf("failed update runner. %s", err.Error())
+	}
+	return res, nil
+}
+
+// RegisterRunner registers a runner by the registration token of the
+// instance, a group or a project. The returned runner has the token to
+// authenticate the runner with.
+func (c *RunnerClient) RegisterRunner(opt *gitlab.RegisterNewRunnerOptions) (*gitlab.Runner, error) {
+	res, _, err := c.Client.Runners.RegisterNewRunner(opt)
+	if err != nil {
+		return nil, fmt.Errorf("failed register runner. %s", err.Error())
+	}
+	return res, nil
+}
+
+// ListRunnerJobs passes the jobs processed by a runner to f page by page until
+// limit jobs are read. Every page is read when limit is zero or less.
+func (c *RunnerClient) ListRunnerJobs(id int, opt *gitlab.ListRunnerJobsOptions, limit int, f func([]*gitlab.Job) error) error {
+	it := newPageIterator(&opt.ListOptions, limit)
+	for it.Next() {
+		jobs, res, err := c.Client.Runners.ListRunnerJobs(id, opt)
+		if err != nil {
+			return fmt.Errorf("failed list runner jobs. %s", err.Error())
+		}
+		if err := f(jobs[:it.Read(len(jobs), res)]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *RunnerClient) EnableProjectRunner(pid string, id int) (*gitlab.Runner, error) {
+	res, _, err := c.Client.Runners.EnableProjectRunner(pid, &gitlab.EnableProjectRunnerOptions{RunnerID: id})
+	if err != nil {
+		return nil, fmt.Errorf("failed enable runner. %s", err.Error())
+	}
+	return res, nil
+}
+
+func (c *RunnerClient) DisableProjectRunner(pid string, id int) error {
+	if _, err := c.Client.Runners.DisableProjectRunner(pid, id); err != nil {
+		return fmt.Errorf("failed disable runner. %s", err.Error())
+	}
+	return nil
+}
+
+type MockRunnerClient struct {
+	Runner
+	MockListAllRunners       func(opt *gitlab.ListRunnersOptions) ([]*gitlab.Runner, error)
+	MockListProjectRunners   func(pid string, opt *gitlab.ListProjectRunnersOptions) ([]*gitlab.Runner, error)
+	MockGetRunnerDetails     func(id int) (*gitlab.RunnerDetails, error)
+	MockRemoveRunner         func(iid int) error
+	MockUpdateRunner         func(id int, opt *gitlab.UpdateRunnerDetailsOptions) (*gitlab.RunnerDetails, error)
+	MockRegisterRunner       func(opt *gitlab.RegisterNewRunnerOptions) (*gitlab.Runner, error)
+	MockListRunnerJobs       func(id int, opt *gitlab.ListRunnerJobsOptions) ([]*gitlab.Job, error)
+	MockEnableProjectRunner  func(pid string, id int) (*gitlab.Runner, error)
+	MockDisableProjectRunner func(pid string, id int) error
+}
+
+func (m *MockRunnerClient) ListAllRunners(opt *gitlab.ListRunnersOptions, limit int, f func([]*gitlab.Runner) error) error {
+	runners, err := m.MockListAllRunners(opt)
+	if err != nil {
+		return err
+	}
+	return f(runners)
+}
+
+func (m *MockRunnerClient) ListProjectRunners(pid string, opt *gitlab.ListProjectRunnersOptions, limit int, f func([]*gitlab.Runner) error) error {
+	runners, err := m.MockListProjectRunners(pid, opt)
+	if err != nil {
+		return err
+	}
+	return f(runners)
+}
+
+func (m *MockRunnerClient) GetRunnerDetails(id int) (*gitlab.RunnerDetails, error) {
+	return m.MockGetRunnerDetails(id)
+}
+
+func (m *MockRunnerClient) RemoveRunner(iid int) error {
+	return m.MockRemoveRunner(iid)
+}
+
+func (m *MockRunnerClient) UpdateRunner(id int, opt *gitlab.UpdateRunnerDetailsOptions) (*gitlab.RunnerDetails, error) {
+	return m.MockUpdateRunner(id, opt)
+}
+
+func (m *MockRunnerClient) RegisterRunner(opt *gitlab.RegisterNewRunnerOptions) (*gitlab.Runner, error) {
+	return m.MockRegisterRunner(opt)
+}
+
+func (m *MockRunnerClient) ListRunnerJobs(id int, opt *gitlab.ListRunnerJobsOptions, limit int, f func([]*gitlab.Job) error) error {
+	jobs, err := m.MockListRunnerJobs(id, opt)
+	if err != nil {
+		return err
+	}
+	return f(jobs)
+}
+
+func (m *MockRunnerClient) EnableProjectRunner(pid string, id int) (*gitlab.Runner, error) {
+	return m.MockEnableProjectRunner(pid, id)
+}
+
+func (m *MockRunnerClient) DisableProjectRunner(pid string, id int) error {
+	return m.MockDisableProjectRunner(pid, id)
+}
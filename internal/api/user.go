@@ -7,8 +7,8 @@ import (
 )
 
 type User interface {
-	Users(opt *gitlab.ListUsersOptions) ([]*gitlab.User, error)
-	ProjectUsers(repositoryName string, opt *gitlab.ListProjectUserOptions) ([]*gitlab.ProjectUser, error)
+	Users(opt *gitlab.ListUsersOptions, limit int, f func([]*gitlab.User) error) error
+	ProjectUsers(repositoryName string, opt *gitlab.ListProjectUserOptions, limit int, f func([]*gitlab.ProjectUser) error) error
 }
 
 type UserClient struct {
@@ -19,20 +19,36 @@ func NewUserClient(client *gitlab.Client) *UserClient {
 	return &UserClient{Client: client}
 }
 
-func (c *UserClient) Users(opt *gitlab.ListUsersOptions) ([]*gitlab.User, error) {
-	results, _, err := c.Client.Users.ListUsers(opt)
-	if err != nil {
-		return nil, fmt.Errorf("Failed list users. Error: %s", err.Error())
+// Users passes the users to f page by page until limit users are read.
+// Every page is read when limit is zero or less.
+func (c *UserClient) Users(opt *gitlab.ListUsersOptions, limit int, f func([]*gitlab.User) error) error {
+	it := newPageIterator(&opt.ListOptions, limit)
+	for it.Next() {
+		results, res, err := c.Client.Users.ListUsers(opt)
+		if err != nil {
+			return fmt.Errorf("Failed list users. Error: %s", err.Error())
+		}
+		if err := f(results[:it.Read(len(results), res)]); err != nil {
+			return err
+		}
 	}
-	return results, nil
+	return nil
 }
 
-func (c *UserClient) ProjectUsers(repositoryName string, opt *gitlab.ListProjectUserOptions) ([]*gitlab.ProjectUser, error) {
-	results, _, err := c.Client.Projects.ListProjectsUsers(repositoryName, opt)
-	if err != nil {
-		return nil, fmt.Errorf("Failed list project users. Error: %s", err.Error())
+// ProjectUsers passes the users of a project to f page by page until limit
+// users are read. Every page is read when limit is zero or less.
+func (c *UserClient) ProjectUsers(repositoryName string, opt *gitlab.ListProjectUserOptions, limit int, f func([]*gitlab.ProjectUser) error) error {
+	it := newPageIterator(&opt.ListOptions, limit)
+	for it.Next() {
+		results, res, err := c.Client.Projects.ListProjectsUsers(repositoryName, opt)
+		if err != nil {
+			return fmt.Errorf("Failed list project users. Error: %s", err.Error())
+		}
+		if err := f(results[:it.Read(len(results), res)]); err != nil {
+			return err
+		}
 	}
-	return results, nil
+	return nil
 }
 
 type MockUserClient struct {
@@ -40,10 +56,18 @@ type MockUserClient struct {
 	MockProjectUsers func(repositoryName string, opt *gitlab.ListProjectUserOptions) ([]*gitlab.ProjectUser, error)
 }
 
-func (m *MockUserClient) Users(opt *gitlab.ListUsersOptions) ([]*gitlab.User, error) {
-	return m.MockUsers(opt)
+func (m *MockUserClient) Users(opt *gitlab.ListUsersOptions, limit int, f func([]*gitlab.User) error) error {
+	results, err := m.MockUsers(opt)
+	if err != nil {
+		return err
+	}
+	return f(results)
 }
 
-func (m *MockUserClient) ProjectUsers(repositoryName string, opt *gitlab.ListProjectUserOptions) ([]*gitlab.ProjectUser, error) {
-	return m.MockProjectUsers(repositoryName, opt)
+func (m *MockUserClient) ProjectUsers(repositoryName string, opt *gitlab.ListProjectUserOptions, limit int, f func([]*gitlab.ProjectUser) error) error {
+	results, err := m.MockProjectUsers(repositoryName, opt)
+	if err != nil {
+		return err
+	}
+	return f(results)
 }
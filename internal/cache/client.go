@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"fmt"
+
+	"github.com/lighttiger2505/lab/internal/api"
+)
+
+// ClientFactory returns the clients of the issues, merge requests, notes and
+// pipelines which keep what they read from GitLab in the store. The clients
+// read the store instead of GitLab when they are offline, and refuse the
+// changes then. The other clients are the ones of the factory.
+type ClientFactory struct {
+	api.APIClientFactory
+	store   *Store
+	profile string
+	offline bool
+}
+
+func NewClientFactory(factory api.APIClientFactory, store *Store, profile string, offline bool) *ClientFactory {
+	return &ClientFactory{
+		APIClientFactory: factory,
+		store:            store,
+		profile:          profile,
+		offline:          offline,
+	}
+}
+
+func (f *ClientFactory) client() *client {
+	return &client{store: f.store, profile: f.profile, offline: f.offline}
+}
+
+func (f *ClientFactory) GetIssueClient() api.Issue {
+	return &issueClient{Issue: f.APIClientFactory.GetIssueClient(), client: f.client()}
+}
+
+func (f *ClientFactory) GetMergeRequestClient() api.MergeRequest {
+	return &mergeRequestClient{MergeRequest: f.APIClientFactory.GetMergeRequestClient(), client: f.client()}
+}
+
+func (f *ClientFactory) GetNoteClient() api.Note {
+	return &noteClient{Note: f.APIClientFactory.GetNoteClient(), client: f.client()}
+}
+
+func (f *ClientFactory) GetPipelineClient() api.Pipeline {
+	return &pipelineClient{Pipeline: f.APIClientFactory.GetPipelineClient(), client: f.client()}
+}
+
+// client is the store of a profile shared by the clients.
+type client struct {
+	store   *Store
+	profile string
+	offline bool
+}
+
+// refuseOffline returns the error of a change made offline.
+func (c *client) refuseOffline(action string) error {
+	if c.offline {
+		return fmt.Errorf("Cannot %s offline", action)
+	}
+	return nil
+}
+
+// put changes a file of a project in the store. The store is only a copy of
+// GitLab, so a failure of writing it does not fail the command.
+func (c *client) put(project, name string, v interface{}, f func()) {
+	c.store.update(c.profile, project, name, v, f)
+}
+
+func notCachedError(kind string, id int) error {
+	return fmt.Errorf("Not found %s %d in the cache. Please run \"lab sync\"", kind, id)
+}
+
+func notSyncedError(kind, project string) error {
+	return fmt.Errorf("Not found the %s of %s in the cache. Please run \"lab sync\"", kind, project)
+}
@@ -0,0 +1,147 @@
+package cache
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func newTestStore(t *testing.T) *Store {
+	dir, err := ioutil.TempDir("", "lab-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewStore(dir)
+}
+
+var testIssues = []*gitlab.Issue{
+	{IID: 1, State: "opened", Title: "title1", UpdatedAt: testTime(1)},
+	{IID: 2, State: "closed", Title: "title2", UpdatedAt: testTime(2)},
+}
+
+func newTestFactory() *api.MockAPIClientFactory {
+	return &api.MockAPIClientFactory{
+		MockGetIssueClient: func() api.Issue {
+			return &api.MockLabIssueClient{
+				MockGetProjectIssues: func(opt *gitlab.ListProjectIssuesOptions, repositoryName string) ([]*gitlab.Issue, error) {
+					return testIssues, nil
+				},
+			}
+		},
+		MockGetMergeRequestClient: func() api.MergeRequest {
+			return &api.MockLabMergeRequestClient{}
+		},
+		MockGetPipelineClient: func() api.Pipeline {
+			return &api.MockPipelineClient{
+				MockProjectPipelines: func(repositoryName string, opt *gitlab.ListProjectPipelinesOptions) (gitlab.PipelineList, error) {
+					list := make(gitlab.PipelineList, 3)
+					for i, status := range []string{"success", "running", "failed"} {
+						list[i].ID = i + 1
+						list[i].Status = status
+						list[i].Ref = "master"
+					}
+					return list, nil
+				},
+			}
+		},
+	}
+}
+
+func readIssues(client api.Issue, opt *gitlab.ListProjectIssuesOptions) ([]int, error) {
+	var got []int
+	err := client.GetProjectIssues(opt, "group/project", 0, func(issues []*gitlab.Issue) error {
+		for _, issue := range issues {
+			got = append(got, issue.IID)
+		}
+		return nil
+	})
+	return got, err
+}
+
+func TestClientFactory_issues(t *testing.T) {
+	store := newTestStore(t)
+	defer os.RemoveAll(store.Dir())
+
+	offline := NewClientFactory(newTestFactory(), store, "gitlab.com", true)
+	if _, err := readIssues(offline.GetIssueClient(), &gitlab.ListProjectIssuesOptions{}); err == nil {
+		t.Errorf("GetProjectIssues() error = nil, want the error of the empty cache")
+	}
+
+	online := NewClientFactory(newTestFactory(), store, "gitlab.com", false)
+	if _, err := readIssues(online.GetIssueClient(), &gitlab.ListProjectIssuesOptions{}); err != nil {
+		t.Fatalf("GetProjectIssues() error = %v", err)
+	}
+
+	got, err := readIssues(offline.GetIssueClient(), &gitlab.ListProjectIssuesOptions{State: gitlab.String("opened")})
+	if err != nil {
+		t.Fatalf("GetProjectIssues() error = %v", err)
+	}
+	if diff := cmp.Diff(got, []int{1}); diff != "" {
+		t.Errorf("invalide arg (-got +want)\n%s", diff)
+	}
+
+	issue, err := offline.GetIssueClient().GetIssue(2, "group/project")
+	if err != nil {
+		t.Fatalf("GetIssue() error = %v", err)
+	}
+	if issue.Title != "title2" {
+		t.Errorf("GetIssue() title = %q, want %q", issue.Title, "title2")
+	}
+	if _, err := offline.GetIssueClient().GetIssue(3, "group/project"); err == nil {
+		t.Errorf("GetIssue() error = nil, want the error of the missing issue")
+	}
+	if _, err := offline.GetIssueClient().CreateIssue(&gitlab.CreateIssueOptions{}, "group/project"); err == nil {
+		t.Errorf("CreateIssue() error = nil, want the error of offline")
+	}
+}
+
+func TestClientFactory_mergeRequestApprovals(t *testing.T) {
+	store := newTestStore(t)
+	defer os.RemoveAll(store.Dir())
+
+	offline := NewClientFactory(newTestFactory(), store, "gitlab.com", true)
+	_, err := offline.GetMergeRequestClient().GetMergeRequestApprovals(1, "group/project")
+	if got := api.StatusCode(err); got != http.StatusNotFound {
+		t.Errorf("GetMergeRequestApprovals() status = %d, want %d", got, http.StatusNotFound)
+	}
+}
+
+func TestClientFactory_pipelines(t *testing.T) {
+	store := newTestStore(t)
+	defer os.RemoveAll(store.Dir())
+
+	online := NewClientFactory(newTestFactory(), store, "gitlab.com", false)
+	err := online.GetPipelineClient().ProjectPipelines("group/project", &gitlab.ListProjectPipelinesOptions{}, 0, func(gitlab.PipelineList) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ProjectPipelines() error = %v", err)
+	}
+
+	offline := NewClientFactory(newTestFactory(), store, "gitlab.com", true)
+	var got []int
+	err = offline.GetPipelineClient().ProjectPipelines("group/project", &gitlab.ListProjectPipelinesOptions{Scope: gitlab.String("finished")}, 0, func(list gitlab.PipelineList) error {
+		for _, p := range list {
+			got = append(got, p.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ProjectPipelines() error = %v", err)
+	}
+	if diff := cmp.Diff(got, []int{3, 1}); diff != "" {
+		t.Errorf("invalide arg (-got +want)\n%s", diff)
+	}
+
+	if _, err := offline.GetPipelineClient().GetPipeline("group/project", 2); err != nil {
+		t.Errorf("GetPipeline() error = %v", err)
+	}
+	if _, err := offline.GetPipelineClient().RetryPipeline("group/project", 2); err == nil {
+		t.Errorf("RetryPipeline() error = nil, want the error of offline")
+	}
+}
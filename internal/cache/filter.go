@@ -0,0 +1,252 @@
+package cache
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+// entry is an issue or a merge request, with the fields filtered by.
+type entry struct {
+	iid          int
+	state        string
+	title        string
+	description  string
+	labels       []string
+	milestone    *gitlab.Milestone
+	authorID     int
+	assigneeIDs  []int
+	sourceBranch string
+	targetBranch string
+	createdAt    *time.Time
+	updatedAt    *time.Time
+}
+
+// filter is the list options of issues and merge requests applied to the
+// entries in the cache, as GitLab would do.
+type filter struct {
+	state        string
+	milestone    string
+	search       string
+	labels       []string
+	notLabels    []string
+	authorID     int
+	assigneeID   int
+	sourceBranch string
+	targetBranch string
+	orderBy      string
+	sort         string
+}
+
+func newIssueFilter(opt *gitlab.ListProjectIssuesOptions, options []gitlab.OptionFunc) (*filter, error) {
+	if err := checkScope(opt.Scope); err != nil {
+		return nil, err
+	}
+	query, err := queryOf(options)
+	if err != nil {
+		return nil, err
+	}
+	return &filter{
+		state:      stringValue(opt.State),
+		milestone:  stringValue(opt.Milestone),
+		search:     stringValue(opt.Search),
+		labels:     splitLabels(opt.Labels),
+		notLabels:  splitLabels([]string{query.Get("not[labels]")}),
+		authorID:   intValue(opt.AuthorID),
+		assigneeID: intValue(opt.AssigneeID),
+		orderBy:    stringValue(opt.OrderBy),
+		sort:       stringValue(opt.Sort),
+	}, nil
+}
+
+func newMergeRequestFilter(opt *gitlab.ListProjectMergeRequestsOptions, options []gitlab.OptionFunc) (*filter, error) {
+	if err := checkScope(opt.Scope); err != nil {
+		return nil, err
+	}
+	query, err := queryOf(options)
+	if err != nil {
+		return nil, err
+	}
+	return &filter{
+		state:        stringValue(opt.State),
+		milestone:    stringValue(opt.Milestone),
+		search:       stringValue(opt.Search),
+		labels:       splitLabels(opt.Labels),
+		notLabels:    splitLabels([]string{query.Get("not[labels]")}),
+		authorID:     intValue(opt.AuthorID),
+		assigneeID:   intValue(opt.AssigneeID),
+		sourceBranch: stringValue(opt.SourceBranch),
+		targetBranch: stringValue(opt.TargetBranch),
+		orderBy:      stringValue(opt.OrderBy),
+		sort:         stringValue(opt.Sort),
+	}, nil
+}
+
+// checkScope returns an error for the scopes of the current user, who is not
+// known offline.
+func checkScope(scope *string) error {
+	switch stringValue(scope) {
+	case "", "all":
+		return nil
+	}
+	return fmt.Errorf("Cannot filter by the scope %s offline", *scope)
+}
+
+// queryOf returns the query the option funcs set to a request, like the
+// "not[labels]" of api.WithNotLabels.
+func queryOf(options []gitlab.OptionFunc) (url.Values, error) {
+	req, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, option := range options {
+		if err := option(req); err != nil {
+			return nil, err
+		}
+	}
+	return req.URL.Query(), nil
+}
+
+// apply returns the entries matching the filter in the order of it, at most
+// limit entries. Every entry is returned when limit is zero or less.
+func (f *filter) apply(entries []*entry, limit int) []*entry {
+	var matched []*entry
+	for _, e := range entries {
+		if f.match(e) {
+			matched = append(matched, e)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		a, b := f.sortTime(matched[i]), f.sortTime(matched[j])
+		if !a.Equal(b) {
+			if f.sort == "asc" {
+				return a.Before(b)
+			}
+			return a.After(b)
+		}
+		if f.sort == "asc" {
+			return matched[i].iid < matched[j].iid
+		}
+		return matched[i].iid > matched[j].iid
+	})
+
+	if limit > 0 && len(matched) > limit {
+		matched = matched[:limit]
+	}
+	return matched
+}
+
+func (f *filter) sortTime(e *entry) time.Time {
+	t := e.updatedAt
+	if f.orderBy == "created_at" {
+		t = e.createdAt
+	}
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+func (f *filter) match(e *entry) bool {
+	if f.state != "" && f.state != "all" && f.state != e.state {
+		return false
+	}
+	if !matchMilestone(f.milestone, e.milestone) {
+		return false
+	}
+	if f.search != "" {
+		search := strings.ToLower(f.search)
+		if !strings.Contains(strings.ToLower(e.title), search) &&
+			!strings.Contains(strings.ToLower(e.description), search) {
+			return false
+		}
+	}
+	for _, label := range f.labels {
+		if !containsString(e.labels, label) {
+			return false
+		}
+	}
+	for _, label := range f.notLabels {
+		if containsString(e.labels, label) {
+			return false
+		}
+	}
+	if f.authorID != 0 && f.authorID != e.authorID {
+		return false
+	}
+	if f.assigneeID != 0 && !containsInt(e.assigneeIDs, f.assigneeID) {
+		return false
+	}
+	if f.sourceBranch != "" && f.sourceBranch != e.sourceBranch {
+		return false
+	}
+	if f.targetBranch != "" && f.targetBranch != e.targetBranch {
+		return false
+	}
+	return true
+}
+
+// matchMilestone matches the title of the milestone, or "None" and "Any" as
+// GitLab does.
+func matchMilestone(want string, milestone *gitlab.Milestone) bool {
+	switch want {
+	case "":
+		return true
+	case "None":
+		return milestone == nil
+	case "Any":
+		return milestone != nil
+	}
+	return milestone != nil && milestone.Title == want
+}
+
+// splitLabels returns the labels given as the list or separated by comma.
+func splitLabels(values []string) []string {
+	var labels []string
+	for _, value := range values {
+		for _, label := range strings.Split(value, ",") {
+			if label = strings.TrimSpace(label); label != "" {
+				labels = append(labels, label)
+			}
+		}
+	}
+	return labels
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func intValue(i *int) int {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func testTime(day int) *time.Time {
+	t := time.Date(2019, 6, day, 0, 0, 0, 0, time.UTC)
+	return &t
+}
+
+var testEntries = []*entry{
+	{
+		iid:       1,
+		state:     "opened",
+		title:     "Fix the login",
+		labels:    []string{"bug"},
+		milestone: &gitlab.Milestone{Title: "v1.0"},
+		authorID:  10,
+		createdAt: testTime(1),
+		updatedAt: testTime(5),
+	},
+	{
+		iid:         2,
+		state:       "closed",
+		title:       "Add the logout",
+		description: "The login page needs it",
+		labels:      []string{"feature", "bug"},
+		authorID:    11,
+		assigneeIDs: []int{10},
+		createdAt:   testTime(2),
+		updatedAt:   testTime(3),
+	},
+	{
+		iid:       3,
+		state:     "opened",
+		title:     "Update the document",
+		labels:    []string{"document"},
+		authorID:  10,
+		createdAt: testTime(3),
+		updatedAt: testTime(4),
+	},
+}
+
+func iids(entries []*entry) []int {
+	var got []int
+	for _, e := range entries {
+		got = append(got, e.iid)
+	}
+	return got
+}
+
+func Test_filter_apply(t *testing.T) {
+	tests := []struct {
+		name    string
+		opt     *gitlab.ListProjectIssuesOptions
+		options []gitlab.OptionFunc
+		limit   int
+		want    []int
+	}{
+		{
+			name: "updated desc by default",
+			opt:  &gitlab.ListProjectIssuesOptions{},
+			want: []int{1, 3, 2},
+		},
+		{
+			name: "created asc",
+			opt: &gitlab.ListProjectIssuesOptions{
+				OrderBy: gitlab.String("created_at"),
+				Sort:    gitlab.String("asc"),
+			},
+			want: []int{1, 2, 3},
+		},
+		{
+			name:  "limit",
+			opt:   &gitlab.ListProjectIssuesOptions{},
+			limit: 2,
+			want:  []int{1, 3},
+		},
+		{
+			name: "state",
+			opt:  &gitlab.ListProjectIssuesOptions{State: gitlab.String("opened")},
+			want: []int{1, 3},
+		},
+		{
+			name: "search in title and description",
+			opt:  &gitlab.ListProjectIssuesOptions{Search: gitlab.String("LOGIN")},
+			want: []int{1, 2},
+		},
+		{
+			name:    "labels",
+			opt:     &gitlab.ListProjectIssuesOptions{Labels: gitlab.Labels{"bug"}},
+			options: []gitlab.OptionFunc{api.WithNotLabels([]string{"feature"})},
+			want:    []int{1},
+		},
+		{
+			name: "no milestone",
+			opt:  &gitlab.ListProjectIssuesOptions{Milestone: gitlab.String("None")},
+			want: []int{3, 2},
+		},
+		{
+			name: "author and assignee",
+			opt: &gitlab.ListProjectIssuesOptions{
+				AuthorID:   gitlab.Int(11),
+				AssigneeID: gitlab.Int(10),
+			},
+			want: []int{2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := newIssueFilter(tt.opt, tt.options)
+			if err != nil {
+				t.Fatalf("newIssueFilter() error = %v", err)
+			}
+			got := iids(f.apply(testEntries, tt.limit))
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("invalide arg (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_newIssueFilter_scope(t *testing.T) {
+	if _, err := newIssueFilter(&gitlab.ListProjectIssuesOptions{Scope: gitlab.String("all")}, nil); err != nil {
+		t.Errorf("newIssueFilter() error = %v", err)
+	}
+	if _, err := newIssueFilter(&gitlab.ListProjectIssuesOptions{Scope: gitlab.String("created-by-me")}, nil); err == nil {
+		t.Errorf("newIssueFilter() error = nil, want the error of the scope")
+	}
+}
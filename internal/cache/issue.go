@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"fmt"
+
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+type issueClient struct {
+	api.Issue
+	*client
+}
+
+func (c *issueClient) GetIssue(iid int, project string) (*gitlab.Issue, error) {
+	if c.offline {
+		var cache issues
+		if err := c.store.load(c.profile, project, issuesFile, &cache); err != nil {
+			return nil, err
+		}
+		issue, ok := cache.Issues[iid]
+		if !ok {
+			return nil, notCachedError("issue", iid)
+		}
+		return issue, nil
+	}
+
+	issue, err := c.Issue.GetIssue(iid, project)
+	if err != nil {
+		return nil, err
+	}
+	c.putIssues(project, []*gitlab.Issue{issue})
+	return issue, nil
+}
+
+func (c *issueClient) GetAllProjectIssues(opt *gitlab.ListIssuesOptions, limit int, f func([]*gitlab.Issue) error, options ...gitlab.OptionFunc) error {
+	if c.offline {
+		return fmt.Errorf("Cannot list the issues of all projects offline")
+	}
+	return c.Issue.GetAllProjectIssues(opt, limit, f, options...)
+}
+
+func (c *issueClient) GetProjectIssues(opt *gitlab.ListProjectIssuesOptions, project string, limit int, f func([]*gitlab.Issue) error, options ...gitlab.OptionFunc) error {
+	if c.offline {
+		filter, err := newIssueFilter(opt, options)
+		if err != nil {
+			return err
+		}
+		var cache issues
+		if err := c.store.load(c.profile, project, issuesFile, &cache); err != nil {
+			return err
+		}
+		if cache.SyncedAt == nil && len(cache.Issues) == 0 {
+			return notSyncedError("issues", project)
+		}
+
+		byEntry := map[*entry]*gitlab.Issue{}
+		var entries []*entry
+		for _, issue := range cache.Issues {
+			e := issueEntry(issue)
+			byEntry[e] = issue
+			entries = append(entries, e)
+		}
+		var matched []*gitlab.Issue
+		for _, e := range filter.apply(entries, limit) {
+			matched = append(matched, byEntry[e])
+		}
+		return f(matched)
+	}
+
+	var read []*gitlab.Issue
+	err := c.Issue.GetProjectIssues(opt, project, limit, func(page []*gitlab.Issue) error {
+		read = append(read, page...)
+		return f(page)
+	}, options...)
+	c.putIssues(project, read)
+	return err
+}
+
+func (c *issueClient) CreateIssue(opt *gitlab.CreateIssueOptions, project string) (*gitlab.Issue, error) {
+	if err := c.refuseOffline("create issue"); err != nil {
+		return nil, err
+	}
+	issue, err := c.Issue.CreateIssue(opt, project)
+	if err != nil {
+		return nil, err
+	}
+	c.putIssues(project, []*gitlab.Issue{issue})
+	return issue, nil
+}
+
+func (c *issueClient) UpdateIssue(opt *gitlab.UpdateIssueOptions, iid int, project string) (*gitlab.Issue, error) {
+	if err := c.refuseOffline("update issue"); err != nil {
+		return nil, err
+	}
+	issue, err := c.Issue.UpdateIssue(opt, iid, project)
+	if err != nil {
+		return nil, err
+	}
+	c.putIssues(project, []*gitlab.Issue{issue})
+	return issue, nil
+}
+
+func (c *issueClient) putIssues(project string, read []*gitlab.Issue) {
+	if len(read) == 0 {
+		return
+	}
+	var cache issues
+	c.put(project, issuesFile, &cache, func() {
+		cache.init()
+		for _, issue := range read {
+			cache.Issues[issue.IID] = issue
+		}
+	})
+}
+
+func issueEntry(issue *gitlab.Issue) *entry {
+	e := &entry{
+		iid:         issue.IID,
+		state:       issue.State,
+		title:       issue.Title,
+		description: issue.Description,
+		labels:      issue.Labels,
+		milestone:   issue.Milestone,
+		authorID:    issue.Author.ID,
+		createdAt:   issue.CreatedAt,
+		updatedAt:   issue.UpdatedAt,
+	}
+	if issue.Assignee.ID != 0 {
+		e.assigneeIDs = append(e.assigneeIDs, issue.Assignee.ID)
+	}
+	for _, assignee := range issue.Assignees {
+		if !containsInt(e.assigneeIDs, assignee.ID) {
+			e.assigneeIDs = append(e.assigneeIDs, assignee.ID)
+		}
+	}
+	return e
+}
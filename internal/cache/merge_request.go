@@ -0,0 +1,207 @@
+package cache
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+type mergeRequestClient struct {
+	api.MergeRequest
+	*client
+}
+
+func (c *mergeRequestClient) GetMergeRequest(iid int, project string) (*gitlab.MergeRequest, error) {
+	if c.offline {
+		var cache mergeRequests
+		if err := c.store.load(c.profile, project, mergeRequestsFile, &cache); err != nil {
+			return nil, err
+		}
+		mergeRequest, ok := cache.MergeRequests[iid]
+		if !ok {
+			return nil, notCachedError("merge request", iid)
+		}
+		return mergeRequest, nil
+	}
+
+	mergeRequest, err := c.MergeRequest.GetMergeRequest(iid, project)
+	if err != nil {
+		return nil, err
+	}
+	c.putMergeRequests(project, []*gitlab.MergeRequest{mergeRequest})
+	return mergeRequest, nil
+}
+
+func (c *mergeRequestClient) GetMergeRequestChanges(iid int, project string) (*gitlab.MergeRequest, error) {
+	if c.offline {
+		return nil, fmt.Errorf("Cannot read the changes of merge request offline")
+	}
+	return c.MergeRequest.GetMergeRequestChanges(iid, project)
+}
+
+func (c *mergeRequestClient) GetMergeRequestDiffVersions(iid int, project string) ([]*gitlab.MergeRequestDiffVersion, error) {
+	if c.offline {
+		return nil, fmt.Errorf("Cannot read the diff versions of merge request offline")
+	}
+	return c.MergeRequest.GetMergeRequestDiffVersions(iid, project)
+}
+
+func (c *mergeRequestClient) GetAllProjectMergeRequest(opt *gitlab.ListMergeRequestsOptions, limit int, f func([]*gitlab.MergeRequest) error, options ...gitlab.OptionFunc) error {
+	if c.offline {
+		return fmt.Errorf("Cannot list the merge requests of all projects offline")
+	}
+	return c.MergeRequest.GetAllProjectMergeRequest(opt, limit, f, options...)
+}
+
+func (c *mergeRequestClient) GetProjectMargeRequest(opt *gitlab.ListProjectMergeRequestsOptions, project string, limit int, f func([]*gitlab.MergeRequest) error, options ...gitlab.OptionFunc) error {
+	if c.offline {
+		filter, err := newMergeRequestFilter(opt, options)
+		if err != nil {
+			return err
+		}
+		var cache mergeRequests
+		if err := c.store.load(c.profile, project, mergeRequestsFile, &cache); err != nil {
+			return err
+		}
+		if cache.SyncedAt == nil && len(cache.MergeRequests) == 0 {
+			return notSyncedError("merge requests", project)
+		}
+
+		byEntry := map[*entry]*gitlab.MergeRequest{}
+		var entries []*entry
+		for _, mergeRequest := range cache.MergeRequests {
+			e := mergeRequestEntry(mergeRequest)
+			byEntry[e] = mergeRequest
+			entries = append(entries, e)
+		}
+		var matched []*gitlab.MergeRequest
+		for _, e := range filter.apply(entries, limit) {
+			matched = append(matched, byEntry[e])
+		}
+		return f(matched)
+	}
+
+	var read []*gitlab.MergeRequest
+	err := c.MergeRequest.GetProjectMargeRequest(opt, project, limit, func(page []*gitlab.MergeRequest) error {
+		read = append(read, page...)
+		return f(page)
+	}, options...)
+	c.putMergeRequests(project, read)
+	return err
+}
+
+func (c *mergeRequestClient) CreateMergeRequest(opt *gitlab.CreateMergeRequestOptions, project string) (*gitlab.MergeRequest, error) {
+	if err := c.refuseOffline("create merge request"); err != nil {
+		return nil, err
+	}
+	mergeRequest, err := c.MergeRequest.CreateMergeRequest(opt, project)
+	if err != nil {
+		return nil, err
+	}
+	c.putMergeRequests(project, []*gitlab.MergeRequest{mergeRequest})
+	return mergeRequest, nil
+}
+
+func (c *mergeRequestClient) UpdateMergeRequest(opt *gitlab.UpdateMergeRequestOptions, iid int, project string) (*gitlab.MergeRequest, error) {
+	if err := c.refuseOffline("update merge request"); err != nil {
+		return nil, err
+	}
+	mergeRequest, err := c.MergeRequest.UpdateMergeRequest(opt, iid, project)
+	if err != nil {
+		return nil, err
+	}
+	c.putMergeRequests(project, []*gitlab.MergeRequest{mergeRequest})
+	return mergeRequest, nil
+}
+
+func (c *mergeRequestClient) AcceptMergeRequest(opt *api.AcceptMergeRequestOptions, iid int, project string) (*gitlab.MergeRequest, error) {
+	if err := c.refuseOffline("merge merge request"); err != nil {
+		return nil, err
+	}
+	mergeRequest, err := c.MergeRequest.AcceptMergeRequest(opt, iid, project)
+	if err != nil {
+		return nil, err
+	}
+	c.putMergeRequests(project, []*gitlab.MergeRequest{mergeRequest})
+	return mergeRequest, nil
+}
+
+// GetMergeRequestApprovals returns the approvals in the cache offline. The
+// approvals not in the cache are not found, as on the GitLab editions
+// without approvals.
+func (c *mergeRequestClient) GetMergeRequestApprovals(iid int, project string) (*gitlab.MergeRequestApprovals, error) {
+	if c.offline {
+		var cache mergeRequests
+		if err := c.store.load(c.profile, project, mergeRequestsFile, &cache); err != nil {
+			return nil, err
+		}
+		approvals, ok := cache.Approvals[iid]
+		if !ok {
+			return nil, &api.ResponseError{
+				StatusCode: http.StatusNotFound,
+				Message:    fmt.Sprintf("Not found the approvals of merge request %d in the cache", iid),
+			}
+		}
+		return approvals, nil
+	}
+
+	approvals, err := c.MergeRequest.GetMergeRequestApprovals(iid, project)
+	if err != nil {
+		return nil, err
+	}
+	var cache mergeRequests
+	c.put(project, mergeRequestsFile, &cache, func() {
+		cache.init()
+		cache.Approvals[iid] = approvals
+	})
+	return approvals, nil
+}
+
+func (c *mergeRequestClient) ApproveMergeRequest(opt *gitlab.ApproveMergeRequestOptions, iid int, project string) (*gitlab.MergeRequestApprovals, error) {
+	if err := c.refuseOffline("approve merge request"); err != nil {
+		return nil, err
+	}
+	return c.MergeRequest.ApproveMergeRequest(opt, iid, project)
+}
+
+func (c *mergeRequestClient) UnapproveMergeRequest(iid int, project string) error {
+	if err := c.refuseOffline("unapprove merge request"); err != nil {
+		return err
+	}
+	return c.MergeRequest.UnapproveMergeRequest(iid, project)
+}
+
+func (c *mergeRequestClient) putMergeRequests(project string, read []*gitlab.MergeRequest) {
+	if len(read) == 0 {
+		return
+	}
+	var cache mergeRequests
+	c.put(project, mergeRequestsFile, &cache, func() {
+		cache.init()
+		for _, mergeRequest := range read {
+			cache.MergeRequests[mergeRequest.IID] = mergeRequest
+		}
+	})
+}
+
+func mergeRequestEntry(mergeRequest *gitlab.MergeRequest) *entry {
+	e := &entry{
+		iid:          mergeRequest.IID,
+		state:        mergeRequest.State,
+		title:        mergeRequest.Title,
+		description:  mergeRequest.Description,
+		labels:       mergeRequest.Labels,
+		milestone:    mergeRequest.Milestone,
+		authorID:     mergeRequest.Author.ID,
+		sourceBranch: mergeRequest.SourceBranch,
+		targetBranch: mergeRequest.TargetBranch,
+		createdAt:    mergeRequest.CreatedAt,
+		updatedAt:    mergeRequest.UpdatedAt,
+	}
+	if mergeRequest.Assignee.ID != 0 {
+		e.assigneeIDs = append(e.assigneeIDs, mergeRequest.Assignee.ID)
+	}
+	return e
+}
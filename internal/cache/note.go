@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"fmt"
+
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+type noteClient struct {
+	api.Note
+	*client
+}
+
+func (c *noteClient) GetIssueNotes(project string, iid int, opt *gitlab.ListIssueNotesOptions) ([]*gitlab.Note, error) {
+	var cache issues
+	if c.offline {
+		if err := c.store.load(c.profile, project, issuesFile, &cache); err != nil {
+			return nil, err
+		}
+		notes, ok := cache.Notes[iid]
+		if !ok {
+			return nil, notCachedNotesError("issue", iid)
+		}
+		return notes, nil
+	}
+
+	notes, err := c.Note.GetIssueNotes(project, iid, opt)
+	if err != nil {
+		return nil, err
+	}
+	c.put(project, issuesFile, &cache, func() {
+		cache.init()
+		cache.Notes[iid] = notes
+	})
+	return notes, nil
+}
+
+func (c *noteClient) GetMergeRequestNotes(project string, iid int, opt *gitlab.ListMergeRequestNotesOptions) ([]*gitlab.Note, error) {
+	var cache mergeRequests
+	if c.offline {
+		if err := c.store.load(c.profile, project, mergeRequestsFile, &cache); err != nil {
+			return nil, err
+		}
+		notes, ok := cache.Notes[iid]
+		if !ok {
+			return nil, notCachedNotesError("merge request", iid)
+		}
+		return notes, nil
+	}
+
+	notes, err := c.Note.GetMergeRequestNotes(project, iid, opt)
+	if err != nil {
+		return nil, err
+	}
+	c.put(project, mergeRequestsFile, &cache, func() {
+		cache.init()
+		cache.Notes[iid] = notes
+	})
+	return notes, nil
+}
+
+func (c *noteClient) GetIssueNote(project string, iid, noteID int) (*gitlab.Note, error) {
+	if err := c.refuseOffline("read comment"); err != nil {
+		return nil, err
+	}
+	return c.Note.GetIssueNote(project, iid, noteID)
+}
+
+func (c *noteClient) CreateIssueNote(project string, iid int, opt *gitlab.CreateIssueNoteOptions) (*gitlab.Note, error) {
+	if err := c.refuseOffline("comment"); err != nil {
+		return nil, err
+	}
+	return c.Note.CreateIssueNote(project, iid, opt)
+}
+
+func (c *noteClient) UpdateIssueNote(project string, iid, noteID int, opt *gitlab.UpdateIssueNoteOptions) (*gitlab.Note, error) {
+	if err := c.refuseOffline("edit comment"); err != nil {
+		return nil, err
+	}
+	return c.Note.UpdateIssueNote(project, iid, noteID, opt)
+}
+
+func (c *noteClient) DeleteIssueNote(project string, iid, noteID int) error {
+	if err := c.refuseOffline("delete comment"); err != nil {
+		return err
+	}
+	return c.Note.DeleteIssueNote(project, iid, noteID)
+}
+
+func (c *noteClient) GetMergeRequestNote(project string, iid, noteID int) (*gitlab.Note, error) {
+	if err := c.refuseOffline("read comment"); err != nil {
+		return nil, err
+	}
+	return c.Note.GetMergeRequestNote(project, iid, noteID)
+}
+
+func (c *noteClient) CreateMergeRequestNote(project string, iid int, opt *gitlab.CreateMergeRequestNoteOptions) (*gitlab.Note, error) {
+	if err := c.refuseOffline("comment"); err != nil {
+		return nil, err
+	}
+	return c.Note.CreateMergeRequestNote(project, iid, opt)
+}
+
+func (c *noteClient) UpdateMergeRequestNote(project string, iid, noteID int, opt *gitlab.UpdateMergeRequestNoteOptions) (*gitlab.Note, error) {
+	if err := c.refuseOffline("edit comment"); err != nil {
+		return nil, err
+	}
+	return c.Note.UpdateMergeRequestNote(project, iid, noteID, opt)
+}
+
+func (c *noteClient) DeleteMergeRequestNote(project string, iid, noteID int) error {
+	if err := c.refuseOffline("delete comment"); err != nil {
+		return err
+	}
+	return c.Note.DeleteMergeRequestNote(project, iid, noteID)
+}
+
+func notCachedNotesError(kind string, iid int) error {
+	return fmt.Errorf("Not found the comments of %s %d in the cache. Please read it online once, or give --no-comment", kind, iid)
+}
@@ -0,0 +1,219 @@
+package cache
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+type pipelineClient struct {
+	api.Pipeline
+	*client
+}
+
+func (c *pipelineClient) ProjectPipelines(project string, opt *gitlab.ListProjectPipelinesOptions, limit int, f func(gitlab.PipelineList) error, options ...gitlab.OptionFunc) error {
+	if c.offline {
+		var cache pipelines
+		if err := c.store.load(c.profile, project, pipelinesFile, &cache); err != nil {
+			return err
+		}
+		if cache.SyncedAt == nil && len(cache.Pipelines) == 0 {
+			return notSyncedError("pipelines", project)
+		}
+		matched, err := filterPipelines(cache.Pipelines, opt, limit)
+		if err != nil {
+			return err
+		}
+		list := make(gitlab.PipelineList, len(matched))
+		for i, pipeline := range matched {
+			list[i].ID = pipeline.ID
+			list[i].Status = pipeline.Status
+			list[i].Ref = pipeline.Ref
+			list[i].Sha = pipeline.Sha
+		}
+		return f(list)
+	}
+
+	var read gitlab.PipelineList
+	err := c.Pipeline.ProjectPipelines(project, opt, limit, func(page gitlab.PipelineList) error {
+		read = append(read, page...)
+		return f(page)
+	}, options...)
+	if len(read) > 0 {
+		var cache pipelines
+		c.put(project, pipelinesFile, &cache, func() {
+			cache.putList(read)
+		})
+	}
+	return err
+}
+
+// ProjectPipelineJobs returns the jobs in the cache offline, filtered by the
+// scope. The jobs are kept only when every job of the pipeline is read.
+func (c *pipelineClient) ProjectPipelineJobs(project string, opt *gitlab.ListJobsOptions, pid int, limit int, f func([]*gitlab.Job) error, options ...gitlab.OptionFunc) error {
+	if c.offline {
+		var cache pipelines
+		if err := c.store.load(c.profile, project, pipelinesFile, &cache); err != nil {
+			return err
+		}
+		jobs, ok := cache.Jobs[pid]
+		if !ok {
+			return fmt.Errorf("Not found the jobs of pipeline %d in the cache. Please run \"lab sync\"", pid)
+		}
+		var matched []*gitlab.Job
+		for _, job := range jobs {
+			if len(opt.Scope) == 0 || containsState(opt.Scope, job.Status) {
+				matched = append(matched, job)
+			}
+		}
+		if limit > 0 && len(matched) > limit {
+			matched = matched[:limit]
+		}
+		return f(matched)
+	}
+
+	whole := limit <= 0 && len(opt.Scope) == 0 && len(options) == 0
+	var read []*gitlab.Job
+	err := c.Pipeline.ProjectPipelineJobs(project, opt, pid, limit, func(page []*gitlab.Job) error {
+		read = append(read, page...)
+		return f(page)
+	}, options...)
+	if err == nil && whole {
+		var cache pipelines
+		c.put(project, pipelinesFile, &cache, func() {
+			cache.init()
+			cache.Jobs[pid] = read
+		})
+	}
+	return err
+}
+
+func (c *pipelineClient) GetPipeline(project string, pid int) (*gitlab.Pipeline, error) {
+	if c.offline {
+		var cache pipelines
+		if err := c.store.load(c.profile, project, pipelinesFile, &cache); err != nil {
+			return nil, err
+		}
+		pipeline, ok := cache.Pipelines[pid]
+		if !ok {
+			return nil, notCachedError("pipeline", pid)
+		}
+		return pipeline, nil
+	}
+
+	pipeline, err := c.Pipeline.GetPipeline(project, pid)
+	if err != nil {
+		return nil, err
+	}
+	c.putPipeline(project, pipeline)
+	return pipeline, nil
+}
+
+func (c *pipelineClient) CreatePipeline(project string, opt *gitlab.CreatePipelineOptions) (*gitlab.Pipeline, error) {
+	if err := c.refuseOffline("run pipeline"); err != nil {
+		return nil, err
+	}
+	pipeline, err := c.Pipeline.CreatePipeline(project, opt)
+	if err != nil {
+		return nil, err
+	}
+	c.putPipeline(project, pipeline)
+	return pipeline, nil
+}
+
+func (c *pipelineClient) RetryPipeline(project string, pid int) (*gitlab.Pipeline, error) {
+	if err := c.refuseOffline("retry pipeline"); err != nil {
+		return nil, err
+	}
+	pipeline, err := c.Pipeline.RetryPipeline(project, pid)
+	if err != nil {
+		return nil, err
+	}
+	c.putPipeline(project, pipeline)
+	return pipeline, nil
+}
+
+func (c *pipelineClient) CancelPipeline(project string, pid int) (*gitlab.Pipeline, error) {
+	if err := c.refuseOffline("cancel pipeline"); err != nil {
+		return nil, err
+	}
+	pipeline, err := c.Pipeline.CancelPipeline(project, pid)
+	if err != nil {
+		return nil, err
+	}
+	c.putPipeline(project, pipeline)
+	return pipeline, nil
+}
+
+func (c *pipelineClient) putPipeline(project string, pipeline *gitlab.Pipeline) {
+	var cache pipelines
+	c.put(project, pipelinesFile, &cache, func() {
+		cache.init()
+		cache.Pipelines[pipeline.ID] = pipeline
+	})
+}
+
+// filterPipelines returns the pipelines matching the list options, ordered by
+// the id.
+func filterPipelines(all map[int]*gitlab.Pipeline, opt *gitlab.ListProjectPipelinesOptions, limit int) ([]*gitlab.Pipeline, error) {
+	scope := stringValue(opt.Scope)
+	switch scope {
+	case "", "running", "pending", "finished":
+	default:
+		return nil, fmt.Errorf("Cannot filter by the scope %s offline", scope)
+	}
+
+	var matched []*gitlab.Pipeline
+	for _, pipeline := range all {
+		if opt.Status != nil && string(*opt.Status) != pipeline.Status {
+			continue
+		}
+		if ref := stringValue(opt.Ref); ref != "" && ref != pipeline.Ref {
+			continue
+		}
+		if sha := stringValue(opt.SHA); sha != "" && sha != pipeline.Sha {
+			continue
+		}
+		if !matchPipelineScope(scope, pipeline.Status) {
+			continue
+		}
+		matched = append(matched, pipeline)
+	}
+
+	asc := stringValue(opt.Sort) == "asc"
+	sort.Slice(matched, func(i, j int) bool {
+		if asc {
+			return matched[i].ID < matched[j].ID
+		}
+		return matched[i].ID > matched[j].ID
+	})
+	if limit > 0 && len(matched) > limit {
+		matched = matched[:limit]
+	}
+	return matched, nil
+}
+
+func matchPipelineScope(scope, status string) bool {
+	switch scope {
+	case "running", "pending":
+		return status == scope
+	case "finished":
+		switch status {
+		case "success", "failed", "canceled", "skipped":
+			return true
+		}
+		return false
+	}
+	return true
+}
+
+func containsState(states []gitlab.BuildStateValue, status string) bool {
+	for _, state := range states {
+		if string(state) == status {
+			return true
+		}
+	}
+	return false
+}
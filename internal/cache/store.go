@@ -0,0 +1,219 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+const (
+	issuesFile        = "issues.json"
+	mergeRequestsFile = "merge_requests.json"
+	pipelinesFile     = "pipelines.json"
+)
+
+const (
+	// lockWait is how long to wait for the lock of a file
+	lockWait = 10 * time.Second
+	// staleLock is the age of a lock left by a process which crashed
+	staleLock = time.Minute
+)
+
+type issues struct {
+	SyncedAt *time.Time             `json:"synced_at,omitempty"`
+	Issues   map[int]*gitlab.Issue  `json:"issues"`
+	Notes    map[int][]*gitlab.Note `json:"notes"`
+}
+
+func (c *issues) init() {
+	if c.Issues == nil {
+		c.Issues = map[int]*gitlab.Issue{}
+	}
+	if c.Notes == nil {
+		c.Notes = map[int][]*gitlab.Note{}
+	}
+}
+
+type mergeRequests struct {
+	SyncedAt      *time.Time                            `json:"synced_at,omitempty"`
+	MergeRequests map[int]*gitlab.MergeRequest          `json:"merge_requests"`
+	Approvals     map[int]*gitlab.MergeRequestApprovals `json:"approvals"`
+	Notes         map[int][]*gitlab.Note                `json:"notes"`
+}
+
+func (c *mergeRequests) init() {
+	if c.MergeRequests == nil {
+		c.MergeRequests = map[int]*gitlab.MergeRequest{}
+	}
+	if c.Approvals == nil {
+		c.Approvals = map[int]*gitlab.MergeRequestApprovals{}
+	}
+	if c.Notes == nil {
+		c.Notes = map[int][]*gitlab.Note{}
+	}
+}
+
+type pipelines struct {
+	SyncedAt  *time.Time               `json:"synced_at,omitempty"`
+	Pipelines map[int]*gitlab.Pipeline `json:"pipelines"`
+	Jobs      map[int][]*gitlab.Job    `json:"jobs"`
+}
+
+func (c *pipelines) init() {
+	if c.Pipelines == nil {
+		c.Pipelines = map[int]*gitlab.Pipeline{}
+	}
+	if c.Jobs == nil {
+		c.Jobs = map[int][]*gitlab.Job{}
+	}
+}
+
+// putList keeps the pipelines of a list. The jobs of the pipelines whose
+// status changed are dropped, they changed too.
+func (c *pipelines) putList(list gitlab.PipelineList) {
+	c.init()
+	for _, p := range list {
+		pipeline, ok := c.Pipelines[p.ID]
+		if !ok {
+			pipeline = &gitlab.Pipeline{ID: p.ID}
+			c.Pipelines[p.ID] = pipeline
+		}
+		if pipeline.Status != p.Status {
+			delete(c.Jobs, p.ID)
+		}
+		pipeline.Status = p.Status
+		pipeline.Ref = p.Ref
+		pipeline.Sha = p.Sha
+	}
+}
+
+// Store keeps the issues, merge requests and pipelines read from GitLab on
+// disk. Every project of every profile has its own directory, e.g.
+// "<dir>/gitlab.com/group%2Fproject/issues.json".
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Dir returns the directory of the store.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+func (s *Store) profileDir(profile string) string {
+	return filepath.Join(s.dir, url.PathEscape(profile))
+}
+
+func (s *Store) projectDir(profile, project string) string {
+	return filepath.Join(s.profileDir(profile), url.PathEscape(project))
+}
+
+// Projects returns the projects of a profile kept in the store.
+func (s *Store) Projects(profile string) ([]string, error) {
+	infos, err := ioutil.ReadDir(s.profileDir(profile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed read cache. %s", err)
+	}
+
+	var projects []string
+	for _, info := range infos {
+		if !info.IsDir() {
+			continue
+		}
+		project, err := url.PathUnescape(info.Name())
+		if err != nil {
+			continue
+		}
+		projects = append(projects, project)
+	}
+	sort.Strings(projects)
+	return projects, nil
+}
+
+// load reads a file of a project into v. v is left as it is when the file
+// does not exist yet.
+func (s *Store) load(profile, project, name string, v interface{}) error {
+	b, err := ioutil.ReadFile(filepath.Join(s.projectDir(profile, project), name))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("Failed read cache. %s", err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("Failed read cache. %s", err)
+	}
+	return nil
+}
+
+// update reads a file of a project into v, lets f change v and writes v back.
+// The file is locked meanwhile, not to lose the changes of a sync running in
+// the background.
+func (s *Store) update(profile, project, name string, v interface{}, f func()) error {
+	dir := s.projectDir(profile, project)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("Failed write cache. %s", err)
+	}
+	path := filepath.Join(dir, name)
+
+	unlock, err := lock(path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := s.load(profile, project, name, v); err != nil {
+		return err
+	}
+	f()
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("Failed write cache. %s", err)
+	}
+	// The file is replaced at once, the readers never see a half written file
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return fmt.Errorf("Failed write cache. %s", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("Failed write cache. %s", err)
+	}
+	return nil
+}
+
+// lock creates the lock file, and returns the function removing it.
+func lock(path string) (func(), error) {
+	deadline := time.Now().Add(lockWait)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("Failed lock cache. %s", err)
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleLock {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("Failed lock cache. %s is locked", path)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
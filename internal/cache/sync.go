@@ -0,0 +1,231 @@
+package cache
+
+import (
+	"sort"
+	"time"
+
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+const (
+	// syncMargin is subtracted from the time of the last sync, not to miss
+	// the changes made while it ran or on a server with a clock behind
+	syncMargin = time.Minute
+	// syncPipelines is the number of the latest pipelines kept in sync
+	syncPipelines = 100
+	// syncPipelineJobs is the number of the latest pipelines whose jobs are
+	// kept in sync
+	syncPipelineJobs = 20
+)
+
+// Syncer reads the issues, merge requests and pipelines of projects changed
+// since the last sync into the store.
+type Syncer struct {
+	factory api.APIClientFactory
+	store   *Store
+	profile string
+	now     func() time.Time
+}
+
+func NewSyncer(factory api.APIClientFactory, store *Store, profile string) *Syncer {
+	return &Syncer{
+		factory: factory,
+		store:   store,
+		profile: profile,
+		now:     time.Now,
+	}
+}
+
+// SyncResult is the number of the items read by a sync.
+type SyncResult struct {
+	Issues        int
+	MergeRequests int
+	Pipelines     int
+}
+
+// Sync reads the items of a project changed since the last sync. The first
+// sync reads every issue and merge request, without the comments.
+func (s *Syncer) Sync(project string) (*SyncResult, error) {
+	result := &SyncResult{}
+	var err error
+	if result.Issues, err = s.syncIssues(project); err != nil {
+		return nil, err
+	}
+	if result.MergeRequests, err = s.syncMergeRequests(project); err != nil {
+		return nil, err
+	}
+	if result.Pipelines, err = s.syncPipelines(project); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *Syncer) syncIssues(project string) (int, error) {
+	var cache issues
+	if err := s.store.load(s.profile, project, issuesFile, &cache); err != nil {
+		return 0, err
+	}
+	started := s.now()
+
+	opt := &gitlab.ListProjectIssuesOptions{
+		OrderBy:     gitlab.String("updated_at"),
+		Sort:        gitlab.String("desc"),
+		ListOptions: gitlab.ListOptions{Page: 1, PerPage: 100},
+	}
+	if cache.SyncedAt != nil {
+		after := cache.SyncedAt.Add(-syncMargin)
+		opt.UpdatedAfter = &after
+	}
+	var read []*gitlab.Issue
+	err := s.factory.GetIssueClient().GetProjectIssues(opt, project, 0, func(page []*gitlab.Issue) error {
+		read = append(read, page...)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// The comments of the changed issues are read again only when they were
+	// read once, the first sync would read the comments of every issue
+	notes := map[int][]*gitlab.Note{}
+	for _, issue := range read {
+		if _, ok := cache.Notes[issue.IID]; !ok {
+			continue
+		}
+		n, err := s.factory.GetNoteClient().GetIssueNotes(project, issue.IID, &gitlab.ListIssueNotesOptions{
+			ListOptions: gitlab.ListOptions{Page: 1},
+		})
+		if err != nil {
+			return 0, err
+		}
+		notes[issue.IID] = n
+	}
+
+	err = s.store.update(s.profile, project, issuesFile, &cache, func() {
+		cache.init()
+		for _, issue := range read {
+			cache.Issues[issue.IID] = issue
+			delete(cache.Notes, issue.IID)
+		}
+		for iid, n := range notes {
+			cache.Notes[iid] = n
+		}
+		cache.SyncedAt = &started
+	})
+	return len(read), err
+}
+
+func (s *Syncer) syncMergeRequests(project string) (int, error) {
+	var cache mergeRequests
+	if err := s.store.load(s.profile, project, mergeRequestsFile, &cache); err != nil {
+		return 0, err
+	}
+	started := s.now()
+
+	opt := &gitlab.ListProjectMergeRequestsOptions{
+		OrderBy:     gitlab.String("updated_at"),
+		Sort:        gitlab.String("desc"),
+		ListOptions: gitlab.ListOptions{Page: 1, PerPage: 100},
+	}
+	if cache.SyncedAt != nil {
+		after := cache.SyncedAt.Add(-syncMargin)
+		opt.UpdatedAfter = &after
+	}
+	var read []*gitlab.MergeRequest
+	err := s.factory.GetMergeRequestClient().GetProjectMargeRequest(opt, project, 0, func(page []*gitlab.MergeRequest) error {
+		read = append(read, page...)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Same as the issues, only the comments read once are read again
+	notes := map[int][]*gitlab.Note{}
+	for _, mergeRequest := range read {
+		if _, ok := cache.Notes[mergeRequest.IID]; !ok {
+			continue
+		}
+		n, err := s.factory.GetNoteClient().GetMergeRequestNotes(project, mergeRequest.IID, &gitlab.ListMergeRequestNotesOptions{Page: 1})
+		if err != nil {
+			return 0, err
+		}
+		notes[mergeRequest.IID] = n
+	}
+
+	err = s.store.update(s.profile, project, mergeRequestsFile, &cache, func() {
+		cache.init()
+		for _, mergeRequest := range read {
+			cache.MergeRequests[mergeRequest.IID] = mergeRequest
+			// The approvals change with the merge request, they are read
+			// again by the detail
+			delete(cache.Approvals, mergeRequest.IID)
+			delete(cache.Notes, mergeRequest.IID)
+		}
+		for iid, n := range notes {
+			cache.Notes[iid] = n
+		}
+		cache.SyncedAt = &started
+	})
+	return len(read), err
+}
+
+// syncPipelines reads the latest pipelines updated since the last sync, and
+// the jobs of the latest ones whose status changed.
+func (s *Syncer) syncPipelines(project string) (int, error) {
+	var cache pipelines
+	if err := s.store.load(s.profile, project, pipelinesFile, &cache); err != nil {
+		return 0, err
+	}
+	started := s.now()
+
+	client := s.factory.GetPipelineClient()
+	opt := &gitlab.ListProjectPipelinesOptions{
+		OrderBy:     gitlab.String("id"),
+		Sort:        gitlab.String("desc"),
+		ListOptions: gitlab.ListOptions{Page: 1, PerPage: syncPipelines},
+	}
+	var options []gitlab.OptionFunc
+	if cache.SyncedAt != nil {
+		options = append(options, api.WithUpdatedAfter(cache.SyncedAt.Add(-syncMargin)))
+	}
+	var read gitlab.PipelineList
+	err := client.ProjectPipelines(project, opt, syncPipelines, func(page gitlab.PipelineList) error {
+		read = append(read, page...)
+		return nil
+	}, options...)
+	if err != nil {
+		return 0, err
+	}
+	sort.Slice(read, func(i, j int) bool { return read[i].ID > read[j].ID })
+
+	jobs := map[int][]*gitlab.Job{}
+	for i, p := range read {
+		if i >= syncPipelineJobs {
+			break
+		}
+		cached, ok := cache.Pipelines[p.ID]
+		if _, hasJobs := cache.Jobs[p.ID]; hasJobs && ok && cached.Status == p.Status {
+			continue
+		}
+		var pipelineJobs []*gitlab.Job
+		err := client.ProjectPipelineJobs(project, &gitlab.ListJobsOptions{}, p.ID, 0, func(page []*gitlab.Job) error {
+			pipelineJobs = append(pipelineJobs, page...)
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+		jobs[p.ID] = pipelineJobs
+	}
+
+	err = s.store.update(s.profile, project, pipelinesFile, &cache, func() {
+		cache.putList(read)
+		for id, j := range jobs {
+			cache.Jobs[id] = j
+		}
+		cache.SyncedAt = &started
+	})
+	return len(read), err
+}
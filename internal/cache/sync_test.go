@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lighttiger2505/lab/internal/api"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+func TestSyncer_Sync(t *testing.T) {
+	store := newTestStore(t)
+	defer os.RemoveAll(store.Dir())
+
+	var updatedAfter []*time.Time
+	notesRead := 0
+	jobsRead := 0
+	factory := newTestFactory()
+	factory.MockGetIssueClient = func() api.Issue {
+		return &api.MockLabIssueClient{
+			MockGetProjectIssues: func(opt *gitlab.ListProjectIssuesOptions, repositoryName string) ([]*gitlab.Issue, error) {
+				updatedAfter = append(updatedAfter, opt.UpdatedAfter)
+				return testIssues, nil
+			},
+		}
+	}
+	factory.MockGetMergeRequestClient = func() api.MergeRequest {
+		return &api.MockLabMergeRequestClient{
+			MockGetProjectMargeRequest: func(opt *gitlab.ListProjectMergeRequestsOptions, repositoryName string) ([]*gitlab.MergeRequest, error) {
+				return []*gitlab.MergeRequest{{IID: 1}}, nil
+			},
+		}
+	}
+	factory.MockGetNoteClient = func() api.Note {
+		return &api.MockNoteClient{
+			MockGetIssueNotes: func(repositoryName string, iid int, opt *gitlab.ListIssueNotesOptions) ([]*gitlab.Note, error) {
+				notesRead++
+				return []*gitlab.Note{{ID: 1}}, nil
+			},
+		}
+	}
+	pipelines := factory.MockGetPipelineClient().(*api.MockPipelineClient)
+	pipelines.MockProjectPipelineJobs = func(repositoryName string, opt *gitlab.ListJobsOptions, pid int) ([]*gitlab.Job, error) {
+		jobsRead++
+		return []*gitlab.Job{{ID: pid * 10}}, nil
+	}
+	pipelineClient := &updatedAfterPipelineClient{MockPipelineClient: pipelines}
+	factory.MockGetPipelineClient = func() api.Pipeline { return pipelineClient }
+
+	syncer := NewSyncer(factory, store, "gitlab.com")
+	syncer.now = func() time.Time { return *testTime(10) }
+	result, err := syncer.Sync("group/project")
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if *result != (SyncResult{Issues: 2, MergeRequests: 1, Pipelines: 3}) {
+		t.Errorf("Sync() = %+v", *result)
+	}
+	if updatedAfter[0] != nil {
+		t.Errorf("Sync() updated_after = %v, want nil at first", updatedAfter[0])
+	}
+	if pipelineClient.updatedAfter[0] != "" {
+		t.Errorf("Sync() pipelines updated_after = %v, want none at first", pipelineClient.updatedAfter[0])
+	}
+	if notesRead != 0 {
+		t.Errorf("Sync() read the comments %d times at first, want 0", notesRead)
+	}
+	if jobsRead != 3 {
+		t.Errorf("Sync() read the jobs %d times, want 3", jobsRead)
+	}
+
+	// The comments read once are read again with the issue
+	online := NewClientFactory(factory, store, "gitlab.com", false)
+	if _, err := online.GetNoteClient().GetIssueNotes("group/project", 1, &gitlab.ListIssueNotesOptions{}); err != nil {
+		t.Fatalf("GetIssueNotes() error = %v", err)
+	}
+	notesRead = 0
+	jobsRead = 0
+
+	syncer.now = func() time.Time { return *testTime(11) }
+	if _, err := syncer.Sync("group/project"); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if want := testTime(10).Add(-syncMargin); updatedAfter[1] == nil || !updatedAfter[1].Equal(want) {
+		t.Errorf("Sync() updated_after = %v, want %v", updatedAfter[1], want)
+	}
+	if want := testTime(10).Add(-syncMargin).Format(time.RFC3339); pipelineClient.updatedAfter[1] != want {
+		t.Errorf("Sync() pipelines updated_after = %v, want %v", pipelineClient.updatedAfter[1], want)
+	}
+	if notesRead != 1 {
+		t.Errorf("Sync() read the comments %d times, want 1", notesRead)
+	}
+	if jobsRead != 0 {
+		t.Errorf("Sync() read the jobs %d times of the unchanged pipelines, want 0", jobsRead)
+	}
+
+	offline := NewClientFactory(factory, store, "gitlab.com", true)
+	notes, err := offline.GetNoteClient().GetIssueNotes("group/project", 1, &gitlab.ListIssueNotesOptions{})
+	if err != nil {
+		t.Fatalf("GetIssueNotes() error = %v", err)
+	}
+	if len(notes) != 1 {
+		t.Errorf("GetIssueNotes() = %d notes, want 1", len(notes))
+	}
+}
+
+// updatedAfterPipelineClient keeps the updated_after of the pipelines read,
+// which is an option of the request.
+type updatedAfterPipelineClient struct {
+	*api.MockPipelineClient
+	updatedAfter []string
+}
+
+func (c *updatedAfterPipelineClient) ProjectPipelines(repositoryName string, opt *gitlab.ListProjectPipelinesOptions, limit int, f func(gitlab.PipelineList) error, options ...gitlab.OptionFunc) error {
+	req := httptest.NewRequest("GET", "/projects/1/pipelines", nil)
+	for _, option := range options {
+		if err := option(req); err != nil {
+			return err
+		}
+	}
+	c.updatedAfter = append(c.updatedAfter, req.URL.Query().Get("updated_after"))
+	return c.MockPipelineClient.ProjectPipelines(repositoryName, opt, limit, f)
+}
@@ -0,0 +1,429 @@
+// Package ciconfig reads a .gitlab-ci.yml without GitLab, to validate it and
+// to simulate the pipelines it creates.
+package ciconfig
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// maxIncludes is the limit of the included files, as in GitLab.
+const maxIncludes = 150
+
+// maxExtendsDepth is the limit of the nested extends, as in GitLab.
+const maxExtendsDepth = 11
+
+// reservedKeys are the top level keys which are not jobs.
+var reservedKeys = []string{
+	"default", "include", "stages", "variables", "workflow",
+	"image", "services", "cache", "before_script", "after_script", "types",
+}
+
+// defaultStages are the stages of the configs without stages.
+var defaultStages = []string{"build", "test", "deploy"}
+
+// defaultStage is the stage of the jobs without stage.
+const defaultStage = "test"
+
+// Config is a CI config with the local includes, the anchors and the extends
+// resolved.
+type Config struct {
+	// Keys are the top level keys in the order they are defined
+	Keys   []string
+	Values map[string]interface{}
+	// Warnings are the problems which do not make the config invalid, e.g.
+	// the includes which can not be read offline
+	Warnings []string
+}
+
+// Load reads the CI config at path. The local includes are read from the
+// directory root.
+func Load(root, path string) (*Config, error) {
+	return load(path, &loader{
+		root:   root,
+		read:   ioutil.ReadFile,
+		where:  "offline",
+		loaded: map[string]bool{},
+	})
+}
+
+// LoadFunc reads the CI config at path with read, e.g. from a commit of the
+// repository. The local includes are read by their path from the root of the
+// repository.
+func LoadFunc(path string, read func(path string) ([]byte, error)) (*Config, error) {
+	return load(path, &loader{
+		read:   read,
+		where:  "from the repository",
+		loaded: map[string]bool{},
+	})
+}
+
+func load(path string, l *loader) (*Config, error) {
+	c := &Config{Values: map[string]interface{}{}}
+	l.config = c
+	if err := l.load(path, ""); err != nil {
+		return nil, err
+	}
+	delete(c.Values, "include")
+	c.Keys = removeKey(c.Keys, "include")
+
+	if err := c.resolveExtends(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Jobs returns the names of the jobs in the order they are defined.
+func (c *Config) Jobs() []string {
+	var jobs []string
+	for _, key := range c.Keys {
+		if isJob(key, c.Values[key]) {
+			jobs = append(jobs, key)
+		}
+	}
+	return jobs
+}
+
+// Job returns the keywords of a job.
+func (c *Config) Job(name string) map[string]interface{} {
+	job, _ := c.Values[name].(map[string]interface{})
+	return job
+}
+
+// Stages returns the stages of the pipeline, with .pre and .post.
+func (c *Config) Stages() []string {
+	stages := defaultStages
+	if list, ok := c.Values["stages"].([]interface{}); ok {
+		stages = nil
+		for _, stage := range list {
+			stages = append(stages, fmt.Sprint(stage))
+		}
+	}
+	return append(append([]string{".pre"}, stages...), ".post")
+}
+
+// Needs returns the jobs in the needs of a job, without the needs of the
+// other pipelines. ok is false when the job has no needs keyword, and true
+// with no jobs for "needs: []".
+func (c *Config) Needs(name string) (jobs []string, ok bool) {
+	v, ok := c.Job(name)["needs"]
+	if !ok {
+		return nil, false
+	}
+	jobs = []string{}
+	for _, need := range needs(v) {
+		jobs = append(jobs, need.job)
+	}
+	return jobs, true
+}
+
+// Variables returns the global variables.
+func (c *Config) Variables() map[string]string {
+	return variables(c.Values["variables"])
+}
+
+// YAML returns the config in YAML, with the keys in the order they are
+// defined.
+func (c *Config) YAML() (string, error) {
+	var m yaml.MapSlice
+	for _, key := range c.Keys {
+		m = append(m, yaml.MapItem{Key: key, Value: c.Values[key]})
+	}
+	b, err := yaml.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func isJob(key string, v interface{}) bool {
+	if strings.HasPrefix(key, ".") {
+		return false
+	}
+	for _, reserved := range reservedKeys {
+		if key == reserved {
+			return false
+		}
+	}
+	_, ok := v.(map[string]interface{})
+	return ok
+}
+
+// variables returns the values of the variables, which are given by the
+// value or by a hash with the value.
+func variables(v interface{}) map[string]string {
+	m, _ := v.(map[string]interface{})
+	vars := map[string]string{}
+	for key, value := range m {
+		if h, ok := value.(map[string]interface{}); ok {
+			value = h["value"]
+		}
+		if value != nil {
+			vars[key] = fmt.Sprint(value)
+		}
+	}
+	return vars
+}
+
+type loader struct {
+	root string
+	read func(path string) ([]byte, error)
+	// where tells where the local includes are read from, for the warnings
+	// of the other includes
+	where  string
+	config *Config
+	loaded map[string]bool
+}
+
+// load merges the file into the config after its includes, so that the file
+// overrides the keys of the included files.
+func (l *loader) load(path, includedBy string) error {
+	if len(l.loaded) >= maxIncludes {
+		return fmt.Errorf("Maximum of %d includes are allowed", maxIncludes)
+	}
+	l.loaded[path] = true
+
+	b, err := l.read(path)
+	if err != nil {
+		if includedBy != "" {
+			return fmt.Errorf("Local file `%s` does not exist! Included by %s", path, includedBy)
+		}
+		return err
+	}
+	keys, values, err := parse(b)
+	if err != nil {
+		if includedBy != "" {
+			return fmt.Errorf("Local file `%s` does not have valid YAML syntax! %s", path, err.Error())
+		}
+		return err
+	}
+
+	for _, include := range includes(values["include"]) {
+		local, ok := include["local"]
+		if !ok {
+			l.config.Warnings = append(l.config.Warnings, fmt.Sprintf("include %s can not be read %s", describeInclude(include), l.where))
+			continue
+		}
+		includePath := strings.TrimPrefix(local, "/")
+		if l.root != "" {
+			includePath = filepath.Join(l.root, includePath)
+		}
+		if l.loaded[includePath] {
+			continue
+		}
+		if err := l.load(includePath, path); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range keys {
+		if _, ok := l.config.Values[key]; !ok {
+			l.config.Keys = append(l.config.Keys, key)
+		}
+	}
+	l.config.Values = deepMerge(l.config.Values, values)
+	return nil
+}
+
+// parse returns the top level keys in the order they are defined, and the
+// config.
+func parse(b []byte) ([]string, map[string]interface{}, error) {
+	// yaml.MapSlice keeps the order, but does not resolve the merge keys
+	var order yaml.MapSlice
+	if err := yaml.Unmarshal(b, &order); err != nil {
+		return nil, nil, err
+	}
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(b, &raw); err != nil {
+		return nil, nil, err
+	}
+
+	var keys []string
+	for _, item := range order {
+		key := fmt.Sprint(item.Key)
+		if key != "<<" {
+			keys = append(keys, key)
+		}
+	}
+	values, _ := normalize(raw).(map[string]interface{})
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	return keys, values, nil
+}
+
+// normalize replaces the maps decoded by yaml with maps of string keys.
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for key, value := range v {
+			m[fmt.Sprint(key)] = normalize(value)
+		}
+		return m
+	case map[string]interface{}:
+		m := map[string]interface{}{}
+		for key, value := range v {
+			m[key] = normalize(value)
+		}
+		return m
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, value := range v {
+			list[i] = normalize(value)
+		}
+		return list
+	}
+	return v
+}
+
+// includes returns the includes as hashes. A string is a local file, or a
+// remote file when it is a URL.
+func includes(v interface{}) []map[string]string {
+	var list []interface{}
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		list = v
+	default:
+		list = []interface{}{v}
+	}
+
+	var result []map[string]string
+	for _, item := range list {
+		include := map[string]string{}
+		switch item := item.(type) {
+		case string:
+			if strings.HasPrefix(item, "http://") || strings.HasPrefix(item, "https://") {
+				include["remote"] = item
+			} else {
+				include["local"] = item
+			}
+		case map[string]interface{}:
+			for key, value := range item {
+				include[key] = fmt.Sprint(value)
+			}
+		}
+		result = append(result, include)
+	}
+	return result
+}
+
+func describeInclude(include map[string]string) string {
+	var keys []string
+	for key := range include {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, key := range keys {
+		parts = append(parts, key+": "+include[key])
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// deepMerge returns dst with the keys of src. The hashes are merged, and the
+// other values of src replace the values of dst.
+func deepMerge(dst, src map[string]interface{}) map[string]interface{} {
+	merged := map[string]interface{}{}
+	for key, value := range dst {
+		merged[key] = value
+	}
+	for key, value := range src {
+		srcMap, srcOK := value.(map[string]interface{})
+		dstMap, dstOK := merged[key].(map[string]interface{})
+		if srcOK && dstOK {
+			merged[key] = deepMerge(dstMap, srcMap)
+		} else {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// resolveExtends merges the keys the jobs and the hidden keys extend into
+// them.
+func (c *Config) resolveExtends() error {
+	resolved := map[string]map[string]interface{}{}
+	var resolve func(key string, depth int, stack []string) (map[string]interface{}, error)
+	resolve = func(key string, depth int, stack []string) (map[string]interface{}, error) {
+		if m, ok := resolved[key]; ok {
+			return m, nil
+		}
+		for _, k := range stack {
+			if k == key {
+				return nil, fmt.Errorf("jobs:%s extends circular dependency detected: %s", stack[0], strings.Join(append(stack, key), " -> "))
+			}
+		}
+		if depth > maxExtendsDepth {
+			return nil, fmt.Errorf("jobs:%s extends nesting too deep", stack[0])
+		}
+		m, _ := c.Values[key].(map[string]interface{})
+
+		parents := stringList(m["extends"])
+		if len(parents) == 0 {
+			resolved[key] = m
+			return m, nil
+		}
+		merged := map[string]interface{}{}
+		for _, parent := range parents {
+			if _, ok := c.Values[parent].(map[string]interface{}); !ok {
+				return nil, fmt.Errorf("jobs:%s extends unknown key %s", key, parent)
+			}
+			p, err := resolve(parent, depth+1, append(stack, key))
+			if err != nil {
+				return nil, err
+			}
+			merged = deepMerge(merged, p)
+		}
+		merged = deepMerge(merged, m)
+		delete(merged, "extends")
+		resolved[key] = merged
+		return merged, nil
+	}
+
+	for _, key := range c.Keys {
+		if _, ok := c.Values[key].(map[string]interface{}); !ok || key == "default" || key == "variables" || key == "workflow" {
+			continue
+		}
+		m, err := resolve(key, 0, nil)
+		if err != nil {
+			return err
+		}
+		c.Values[key] = m
+	}
+	return nil
+}
+
+// stringList returns a string or a list of strings as a list.
+func stringList(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var list []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
+
+func removeKey(keys []string, key string) []string {
+	var removed []string
+	for _, k := range keys {
+		if k != key {
+			removed = append(removed, k)
+		}
+	}
+	return removed
+}
@@ -0,0 +1,145 @@
+package ciconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// writeFiles writes the files into a temporary directory, and returns the
+// directory.
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "lab-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoad(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		".gitlab-ci.yml": `include:
+  - local: /ci/base.yml
+  - template: Security/SAST.gitlab-ci.yml
+variables:
+  GO_VERSION: "1.10"
+.cache: &cache
+  cache:
+    paths: [vendor]
+build:
+  <<: *cache
+  extends: .go
+  script: go build
+test:
+  extends: [.go, .tags]
+  variables:
+    CGO_ENABLED: "0"
+`,
+		"ci/base.yml": `include: ci/tags.yml
+variables:
+  GO_VERSION: "1.9"
+  GOPATH: /go
+.go:
+  image: golang
+  variables:
+    GOFLAGS: -v
+  script: go test
+`,
+		"ci/tags.yml": `.tags:
+  tags: [docker]
+`,
+	})
+	defer os.RemoveAll(dir)
+
+	c, err := Load(dir, filepath.Join(dir, ".gitlab-ci.yml"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if diff := cmp.Diff(c.Jobs(), []string{"build", "test"}); diff != "" {
+		t.Errorf("Jobs() (-got +want)\n%s", diff)
+	}
+	if diff := cmp.Diff(c.Variables(), map[string]string{"GO_VERSION": "1.10", "GOPATH": "/go"}); diff != "" {
+		t.Errorf("Variables() (-got +want)\n%s", diff)
+	}
+	wantBuild := map[string]interface{}{
+		"image":     "golang",
+		"variables": map[string]interface{}{"GOFLAGS": "-v"},
+		"script":    "go build",
+		"cache":     map[string]interface{}{"paths": []interface{}{"vendor"}},
+	}
+	if diff := cmp.Diff(c.Job("build"), wantBuild); diff != "" {
+		t.Errorf("Job(build) (-got +want)\n%s", diff)
+	}
+	wantTest := map[string]interface{}{
+		"image":     "golang",
+		"variables": map[string]interface{}{"GOFLAGS": "-v", "CGO_ENABLED": "0"},
+		"script":    "go test",
+		"tags":      []interface{}{"docker"},
+	}
+	if diff := cmp.Diff(c.Job("test"), wantTest); diff != "" {
+		t.Errorf("Job(test) (-got +want)\n%s", diff)
+	}
+	wantWarnings := []string{"include {template: Security/SAST.gitlab-ci.yml} can not be read offline"}
+	if diff := cmp.Diff(c.Warnings, wantWarnings); diff != "" {
+		t.Errorf("Warnings (-got +want)\n%s", diff)
+	}
+}
+
+func TestLoad_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name:    "syntax",
+			files:   map[string]string{".gitlab-ci.yml": "build: [\n"},
+			wantErr: "yaml: line 1: did not find expected node content",
+		},
+		{
+			name:    "missing include",
+			files:   map[string]string{".gitlab-ci.yml": "include: /ci/missing.yml\n"},
+			wantErr: "Local file `ci/missing.yml` does not exist! Included by .gitlab-ci.yml",
+		},
+		{
+			name:    "circular extends",
+			files:   map[string]string{".gitlab-ci.yml": ".a:\n  extends: .b\n.b:\n  extends: .a\n"},
+			wantErr: "jobs:.a extends circular dependency detected: .a -> .b -> .a",
+		},
+		{
+			name:    "unknown extends",
+			files:   map[string]string{".gitlab-ci.yml": "build:\n  extends: .go\n"},
+			wantErr: "jobs:build extends unknown key .go",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeFiles(t, tt.files)
+			defer os.RemoveAll(dir)
+
+			_, err := Load(dir, filepath.Join(dir, ".gitlab-ci.yml"))
+			if err == nil {
+				t.Fatalf("Load() want error %q", tt.wantErr)
+			}
+			// The paths are relative to the directory in the messages
+			got := strings.Replace(err.Error(), dir+"/", "", -1)
+			if got != tt.wantErr {
+				t.Errorf("Load() error = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package ciconfig
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// value is an operand of an expression. A variable which is not defined is
+// null, and a regexp literal has re.
+type value struct {
+	str  string
+	null bool
+	re   *regexp.Regexp
+}
+
+func (v value) truthy() bool {
+	return !v.null && v.str != ""
+}
+
+// evalExpression evaluates an expression of rules:if and only:variables,
+// e.g. `$CI_COMMIT_BRANCH == "main" && $CI_COMMIT_TAG =~ /^v/`.
+func evalExpression(expr string, vars map[string]string) (bool, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return false, err
+	}
+	p := &exprParser{tokens: tokens, vars: vars}
+	result, err := p.or()
+	if err != nil {
+		return false, err
+	}
+	if p.pos < len(p.tokens) {
+		return false, fmt.Errorf("invalid expression syntax near %s", p.tokens[p.pos].text)
+	}
+	return result.truthy(), nil
+}
+
+type tokenKind int
+
+const (
+	tokenVariable tokenKind = iota
+	tokenString
+	tokenRegexp
+	tokenNull
+	tokenOperator
+	tokenOpen
+	tokenClose
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+var (
+	variablePattern = regexp.MustCompile(`^\$(\{[A-Za-z_][A-Za-z0-9_]*\}|[A-Za-z_][A-Za-z0-9_]*)`)
+	regexpPattern   = regexp.MustCompile(`^/((?:\\.|[^/\\])*)/([a-z]*)`)
+	operators       = []string{"==", "!=", "=~", "!~", "&&", "||"}
+)
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	rest := strings.TrimSpace(expr)
+	for rest != "" {
+		// A slash starts a regexp only where an operand is expected
+		operand := len(tokens) == 0 || tokens[len(tokens)-1].kind == tokenOperator || tokens[len(tokens)-1].kind == tokenOpen
+
+		switch {
+		case rest[0] == '(':
+			tokens = append(tokens, token{kind: tokenOpen, text: "("})
+			rest = rest[1:]
+		case rest[0] == ')':
+			tokens = append(tokens, token{kind: tokenClose, text: ")"})
+			rest = rest[1:]
+		case rest[0] == '"' || rest[0] == '\'':
+			end := strings.IndexByte(rest[1:], rest[0])
+			if end < 0 {
+				return nil, fmt.Errorf("invalid expression syntax, unterminated string %s", rest)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: rest[1 : end+1]})
+			rest = rest[end+2:]
+		case rest[0] == '$':
+			match := variablePattern.FindString(rest)
+			if match == "" {
+				return nil, fmt.Errorf("invalid expression syntax near %s", rest)
+			}
+			tokens = append(tokens, token{kind: tokenVariable, text: strings.Trim(match[1:], "{}")})
+			rest = rest[len(match):]
+		case rest[0] == '/' && operand:
+			match := regexpPattern.FindString(rest)
+			if match == "" {
+				return nil, fmt.Errorf("invalid expression syntax, unterminated regexp %s", rest)
+			}
+			tokens = append(tokens, token{kind: tokenRegexp, text: match})
+			rest = rest[len(match):]
+		case strings.HasPrefix(rest, "null"):
+			tokens = append(tokens, token{kind: tokenNull, text: "null"})
+			rest = rest[len("null"):]
+		default:
+			found := false
+			for _, op := range operators {
+				if strings.HasPrefix(rest, op) {
+					tokens = append(tokens, token{kind: tokenOperator, text: op})
+					rest = rest[len(op):]
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("invalid expression syntax near %s", rest)
+			}
+		}
+		rest = strings.TrimSpace(rest)
+	}
+	return tokens, nil
+}
+
+// exprParser evaluates the tokens while parsing them. && binds tighter than
+// ||, as in GitLab.
+type exprParser struct {
+	tokens []token
+	pos    int
+	vars   map[string]string
+}
+
+func (p *exprParser) peek(text string) bool {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokenOperator && p.tokens[p.pos].text == text
+}
+
+func (p *exprParser) or() (value, error) {
+	left, err := p.and()
+	if err != nil {
+		return value{}, err
+	}
+	for p.peek("||") {
+		p.pos++
+		right, err := p.and()
+		if err != nil {
+			return value{}, err
+		}
+		left = boolValue(left.truthy() || right.truthy())
+	}
+	return left, nil
+}
+
+func (p *exprParser) and() (value, error) {
+	left, err := p.comparison()
+	if err != nil {
+		return value{}, err
+	}
+	for p.peek("&&") {
+		p.pos++
+		right, err := p.comparison()
+		if err != nil {
+			return value{}, err
+		}
+		left = boolValue(left.truthy() && right.truthy())
+	}
+	return left, nil
+}
+
+func (p *exprParser) comparison() (value, error) {
+	left, err := p.operand()
+	if err != nil {
+		return value{}, err
+	}
+	for _, op := range []string{"==", "!=", "=~", "!~"} {
+		if !p.peek(op) {
+			continue
+		}
+		p.pos++
+		right, err := p.operand()
+		if err != nil {
+			return value{}, err
+		}
+		switch op {
+		case "==":
+			return boolValue(equal(left, right)), nil
+		case "!=":
+			return boolValue(!equal(left, right)), nil
+		}
+		matched, err := match(left, right)
+		if err != nil {
+			return value{}, err
+		}
+		return boolValue(matched == (op == "=~")), nil
+	}
+	return left, nil
+}
+
+func (p *exprParser) operand() (value, error) {
+	if p.pos >= len(p.tokens) {
+		return value{}, fmt.Errorf("invalid expression syntax, missing operand")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	switch t.kind {
+	case tokenOpen:
+		v, err := p.or()
+		if err != nil {
+			return value{}, err
+		}
+		if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != tokenClose {
+			return value{}, fmt.Errorf("invalid expression syntax, missing )")
+		}
+		p.pos++
+		return v, nil
+	case tokenVariable:
+		if v, ok := p.vars[t.text]; ok {
+			return value{str: v}, nil
+		}
+		return value{null: true}, nil
+	case tokenString:
+		return value{str: t.text}, nil
+	case tokenNull:
+		return value{null: true}, nil
+	case tokenRegexp:
+		re, err := compileRegexp(t.text)
+		if err != nil {
+			return value{}, err
+		}
+		return value{re: re}, nil
+	}
+	return value{}, fmt.Errorf("invalid expression syntax near %s", t.text)
+}
+
+func boolValue(b bool) value {
+	if b {
+		return value{str: "true"}
+	}
+	return value{null: true}
+}
+
+func equal(left, right value) bool {
+	if left.null || right.null {
+		return left.null == right.null
+	}
+	return left.str == right.str
+}
+
+// match matches the left operand by the regexp of the right operand, which
+// is a regexp literal or a variable holding one.
+func match(left, right value) (bool, error) {
+	re := right.re
+	if re == nil {
+		if right.null {
+			return false, nil
+		}
+		compiled, err := compileRegexp(right.str)
+		if err != nil {
+			return false, err
+		}
+		re = compiled
+	}
+	if left.null {
+		return false, nil
+	}
+	return re.MatchString(left.str), nil
+}
+
+// compileRegexp compiles a regexp literal, e.g. "/^release-.*$/i".
+func compileRegexp(literal string) (*regexp.Regexp, error) {
+	m := regexpPattern.FindStringSubmatch(literal)
+	if m == nil || len(m[0]) != len(literal) {
+		return nil, fmt.Errorf("invalid regexp %s", literal)
+	}
+	pattern := strings.Replace(m[1], `\/`, "/", -1)
+	if m[2] != "" {
+		pattern = "(?" + m[2] + ")" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regexp %s", literal)
+	}
+	return re, nil
+}
@@ -0,0 +1,42 @@
+package ciconfig
+
+import "testing"
+
+func TestEvalExpression(t *testing.T) {
+	vars := map[string]string{
+		"CI_COMMIT_BRANCH": "release-1.0",
+		"CI_COMMIT_TAG":    "",
+		"PATTERN":          "/^release-/",
+	}
+	tests := []struct {
+		expr    string
+		want    bool
+		wantErr bool
+	}{
+		{expr: `$CI_COMMIT_BRANCH == "release-1.0"`, want: true},
+		{expr: `$CI_COMMIT_BRANCH != 'main'`, want: true},
+		{expr: `$CI_COMMIT_BRANCH =~ /^RELEASE-/i`, want: true},
+		{expr: `$CI_COMMIT_BRANCH !~ /^release-/`, want: false},
+		{expr: `$CI_COMMIT_BRANCH =~ $PATTERN`, want: true},
+		{expr: `$CI_COMMIT_TAG`, want: false},
+		{expr: `$CI_COMMIT_TAG == null`, want: false},
+		{expr: `$CI_MERGE_REQUEST_IID == null`, want: true},
+		{expr: `${CI_COMMIT_BRANCH} && $UNDEFINED || $CI_COMMIT_BRANCH`, want: true},
+		{expr: `$CI_COMMIT_BRANCH && ($UNDEFINED || $CI_COMMIT_TAG)`, want: false},
+		{expr: `$CI_COMMIT_BRANCH ==`, wantErr: true},
+		{expr: `$CI_COMMIT_BRANCH == "main`, wantErr: true},
+		{expr: `($CI_COMMIT_BRANCH`, wantErr: true},
+		{expr: `CI_COMMIT_BRANCH == "main"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := evalExpression(tt.expr, vars)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("evalExpression() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("evalExpression() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
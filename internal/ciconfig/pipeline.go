@@ -0,0 +1,333 @@
+package ciconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Pipeline sources, as CI_PIPELINE_SOURCE.
+const (
+	SourcePush         = "push"
+	SourceMergeRequest = "merge_request_event"
+)
+
+// Pipeline is a pipeline to simulate.
+type Pipeline struct {
+	// Ref is the branch or the tag, or the source branch of a merge request
+	Ref    string
+	Tag    bool
+	Source string
+	// Variables are the predefined variables, e.g. CI_DEFAULT_BRANCH
+	Variables map[string]string
+	// Root is the directory the files of rules:exists are found in
+	Root string
+}
+
+// variables returns the predefined variables of the pipeline.
+func (p *Pipeline) variables() map[string]string {
+	vars := map[string]string{
+		"CI_COMMIT_REF_NAME": p.Ref,
+		"CI_PIPELINE_SOURCE": p.Source,
+	}
+	switch {
+	case p.Source == SourceMergeRequest:
+		vars["CI_MERGE_REQUEST_SOURCE_BRANCH_NAME"] = p.Ref
+		vars["CI_MERGE_REQUEST_EVENT_TYPE"] = "detached"
+	case p.Tag:
+		vars["CI_COMMIT_TAG"] = p.Ref
+	default:
+		vars["CI_COMMIT_BRANCH"] = p.Ref
+	}
+	for key, value := range p.Variables {
+		vars[key] = value
+	}
+	return vars
+}
+
+// Job is a job which a pipeline creates.
+type Job struct {
+	Name         string
+	Stage        string
+	When         string
+	AllowFailure bool
+}
+
+// Simulate returns the jobs the pipeline creates ordered by the stages, and
+// false when the workflow creates no pipeline. rules:changes and
+// only:changes are always true, as the changes are unknown offline.
+func Simulate(c *Config, p *Pipeline) ([]*Job, bool, error) {
+	vars := p.variables()
+	for key, value := range c.Variables() {
+		if _, ok := vars[key]; !ok {
+			vars[key] = value
+		}
+	}
+
+	if workflow, ok := c.Values["workflow"].(map[string]interface{}); ok && workflow["rules"] != nil {
+		rule, err := matchRules(workflow["rules"], vars, p.Root)
+		if err != nil {
+			return nil, false, fmt.Errorf("workflow %s", err.Error())
+		}
+		if rule == nil || rule["when"] == "never" {
+			return nil, false, nil
+		}
+		for key, value := range variables(rule["variables"]) {
+			vars[key] = value
+		}
+	}
+
+	var jobs []*Job
+	for _, stage := range c.Stages() {
+		for _, name := range c.Jobs() {
+			job := c.Job(name)
+			jobStage, _ := job["stage"].(string)
+			if jobStage == "" {
+				jobStage = defaultStage
+			}
+			if jobStage != stage {
+				continue
+			}
+
+			created, err := simulateJob(name, job, vars, p)
+			if err != nil {
+				return nil, true, err
+			}
+			if created != nil {
+				created.Stage = stage
+				jobs = append(jobs, created)
+			}
+		}
+	}
+	return jobs, true, nil
+}
+
+func simulateJob(name string, job map[string]interface{}, pipelineVars map[string]string, p *Pipeline) (*Job, error) {
+	vars := map[string]string{}
+	for key, value := range pipelineVars {
+		vars[key] = value
+	}
+	for key, value := range variables(job["variables"]) {
+		vars[key] = value
+	}
+
+	created := &Job{Name: name, When: "on_success"}
+	if when, ok := job["when"].(string); ok {
+		created.When = when
+	}
+	switch allowFailure := job["allow_failure"].(type) {
+	case bool:
+		created.AllowFailure = allowFailure
+	case map[string]interface{}:
+		// allow_failure:exit_codes
+		created.AllowFailure = true
+	default:
+		created.AllowFailure = created.When == "manual"
+	}
+
+	if job["rules"] != nil {
+		rule, err := matchRules(job["rules"], vars, p.Root)
+		if err != nil {
+			return nil, fmt.Errorf("jobs:%s %s", name, err.Error())
+		}
+		if rule == nil || rule["when"] == "never" {
+			return nil, nil
+		}
+		if when, ok := rule["when"].(string); ok {
+			created.When = when
+		}
+		if allowFailure, ok := rule["allow_failure"].(bool); ok {
+			created.AllowFailure = allowFailure
+		}
+		return created, nil
+	}
+
+	only, err := matchOnlyExcept(job["only"], vars, p, true)
+	if err != nil {
+		return nil, fmt.Errorf("jobs:%s only %s", name, err.Error())
+	}
+	except, err := matchOnlyExcept(job["except"], vars, p, false)
+	if err != nil {
+		return nil, fmt.Errorf("jobs:%s except %s", name, err.Error())
+	}
+	if !only || except {
+		return nil, nil
+	}
+	return created, nil
+}
+
+// matchRules returns the first rule which matches, or nil when no rule
+// matches.
+func matchRules(v interface{}, vars map[string]string, root string) (map[string]interface{}, error) {
+	list, _ := v.([]interface{})
+	for _, item := range list {
+		rule, _ := item.(map[string]interface{})
+		matched := true
+		if expr, ok := rule["if"].(string); ok {
+			result, err := evalExpression(expr, vars)
+			if err != nil {
+				return nil, fmt.Errorf("rules:if %s", err.Error())
+			}
+			matched = result
+		}
+		if exists, ok := rule["exists"]; ok && matched {
+			matched = existFiles(root, exists)
+		}
+		if matched {
+			return rule, nil
+		}
+	}
+	return nil, nil
+}
+
+// matchOnlyExcept returns whether the pipeline matches the only or the
+// except of a job. The conditions of only must all match, and one condition
+// of except is enough.
+func matchOnlyExcept(v interface{}, vars map[string]string, p *Pipeline, only bool) (bool, error) {
+	if v == nil {
+		if only {
+			return matchRefs([]string{"branches", "tags"}, p), nil
+		}
+		return false, nil
+	}
+
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return matchRefs(stringList(v), p), nil
+	}
+
+	var conditions []bool
+	refs := stringList(m["refs"])
+	if refs == nil && only {
+		refs = []string{"branches", "tags"}
+	}
+	if refs != nil {
+		conditions = append(conditions, matchRefs(refs, p))
+	}
+	if exprs, ok := m["variables"]; ok {
+		matched := false
+		for _, expr := range stringList(exprs) {
+			result, err := evalExpression(expr, vars)
+			if err != nil {
+				return false, fmt.Errorf("variables %s", err.Error())
+			}
+			matched = matched || result
+		}
+		conditions = append(conditions, matched)
+	}
+
+	for _, condition := range conditions {
+		if only && !condition {
+			return false, nil
+		}
+		if !only && condition {
+			return true, nil
+		}
+	}
+	return only, nil
+}
+
+// refKeywords are the keywords of only:refs with the pipelines they match.
+var refKeywords = map[string]func(p *Pipeline) bool{
+	"branches":       func(p *Pipeline) bool { return !p.Tag && p.Source != SourceMergeRequest },
+	"tags":           func(p *Pipeline) bool { return p.Tag },
+	"merge_requests": func(p *Pipeline) bool { return p.Source == SourceMergeRequest },
+	"pushes":         func(p *Pipeline) bool { return p.Source == SourcePush },
+	"api":            func(p *Pipeline) bool { return p.Source == "api" },
+	"chat":           func(p *Pipeline) bool { return p.Source == "chat" },
+	"external":       func(p *Pipeline) bool { return p.Source == "external" },
+	"pipelines":      func(p *Pipeline) bool { return p.Source == "pipeline" },
+	"schedules":      func(p *Pipeline) bool { return p.Source == "schedule" },
+	"triggers":       func(p *Pipeline) bool { return p.Source == "trigger" },
+	"web":            func(p *Pipeline) bool { return p.Source == "web" },
+}
+
+func matchRefs(refs []string, p *Pipeline) bool {
+	for _, ref := range refs {
+		// The refs of other projects are given as "ref@group/project"
+		if i := strings.LastIndex(ref, "@"); i > 0 {
+			ref = ref[:i]
+		}
+		if match, ok := refKeywords[ref]; ok {
+			if match(p) {
+				return true
+			}
+			continue
+		}
+		if strings.HasPrefix(ref, "/") {
+			re, err := compileRegexp(ref)
+			if err == nil && re.MatchString(p.Ref) {
+				return true
+			}
+			continue
+		}
+		if ref == p.Ref {
+			return true
+		}
+	}
+	return false
+}
+
+// existFiles returns whether a file matches one of the glob patterns of
+// rules:exists in root.
+func existFiles(root string, v interface{}) bool {
+	patterns := stringList(v)
+	if m, ok := v.(map[string]interface{}); ok {
+		patterns = stringList(m["paths"])
+	}
+
+	var res []*regexp.Regexp
+	for _, pattern := range patterns {
+		res = append(res, globRegexp(strings.TrimPrefix(pattern, "/")))
+	}
+	found := false
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || found {
+			return filepath.SkipDir
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		for _, re := range res {
+			if re.MatchString(filepath.ToSlash(rel)) {
+				found = true
+				return filepath.SkipDir
+			}
+		}
+		return nil
+	})
+	return found
+}
+
+// globRegexp converts a glob pattern with "**" to a regexp.
+func globRegexp(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			b.WriteString("[^/]*")
+		case pattern[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
@@ -0,0 +1,135 @@
+package ciconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const pipelineConfig = `stages: [build, test, deploy]
+workflow:
+  rules:
+    - if: $CI_COMMIT_BRANCH =~ /^wip-/
+      when: never
+    - when: always
+build:
+  stage: build
+  script: make
+unit:
+  stage: test
+  script: make test
+  rules:
+    - if: $CI_PIPELINE_SOURCE == "merge_request_event"
+    - if: $CI_COMMIT_BRANCH
+      exists: ["**/*_test.go"]
+docs:
+  stage: test
+  script: make docs
+  rules:
+    - exists: [docs/*.md]
+      when: manual
+lint:
+  script: make lint
+  only: [merge_requests, /^feature-/]
+release:
+  stage: deploy
+  script: make release
+  only:
+    refs: [tags]
+    variables: [$RELEASE == "true"]
+deploy:
+  stage: deploy
+  script: make deploy
+  rules:
+    - if: $CI_COMMIT_BRANCH == $CI_DEFAULT_BRANCH
+      when: manual
+      allow_failure: false
+  variables:
+    RELEASE: "true"
+cleanup:
+  stage: deploy
+  script: make clean
+  except: [tags]
+`
+
+func TestSimulate(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		".gitlab-ci.yml":   pipelineConfig,
+		"cmd/main_test.go": "package main",
+	})
+	defer os.RemoveAll(dir)
+
+	c, err := Load(dir, filepath.Join(dir, ".gitlab-ci.yml"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		pipeline    *Pipeline
+		want        []*Job
+		wantCreated bool
+	}{
+		{
+			name:     "default branch",
+			pipeline: &Pipeline{Ref: "main", Source: SourcePush, Variables: map[string]string{"CI_DEFAULT_BRANCH": "main"}},
+			want: []*Job{
+				{Name: "build", Stage: "build", When: "on_success"},
+				{Name: "unit", Stage: "test", When: "on_success"},
+				{Name: "deploy", Stage: "deploy", When: "manual"},
+				{Name: "cleanup", Stage: "deploy", When: "on_success"},
+			},
+			wantCreated: true,
+		},
+		{
+			name:     "feature branch",
+			pipeline: &Pipeline{Ref: "feature-login", Source: SourcePush},
+			want: []*Job{
+				{Name: "build", Stage: "build", When: "on_success"},
+				{Name: "unit", Stage: "test", When: "on_success"},
+				{Name: "lint", Stage: "test", When: "on_success"},
+				{Name: "cleanup", Stage: "deploy", When: "on_success"},
+			},
+			wantCreated: true,
+		},
+		{
+			name:     "merge request",
+			pipeline: &Pipeline{Ref: "fix", Source: SourceMergeRequest, Variables: map[string]string{"CI_DEFAULT_BRANCH": "main"}},
+			want: []*Job{
+				{Name: "unit", Stage: "test", When: "on_success"},
+				{Name: "lint", Stage: "test", When: "on_success"},
+			},
+			wantCreated: true,
+		},
+		{
+			name:     "tag",
+			pipeline: &Pipeline{Ref: "v1.0", Tag: true, Source: SourcePush, Variables: map[string]string{"CI_DEFAULT_BRANCH": "main", "RELEASE": "true"}},
+			want: []*Job{
+				{Name: "build", Stage: "build", When: "on_success"},
+				{Name: "release", Stage: "deploy", When: "on_success"},
+			},
+			wantCreated: true,
+		},
+		{
+			name:     "workflow",
+			pipeline: &Pipeline{Ref: "wip-login", Source: SourcePush},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.pipeline.Root = dir
+			got, created, err := Simulate(c, tt.pipeline)
+			if err != nil {
+				t.Fatalf("Simulate() error = %v", err)
+			}
+			if created != tt.wantCreated {
+				t.Errorf("Simulate() created = %v, want %v", created, tt.wantCreated)
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("Simulate() (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,399 @@
+package ciconfig
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// keyword is the schema of a keyword. want tells the valid values in the
+// errors.
+type keyword struct {
+	valid func(v interface{}) bool
+	want  string
+}
+
+var (
+	scriptKeyword   = keyword{isScript, "a string or a nested array of strings"}
+	stringKeyword   = keyword{isString, "a string"}
+	stringsKeyword  = keyword{isStringList, "a string or an array of strings"}
+	hashKeyword     = keyword{isHash, "a hash"}
+	boolKeyword     = keyword{isBool, "a boolean value"}
+	stringOrHash    = keyword{anyOf(isString, isHash), "a string or a hash"}
+	integerOrHash   = keyword{anyOf(isInteger, isHash), "an integer or a hash"}
+	boolOrHash      = keyword{anyOf(isBool, isHash), "a boolean value or a hash"}
+	listKeyword     = keyword{isList, "an array"}
+	hashOrList      = keyword{anyOf(isHash, isList), "a hash or an array"}
+	variableKeyword = keyword{isVariables, "a hash of key value pairs"}
+)
+
+var jobWhens = []string{"on_success", "on_failure", "always", "manual", "delayed"}
+
+// jobKeywords is the schema of the keywords of a job.
+var jobKeywords = map[string]keyword{
+	"after_script":        scriptKeyword,
+	"allow_failure":       boolOrHash,
+	"artifacts":           hashKeyword,
+	"before_script":       scriptKeyword,
+	"cache":               hashOrList,
+	"coverage":            stringKeyword,
+	"dast_configuration":  hashKeyword,
+	"dependencies":        listKeyword,
+	"environment":         stringOrHash,
+	"except":              keyword{isOnlyExcept, "an array of strings or a hash"},
+	"hooks":               hashKeyword,
+	"id_tokens":           hashKeyword,
+	"identity":            stringKeyword,
+	"image":               stringOrHash,
+	"inherit":             hashKeyword,
+	"interruptible":       boolKeyword,
+	"manual_confirmation": stringKeyword,
+	"needs":               listKeyword,
+	"only":                keyword{isOnlyExcept, "an array of strings or a hash"},
+	"pages":               boolOrHash,
+	"parallel":            integerOrHash,
+	"publish":             stringKeyword,
+	"release":             hashKeyword,
+	"resource_group":      stringKeyword,
+	"retry":               integerOrHash,
+	"rules":               listKeyword,
+	"script":              scriptKeyword,
+	"secrets":             hashKeyword,
+	"services":            listKeyword,
+	"stage":               stringKeyword,
+	"start_in":            stringKeyword,
+	"tags":                stringsKeyword,
+	"timeout":             stringKeyword,
+	"trigger":             stringOrHash,
+	"variables":           variableKeyword,
+	"when":                keyword{oneOf(jobWhens...), "one of " + strings.Join(jobWhens, ", ")},
+}
+
+// defaultKeywords are the keywords of a job which the default can set.
+var defaultKeywords = []string{
+	"after_script", "artifacts", "before_script", "cache", "hooks", "id_tokens",
+	"image", "interruptible", "retry", "services", "tags", "timeout",
+}
+
+var ruleWhens = []string{"on_success", "on_failure", "always", "manual", "delayed", "never"}
+
+// ruleKeywords is the schema of the keywords of a rule of a job.
+var ruleKeywords = map[string]keyword{
+	"if":            stringKeyword,
+	"changes":       hashOrList,
+	"exists":        hashOrList,
+	"when":          keyword{oneOf(ruleWhens...), "one of " + strings.Join(ruleWhens, ", ")},
+	"allow_failure": boolKeyword,
+	"variables":     variableKeyword,
+	"start_in":      stringKeyword,
+	"needs":         listKeyword,
+	"interruptible": boolKeyword,
+}
+
+// Validate returns the errors of the config. The errors of the jobs start
+// with "jobs:<name>", as the errors of GitLab.
+func Validate(c *Config) []string {
+	var errs []string
+	errs = append(errs, validateRoot(c)...)
+
+	jobs := c.Jobs()
+	if len(jobs) == 0 {
+		errs = append(errs, "jobs config should contain at least one visible job")
+	}
+	stages := c.Stages()
+	for _, name := range jobs {
+		errs = append(errs, validateJob(c, name, stages)...)
+	}
+	return errs
+}
+
+func validateRoot(c *Config) []string {
+	var errs []string
+	if v, ok := c.Values["stages"]; ok && !isStringList(v) {
+		errs = append(errs, "stages config should be an array of strings")
+	}
+	if v, ok := c.Values["variables"]; ok && !isVariables(v) {
+		errs = append(errs, "variables config should be a hash of key value pairs")
+	}
+	if v, ok := c.Values["default"]; ok {
+		m, isMap := v.(map[string]interface{})
+		if !isMap {
+			errs = append(errs, "default config should be a hash")
+		}
+		for _, key := range unknownKeys(m, defaultKeywords) {
+			errs = append(errs, fmt.Sprintf("default config contains unknown keys: %s", key))
+		}
+	}
+	if v, ok := c.Values["workflow"]; ok {
+		m, isMap := v.(map[string]interface{})
+		if !isMap {
+			errs = append(errs, "workflow config should be a hash")
+		}
+		for _, key := range unknownKeys(m, []string{"rules", "name", "auto_cancel"}) {
+			errs = append(errs, fmt.Sprintf("workflow config contains unknown keys: %s", key))
+		}
+		errs = append(errs, validateRules("workflow", m["rules"])...)
+	}
+	for _, key := range c.Keys {
+		if !isJob(key, c.Values[key]) && !isReserved(key) && !strings.HasPrefix(key, ".") {
+			errs = append(errs, fmt.Sprintf("jobs:%s config should be a hash", key))
+		}
+	}
+	return errs
+}
+
+func validateJob(c *Config, name string, stages []string) []string {
+	var errs []string
+	job := c.Job(name)
+	prefix := "jobs:" + name
+
+	var keys []string
+	for key := range jobKeywords {
+		keys = append(keys, key)
+	}
+	for _, key := range unknownKeys(job, keys) {
+		errs = append(errs, fmt.Sprintf("%s config contains unknown keys: %s", prefix, key))
+	}
+	for _, key := range sortedKeys(job) {
+		if kw, ok := jobKeywords[key]; ok && !kw.valid(job[key]) {
+			errs = append(errs, fmt.Sprintf("%s %s config should be %s", prefix, key, kw.want))
+		}
+	}
+
+	if _, ok := job["script"]; !ok {
+		if _, ok := job["trigger"]; !ok {
+			errs = append(errs, fmt.Sprintf("%s config should implement a script: or a trigger: keyword", prefix))
+		}
+	}
+	stage := defaultStage
+	if s, ok := job["stage"].(string); ok {
+		stage = s
+	}
+	if !contains(stages, stage) {
+		errs = append(errs, fmt.Sprintf("%s stage parameter should be %s", prefix, strings.Join(stages, ", ")))
+	}
+	if job["when"] == "delayed" && job["start_in"] == nil {
+		errs = append(errs, fmt.Sprintf("%s start in should be specified for delayed job", prefix))
+	}
+	if _, ok := job["rules"]; ok {
+		for _, key := range []string{"only", "except"} {
+			if _, ok := job[key]; ok {
+				errs = append(errs, fmt.Sprintf("%s config key may not be used with `rules`: %s", prefix, key))
+			}
+		}
+		errs = append(errs, validateRules(prefix, job["rules"])...)
+	}
+
+	jobs := c.Jobs()
+	for _, need := range needs(job["needs"]) {
+		if !need.optional && !contains(jobs, need.job) {
+			errs = append(errs, fmt.Sprintf("%s needs job %s which is not defined", prefix, need.job))
+		}
+	}
+	return errs
+}
+
+func validateRules(prefix string, v interface{}) []string {
+	if v == nil {
+		return nil
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		return []string{fmt.Sprintf("%s rules config should be an array of hashes", prefix)}
+	}
+
+	var errs []string
+	var keys []string
+	for key := range ruleKeywords {
+		keys = append(keys, key)
+	}
+	for _, item := range list {
+		rule, ok := item.(map[string]interface{})
+		if !ok {
+			errs = append(errs, fmt.Sprintf("%s rules:rule config should be a hash", prefix))
+			continue
+		}
+		for _, key := range unknownKeys(rule, keys) {
+			errs = append(errs, fmt.Sprintf("%s rules:rule config contains unknown keys: %s", prefix, key))
+		}
+		for _, key := range sortedKeys(rule) {
+			if kw, ok := ruleKeywords[key]; ok && !kw.valid(rule[key]) {
+				errs = append(errs, fmt.Sprintf("%s rules:rule %s config should be %s", prefix, key, kw.want))
+			}
+		}
+		if expr, ok := rule["if"].(string); ok {
+			if _, err := evalExpression(expr, nil); err != nil {
+				errs = append(errs, fmt.Sprintf("%s rules:rule if %s", prefix, err.Error()))
+			}
+		}
+	}
+	return errs
+}
+
+// need is a job in the needs of a job.
+type need struct {
+	job      string
+	optional bool
+}
+
+func needs(v interface{}) []need {
+	list, _ := v.([]interface{})
+	var result []need
+	for _, item := range list {
+		switch item := item.(type) {
+		case string:
+			result = append(result, need{job: item})
+		case map[string]interface{}:
+			// The needs of other pipelines are not checked
+			if _, ok := item["pipeline"]; ok {
+				continue
+			}
+			if _, ok := item["project"]; ok {
+				continue
+			}
+			job, _ := item["job"].(string)
+			optional, _ := item["optional"].(bool)
+			result = append(result, need{job: job, optional: optional})
+		}
+	}
+	return result
+}
+
+func unknownKeys(m map[string]interface{}, known []string) []string {
+	var unknown []string
+	for _, key := range sortedKeys(m) {
+		if !contains(known, key) {
+			unknown = append(unknown, key)
+		}
+	}
+	return unknown
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isReserved(key string) bool {
+	return contains(reservedKeys, key)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func anyOf(valids ...func(v interface{}) bool) func(v interface{}) bool {
+	return func(v interface{}) bool {
+		for _, valid := range valids {
+			if valid(v) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func oneOf(values ...string) func(v interface{}) bool {
+	return func(v interface{}) bool {
+		s, ok := v.(string)
+		return ok && contains(values, s)
+	}
+}
+
+func isString(v interface{}) bool {
+	_, ok := v.(string)
+	return ok
+}
+
+func isBool(v interface{}) bool {
+	_, ok := v.(bool)
+	return ok
+}
+
+func isInteger(v interface{}) bool {
+	_, ok := v.(int)
+	return ok
+}
+
+func isHash(v interface{}) bool {
+	_, ok := v.(map[string]interface{})
+	return ok
+}
+
+func isList(v interface{}) bool {
+	_, ok := v.([]interface{})
+	return ok
+}
+
+func isStringList(v interface{}) bool {
+	if isString(v) {
+		return true
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, item := range list {
+		if !isString(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// isScript accepts a string or an array of strings nested up to 10 levels,
+// which are made by the anchors of scripts.
+func isScript(v interface{}) bool {
+	var valid func(v interface{}, depth int) bool
+	valid = func(v interface{}, depth int) bool {
+		if isString(v) {
+			return true
+		}
+		list, ok := v.([]interface{})
+		if !ok || depth > 10 {
+			return false
+		}
+		for _, item := range list {
+			if !valid(item, depth+1) {
+				return false
+			}
+		}
+		return true
+	}
+	return valid(v, 0)
+}
+
+func isVariables(v interface{}) bool {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for _, value := range m {
+		switch value.(type) {
+		case string, int, float64, bool, map[string]interface{}:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isOnlyExcept(v interface{}) bool {
+	if isStringList(v) {
+		return true
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	return len(unknownKeys(m, []string{"refs", "variables", "changes", "kubernetes"})) == 0
+}
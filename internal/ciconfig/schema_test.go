@@ -0,0 +1,83 @@
+package ciconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidate(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		".gitlab-ci.yml": `stages: [build, test]
+default:
+  image: golang
+  stage: build
+workflow:
+  rules:
+    - if: $CI_COMMIT_BRANCH ==
+build:
+  stage: build
+  script: [make, [make install]]
+  when: later
+test:
+  stage: check
+  rules:
+    - if: $CI_COMMIT_BRANCH
+      when: sometimes
+  only: [main]
+  needs: [build, lint, {job: docs, optional: true}]
+deploy:
+  stage: test
+  scripts: make deploy
+  trigger: group/deploy
+`,
+	})
+	defer os.RemoveAll(dir)
+
+	c, err := Load(dir, filepath.Join(dir, ".gitlab-ci.yml"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	got := Validate(c)
+	want := []string{
+		"default config contains unknown keys: stage",
+		"workflow rules:rule if invalid expression syntax, missing operand",
+		"jobs:build when config should be one of on_success, on_failure, always, manual, delayed",
+		"jobs:test config should implement a script: or a trigger: keyword",
+		"jobs:test stage parameter should be .pre, build, test, .post",
+		"jobs:test config key may not be used with `rules`: only",
+		"jobs:test rules:rule when config should be one of on_success, on_failure, always, manual, delayed, never",
+		"jobs:test needs job lint which is not defined",
+		"jobs:deploy config contains unknown keys: scripts",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Validate() (-got +want)\n%s", diff)
+	}
+}
+
+func TestValidate_defaultStage(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		".gitlab-ci.yml": `stages: [build]
+compile:
+  stage: build
+  script: make
+unit:
+  script: make test
+`,
+	})
+	defer os.RemoveAll(dir)
+
+	c, err := Load(dir, filepath.Join(dir, ".gitlab-ci.yml"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	got := Validate(c)
+	want := []string{
+		"jobs:unit stage parameter should be .pre, build, .post",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Validate() (-got +want)\n%s", diff)
+	}
+}
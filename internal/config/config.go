@@ -20,9 +20,34 @@ type Config struct {
 }
 
 type Profile struct {
-	Token          string `yaml:"token"`
+	Token string `yaml:"token"`
+	// TokenCommand is the command printing the token, like "pass show gitlab"
+	TokenCommand string `yaml:"token_command,omitempty"`
+	// TokenStore is where the token is kept, "config" for the token above,
+	// "keyring" for the Secret Service or "git" for the git credential
+	// helpers. It is "config" when empty
+	TokenStore     string `yaml:"token_store,omitempty"`
 	DefaultGroup   string `yaml:"default_group"`
 	DefaultProject string `yaml:"default_project"`
+	// Hosts are the other host names used by the git remotes of the profile,
+	// like the host of the ssh server or an alias in ~/.ssh/config
+	Hosts []string `yaml:"hosts,omitempty"`
+	// BaseURL is the url of the GitLab, like "http://example.com:8080/gitlab".
+	// It is "https://" and the profile name when empty
+	BaseURL string `yaml:"base_url,omitempty"`
+	TLS     `yaml:",inline"`
+}
+
+// TLS is the setting of the TLS connection to the GitLab API.
+type TLS struct {
+	// CACert is the path of a PEM bundle trusted in addition to the system
+	// certificates
+	CACert string `yaml:"ca_cert,omitempty"`
+	// ClientCert and ClientKey are the paths of the PEM encoded client
+	// certificate and its private key
+	ClientCert         string `yaml:"client_cert,omitempty"`
+	ClientKey          string `yaml:"client_key,omitempty"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify,omitempty"`
 }
 
 func NewConfig() *Config {
@@ -44,6 +69,11 @@ func (c *Config) Path() string {
 	return configFilePath
 }
 
+// CacheDir returns the directory of the cache, next to the config file.
+func (c *Config) CacheDir() string {
+	return filepath.Join(filepath.Dir(configFilePath), "cache")
+}
+
 func (c *Config) Read() (string, error) {
 	if err := os.MkdirAll(filepath.Dir(configFilePath), 0700); err != nil {
 		return "", fmt.Errorf("cannot create directory, %s", err)
@@ -100,7 +130,7 @@ func (c *Config) Load() error {
 }
 
 func (c *Config) Save() error {
-	file, err := os.OpenFile(configFilePath, os.O_WRONLY, 0666)
+	file, err := os.OpenFile(configFilePath, os.O_WRONLY|os.O_TRUNC, 0666)
 	if err != nil {
 		return fmt.Errorf("cannot open file, %s", err)
 	}
@@ -142,15 +172,21 @@ func (c *Config) HasDomain(domain string) bool {
 	return true
 }
 
-func (c *Config) GetToken(domain string) string {
-	profile, _ := c.GetProfile(domain)
-	return profile.Token
-}
+// FindProfile returns the name of the profile of a git remote host. The host
+// matches the profile named after it, or a profile listing it in its hosts.
+func (c *Config) FindProfile(host string) (string, bool) {
+	if c.HasDomain(host) {
+		return host, true
+	}
 
-func (c *Config) SetToken(domain, token string) {
-	profile, _ := c.GetProfile(domain)
-	profile.Token = token
-	c.SetProfile(domain, *profile)
+	for _, name := range c.ProfileNames() {
+		for _, h := range c.Profiles[name].Hosts {
+			if h == host {
+				return name, true
+			}
+		}
+	}
+	return "", false
 }
 
 func getXDGConfigPath(goos string) string {
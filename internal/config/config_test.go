@@ -81,6 +81,36 @@ default_profile: default_profile
 			},
 			wantErr: false,
 		},
+		{
+			name: "self-hosted",
+			configContents: `profiles:
+  code.corp.example:
+    token: token1
+    hosts:
+    - ssh.code.corp.example
+    base_url: http://code.corp.example:8080/gitlab
+    ca_cert: ~/corp-ca.pem
+    client_cert: ~/client.pem
+    client_key: ~/client-key.pem
+    insecure_skip_verify: true
+`,
+			want: &Config{
+				Profiles: map[string]Profile{
+					"code.corp.example": Profile{
+						Token:   "token1",
+						Hosts:   []string{"ssh.code.corp.example"},
+						BaseURL: "http://code.corp.example:8080/gitlab",
+						TLS: TLS{
+							CACert:             "~/corp-ca.pem",
+							ClientCert:         "~/client.pem",
+							ClientKey:          "~/client-key.pem",
+							InsecureSkipVerify: true,
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -200,3 +230,53 @@ func Test_getXDGConfigPath(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_FindProfile(t *testing.T) {
+	c := &Config{
+		Profiles: map[string]Profile{
+			"gitlab.com": Profile{},
+			"code.corp.example": Profile{
+				Hosts: []string{"ssh.code.corp.example", "corp"},
+			},
+		},
+	}
+	tests := []struct {
+		name   string
+		host   string
+		want   string
+		wantOk bool
+	}{
+		{
+			name:   "profile name",
+			host:   "gitlab.com",
+			want:   "gitlab.com",
+			wantOk: true,
+		},
+		{
+			name:   "known host",
+			host:   "ssh.code.corp.example",
+			want:   "code.corp.example",
+			wantOk: true,
+		},
+		{
+			name:   "ssh alias",
+			host:   "corp",
+			want:   "code.corp.example",
+			wantOk: true,
+		},
+		{
+			name:   "unknown host",
+			host:   "github.com",
+			want:   "",
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotOk := c.FindProfile(tt.host)
+			if got != tt.want || gotOk != tt.wantOk {
+				t.Errorf("Config.FindProfile() = %v, %v, want %v, %v", got, gotOk, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// TokenStoreConfig keeps the token in the config file, in plain text
+	TokenStoreConfig = "config"
+	// TokenStoreKeyring keeps the token in the Secret Service, like the
+	// GNOME Keyring or KWallet, with secret-tool of libsecret
+	TokenStoreKeyring = "keyring"
+	// TokenStoreGit keeps the token in the git credential helpers, as the
+	// password of the GitLab host
+	TokenStoreGit = "git"
+)
+
+const (
+	// tokenEnv is the token of every profile
+	tokenEnv = "GITLAB_TOKEN"
+	// keyringService is the service of the tokens in the Secret Service
+	keyringService = "lab"
+	// gitCredentialUser is the user name of the tokens stored in the git
+	// credential helpers. GitLab takes any user name with a token
+	gitCredentialUser = "lab"
+)
+
+// For os/exec test
+var runCommand = func(input string, env []string, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(input)
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+// GetToken returns the token of the profile from the first of the
+// environment variables, the token command and the token store of the
+// profile.
+func (c *Config) GetToken(domain string) (string, error) {
+	profile, err := c.GetProfile(domain)
+	if err != nil {
+		return "", err
+	}
+
+	if token := os.Getenv(TokenEnv(domain)); token != "" {
+		return token, nil
+	}
+	if token := os.Getenv(tokenEnv); token != "" {
+		return token, nil
+	}
+	if profile.TokenCommand != "" {
+		return commandToken(profile.TokenCommand)
+	}
+
+	switch profile.TokenStore {
+	case "", TokenStoreConfig:
+		return profile.Token, nil
+	case TokenStoreKeyring:
+		return keyringToken(domain)
+	case TokenStoreGit:
+		return gitCredentialToken(profile.credentialURL(domain))
+	}
+	return "", unknownTokenStoreError(profile.TokenStore)
+}
+
+// SetToken keeps the token in the token store of the profile. The config has
+// to be saved for the token store "config".
+func (c *Config) SetToken(domain, token string) error {
+	profile, err := c.GetProfile(domain)
+	if err != nil {
+		return err
+	}
+
+	switch profile.TokenStore {
+	case "", TokenStoreConfig:
+		profile.Token = token
+		c.SetProfile(domain, *profile)
+		return nil
+	case TokenStoreKeyring:
+		return setKeyringToken(domain, token)
+	case TokenStoreGit:
+		return setGitCredentialToken(profile.credentialURL(domain), token)
+	}
+	return unknownTokenStoreError(profile.TokenStore)
+}
+
+// MigrateTokens moves the tokens in the config file to the token store, and
+// returns the names of the moved profiles. The config has to be saved after.
+func (c *Config) MigrateTokens(store string) ([]string, error) {
+	switch store {
+	case TokenStoreKeyring, TokenStoreGit:
+	default:
+		return nil, unknownTokenStoreError(store)
+	}
+
+	var migrated []string
+	for _, name := range c.ProfileNames() {
+		profile := c.Profiles[name]
+		if profile.Token == "" {
+			continue
+		}
+		if profile.TokenStore != "" && profile.TokenStore != TokenStoreConfig {
+			continue
+		}
+
+		token := profile.Token
+		profile.TokenStore = store
+		profile.Token = ""
+		c.SetProfile(name, profile)
+		if err := c.SetToken(name, token); err != nil {
+			// Keep the token in the config not to lose it
+			profile.TokenStore = ""
+			profile.Token = token
+			c.SetProfile(name, profile)
+			return migrated, fmt.Errorf("Failed move the token of %s. %s", name, err)
+		}
+		migrated = append(migrated, name)
+	}
+	return migrated, nil
+}
+
+// ProfileNames returns the names of the profiles in order.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TokenEnv returns the environment variable of the token of a profile, like
+// "GITLAB_TOKEN_GITLAB_EXAMPLE_COM" for "gitlab.example.com".
+func TokenEnv(domain string) string {
+	name := strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, domain)
+	return tokenEnv + "_" + name
+}
+
+func unknownTokenStoreError(store string) error {
+	return fmt.Errorf("Unknown token store %q. Please set \"config\", \"keyring\" or \"git\"", store)
+}
+
+// credentialURL returns the url of the GitLab of the profile.
+func (p *Profile) credentialURL(domain string) *url.URL {
+	if p.BaseURL != "" {
+		if u, err := url.Parse(p.BaseURL); err == nil && u.Host != "" {
+			return u
+		}
+	}
+	return &url.URL{Scheme: "https", Host: domain}
+}
+
+func commandToken(command string) (string, error) {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	token, err := runCommand("", nil, shell, flag, command)
+	if err != nil {
+		return "", fmt.Errorf("Failed run token command. %s", commandError(err))
+	}
+	return strings.TrimSpace(token), nil
+}
+
+func keyringToken(domain string) (string, error) {
+	token, err := runCommand("", nil, "secret-tool", "lookup", "service", keyringService, "profile", domain)
+	if _, ok := err.(*exec.ExitError); ok {
+		// secret-tool fails when the token is not stored
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("Failed read token from keyring. %s", commandError(err))
+	}
+	return token, nil
+}
+
+func setKeyringToken(domain, token string) error {
+	_, err := runCommand(
+		token,
+		nil,
+		"secret-tool", "store",
+		"--label", fmt.Sprintf("GitLab token of %s for lab", domain),
+		"service", keyringService,
+		"profile", domain,
+	)
+	if err != nil {
+		return fmt.Errorf("Failed store token to keyring. %s", commandError(err))
+	}
+	return nil
+}
+
+// gitCredentialToken returns the password of the GitLab host stored for the
+// user of lab in the git credential helpers, not the password of the git
+// remotes of the other users.
+func gitCredentialToken(u *url.URL) (string, error) {
+	input := fmt.Sprintf("protocol=%s\nhost=%s\nusername=%s\n\n", u.Scheme, u.Host, gitCredentialUser)
+	// The user is not asked for the password the helpers do not have
+	out, err := runCommand(input, []string{"GIT_TERMINAL_PROMPT=0"}, "git", "credential", "fill")
+	if _, ok := err.(*exec.ExitError); ok {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("Failed read token from git credential. %s", commandError(err))
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		if password := strings.TrimPrefix(scanner.Text(), "password="); password != scanner.Text() {
+			return password, nil
+		}
+	}
+	return "", nil
+}
+
+func setGitCredentialToken(u *url.URL, token string) error {
+	input := fmt.Sprintf("protocol=%s\nhost=%s\nusername=%s\npassword=%s\n\n", u.Scheme, u.Host, gitCredentialUser, token)
+	if _, err := runCommand(input, nil, "git", "credential", "approve"); err != nil {
+		return fmt.Errorf("Failed store token to git credential. %s", commandError(err))
+	}
+	return nil
+}
+
+// commandError returns the error with the message the command printed.
+func commandError(err error) string {
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return strings.TrimSpace(string(exitErr.Stderr))
+	}
+	return err.Error()
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeCommand replaces the commands run for the tokens, and returns the
+// commands run with the inputs.
+func fakeCommand(outputs map[string]string) (*[]string, func()) {
+	var runs []string
+	runCommand = func(input string, env []string, name string, args ...string) (string, error) {
+		command := strings.Join(append([]string{name}, args...), " ")
+		runs = append(runs, command+"\n"+input)
+		out, ok := outputs[command]
+		if !ok {
+			return "", &exec.ExitError{ProcessState: &os.ProcessState{}}
+		}
+		return out, nil
+	}
+	return &runs, func() { runCommand = defaultRunCommand }
+}
+
+var defaultRunCommand = runCommand
+
+func TestConfig_GetToken(t *testing.T) {
+	outputs := map[string]string{
+		"sh -c pass show gitlab":                         "command-token\n",
+		"secret-tool lookup service lab profile keyring": "keyring-token",
+		"git credential fill":                            "protocol=https\nhost=git.example\nusername=lab\npassword=git-token",
+	}
+	runs, restore := fakeCommand(outputs)
+	defer restore()
+
+	c := &Config{
+		Profiles: map[string]Profile{
+			"plain":       {Token: "plain-token"},
+			"command":     {Token: "plain-token", TokenCommand: "pass show gitlab"},
+			"keyring":     {TokenStore: TokenStoreKeyring},
+			"git.example": {TokenStore: TokenStoreGit},
+			"env.example": {Token: "plain-token"},
+			"unknown":     {TokenStore: "vault"},
+		},
+	}
+	os.Setenv(TokenEnv("env.example"), "env-token")
+	defer os.Unsetenv(TokenEnv("env.example"))
+
+	tests := []struct {
+		domain  string
+		want    string
+		wantErr bool
+	}{
+		{domain: "plain", want: "plain-token"},
+		{domain: "command", want: "command-token"},
+		{domain: "keyring", want: "keyring-token"},
+		{domain: "git.example", want: "git-token"},
+		{domain: "env.example", want: "env-token"},
+		{domain: "unknown", wantErr: true},
+		{domain: "none", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			got, err := c.GetToken(tt.domain)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetToken() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	wantFill := "git credential fill\nprotocol=https\nhost=git.example\nusername=lab\n\n"
+	if !containsRun(*runs, wantFill) {
+		t.Errorf("GetToken() ran %q, want %q", *runs, wantFill)
+	}
+}
+
+func containsRun(runs []string, run string) bool {
+	for _, r := range runs {
+		if r == run {
+			return true
+		}
+	}
+	return false
+}
+
+func TestConfig_GetToken_notStored(t *testing.T) {
+	_, restore := fakeCommand(map[string]string{})
+	defer restore()
+
+	c := &Config{
+		Profiles: map[string]Profile{
+			"keyring": {TokenStore: TokenStoreKeyring},
+		},
+	}
+	got, err := c.GetToken("keyring")
+	if err != nil {
+		t.Fatalf("GetToken() error = %v", err)
+	}
+	if got != "" {
+		t.Errorf("GetToken() = %q, want empty", got)
+	}
+}
+
+func TestConfig_MigrateTokens(t *testing.T) {
+	runs, restore := fakeCommand(map[string]string{
+		"git credential approve": "",
+	})
+	defer restore()
+
+	c := &Config{
+		Profiles: map[string]Profile{
+			"gitlab.com": {Token: "token1"},
+			"code.corp.example": {
+				Token:   "token2",
+				BaseURL: "http://code.corp.example:8080/gitlab",
+			},
+			"keyring": {TokenStore: TokenStoreKeyring},
+		},
+	}
+	migrated, err := c.MigrateTokens(TokenStoreGit)
+	if err != nil {
+		t.Fatalf("MigrateTokens() error = %v", err)
+	}
+	if diff := cmp.Diff(migrated, []string{"code.corp.example", "gitlab.com"}); diff != "" {
+		t.Errorf("MigrateTokens() differs: (-got +want)\n%s", diff)
+	}
+
+	wantRuns := []string{
+		"git credential approve\nprotocol=http\nhost=code.corp.example:8080\nusername=lab\npassword=token2\n\n",
+		"git credential approve\nprotocol=https\nhost=gitlab.com\nusername=lab\npassword=token1\n\n",
+	}
+	if diff := cmp.Diff(*runs, wantRuns); diff != "" {
+		t.Errorf("MigrateTokens() differs: (-got +want)\n%s", diff)
+	}
+
+	want := Profile{TokenStore: TokenStoreGit}
+	if diff := cmp.Diff(c.Profiles["gitlab.com"], want); diff != "" {
+		t.Errorf("MigrateTokens() differs: (-got +want)\n%s", diff)
+	}
+}
+
+func TestConfig_MigrateTokens_failed(t *testing.T) {
+	_, restore := fakeCommand(map[string]string{})
+	defer restore()
+
+	c := &Config{
+		Profiles: map[string]Profile{
+			"gitlab.com": {Token: "token1"},
+		},
+	}
+	if _, err := c.MigrateTokens(TokenStoreKeyring); err == nil {
+		t.Fatalf("MigrateTokens() error = nil, want the error of secret-tool")
+	}
+	want := Profile{Token: "token1"}
+	if diff := cmp.Diff(c.Profiles["gitlab.com"], want); diff != "" {
+		t.Errorf("MigrateTokens() differs: (-got +want)\n%s", diff)
+	}
+}
+
+func TestTokenEnv(t *testing.T) {
+	if got, want := TokenEnv("gitlab.example.com"), "GITLAB_TOKEN_GITLAB_EXAMPLE_COM"; got != want {
+		t.Errorf("TokenEnv() = %q, want %q", got, want)
+	}
+}
@@ -23,12 +23,31 @@ type GitLabProjectInfo struct {
 	Domain  string
 	Project string
 	Token   string
+	// URL is the base url of the GitLab, BaseUrl is "https://" + Domain when
+	// it is empty
+	URL string
+	TLS config.TLS
 }
 
 func (r *GitLabProjectInfo) BaseUrl() string {
+	if r.URL != "" {
+		return strings.TrimSuffix(r.URL, "/")
+	}
 	return "https://" + r.Domain
 }
 
+// setProfile sets the GitLab of the profile as the target. The token is read
+// from the token backends after the target is decided.
+func (r *GitLabProjectInfo) setProfile(domain string, profile *config.Profile) {
+	if r.Domain != domain {
+		// The token is of the other domain
+		r.Token = ""
+	}
+	r.Domain = domain
+	r.URL = profile.BaseURL
+	r.TLS = profile.TLS
+}
+
 func (r *GitLabProjectInfo) ApiUrl() string {
 	return strings.Join([]string{r.BaseUrl(), "api", "v4"}, "/")
 }
@@ -91,6 +110,13 @@ func (c *RemoteCollecter) CollectTarget(project, profile string) (*GitLabProject
 		}
 	}
 
+	// The token of the local repository is read once, it may run a command
+	if pInfo.Domain != "" && pInfo.Token == "" {
+		pInfo.Token, err = c.Cfg.GetToken(pInfo.Domain)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return pInfo, nil
 }
 
@@ -99,8 +125,7 @@ func (c *RemoteCollecter) collectTargetByDefaultConfig(pInfo *GitLabProjectInfo)
 		return pInfo
 	}
 	profile := c.Cfg.GetDefaultProfile()
-	pInfo.Domain = c.Cfg.DefalutProfile
-	pInfo.Token = profile.Token
+	pInfo.setProfile(c.Cfg.DefalutProfile, profile)
 
 	if profile.DefaultProject == "" {
 		return pInfo
@@ -116,9 +141,18 @@ func (c *RemoteCollecter) collectTargetByLocalRepository(pInfo *GitLabProjectInf
 		return nil, err
 	}
 
-	gitlabRemotes := filterHasGitlabDomain(gitRemotes)
+	hostNames, err := loadSSHHostNames(sshConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	gitlabRemotes := c.filterHasProfile(gitRemotes, hostNames)
+	if len(gitlabRemotes) == 0 {
+		// Guess the remote of the GitLab that is not configured yet
+		gitlabRemotes = filterHasGitlabDomain(resolveSSHHostNames(gitRemotes, hostNames))
+	}
 	if len(gitlabRemotes) == 0 {
-		return nil, fmt.Errorf("Not found gitlab remote repository")
+		return nil, fmt.Errorf("Not found gitlab remote repository. Please add the host of the remote to the hosts of a profile")
 	}
 	gitlabRemotes = excludeDuplicateDomain(gitlabRemotes)
 	targetRepo := gitlabRemotes[0]
@@ -135,7 +169,10 @@ func (c *RemoteCollecter) collectTargetByLocalRepository(pInfo *GitLabProjectInf
 		c.UI.Message("Saved profile.")
 	}
 
-	token = c.Cfg.GetToken(domain)
+	token, err = c.Cfg.GetToken(domain)
+	if err != nil {
+		return nil, err
+	}
 	if token == "" {
 		c.UI.Message(fmt.Sprintf("Not found private token in the domain [%s].", domain))
 		token, err = c.UI.Ask("Please enter GitLab private token:")
@@ -143,15 +180,25 @@ func (c *RemoteCollecter) collectTargetByLocalRepository(pInfo *GitLabProjectInf
 			return nil, fmt.Errorf("cannot read private token, %s", err)
 		}
 
-		c.Cfg.SetToken(domain, token)
+		if err := c.Cfg.SetToken(domain, token); err != nil {
+			return nil, err
+		}
 		if err := c.Cfg.Save(); err != nil {
 			return nil, err
 		}
 		c.UI.Message("Saved private Token.")
 	}
 
-	pInfo.Domain = domain
+	profile, err := c.Cfg.GetProfile(domain)
+	if err != nil {
+		return nil, err
+	}
+	pInfo.setProfile(domain, profile)
 	pInfo.Token = token
+	if pInfo.URL == "" && (targetRepo.Scheme != "" || targetRepo.Port != "") {
+		// The http remote tells where the GitLab is served
+		pInfo.URL = targetRepo.BaseUrl()
+	}
 	pInfo.Project = targetRepo.RepositoryFullName()
 
 	return pInfo, nil
@@ -163,8 +210,7 @@ func (c *RemoteCollecter) collectTargetByArgs(pInfo *GitLabProjectInfo, project,
 		if err != nil {
 			return nil, err
 		}
-		pInfo.Domain = profile
-		pInfo.Token = p.Token
+		pInfo.setProfile(profile, p)
 	}
 
 	if project != "" {
@@ -174,6 +220,41 @@ func (c *RemoteCollecter) collectTargetByArgs(pInfo *GitLabProjectInfo, project,
 	return pInfo, nil
 }
 
+// resolveSSHHostNames replaces the ssh host aliases in the domain of the
+// remotes by the host names.
+func resolveSSHHostNames(remoteInfos []*git.RemoteInfo, hostNames map[string]string) []*git.RemoteInfo {
+	resolved := make([]*git.RemoteInfo, len(remoteInfos))
+	for i, remoteInfo := range remoteInfos {
+		resolved[i] = remoteInfo
+		if hostName, ok := hostNames[remoteInfo.Domain]; ok {
+			r := *remoteInfo
+			r.Domain = hostName
+			resolved[i] = &r
+		}
+	}
+	return resolved
+}
+
+// filterHasProfile returns the remotes hosted on a GitLab of the config,
+// matching the domain or the host name of its ssh alias. The domain of the
+// remotes is replaced by the name of the profile.
+func (c *RemoteCollecter) filterHasProfile(remoteInfos []*git.RemoteInfo, hostNames map[string]string) []*git.RemoteInfo {
+	var gitlabRemotes []*git.RemoteInfo
+	for _, remoteInfo := range remoteInfos {
+		name, ok := c.Cfg.FindProfile(remoteInfo.Domain)
+		if hostName, isAlias := hostNames[remoteInfo.Domain]; !ok && isAlias {
+			name, ok = c.Cfg.FindProfile(hostName)
+		}
+		if !ok {
+			continue
+		}
+		r := *remoteInfo
+		r.Domain = name
+		gitlabRemotes = append(gitlabRemotes, &r)
+	}
+	return gitlabRemotes
+}
+
 func filterHasGitlabDomain(remoteInfos []*git.RemoteInfo) []*git.RemoteInfo {
 	var gitlabRemotes []*git.RemoteInfo
 	for _, remoteInfo := range remoteInfos {
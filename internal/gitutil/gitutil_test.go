@@ -0,0 +1,128 @@
+package gitutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/lighttiger2505/lab/git"
+	"github.com/lighttiger2505/lab/internal/config"
+)
+
+func Test_parseSSHHostNames(t *testing.T) {
+	sshConfig := `# comment
+Host corp corp-alt
+  HostName code.corp.example
+  User git
+
+Host *.internal
+  HostName proxy.example
+
+Host=other
+  HostName=other.example
+  HostName ignored.example
+
+Host *
+  IdentityFile ~/.ssh/id_rsa
+`
+	got, err := parseSSHHostNames(strings.NewReader(sshConfig))
+	if err != nil {
+		t.Fatalf("parseSSHHostNames() error = %v", err)
+	}
+	want := map[string]string{
+		"corp":     "code.corp.example",
+		"corp-alt": "code.corp.example",
+		"other":    "other.example",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("parseSSHHostNames() differs: (-got +want)\n%s", diff)
+	}
+}
+
+func TestRemoteCollecter_filterHasProfile(t *testing.T) {
+	c := &RemoteCollecter{
+		Cfg: &config.Config{
+			Profiles: map[string]config.Profile{
+				"code.corp.example": config.Profile{
+					Hosts: []string{"ssh.code.corp.example"},
+				},
+			},
+		},
+	}
+	hostNames := map[string]string{
+		"corp": "code.corp.example",
+	}
+	remotes := []*git.RemoteInfo{
+		&git.RemoteInfo{Remote: "origin", Domain: "code.corp.example", Group: "group", Repository: "repo"},
+		&git.RemoteInfo{Remote: "ssh", Domain: "ssh.code.corp.example", Group: "group", Repository: "repo"},
+		&git.RemoteInfo{Remote: "alias", Domain: "corp", Group: "group", Repository: "repo"},
+		&git.RemoteInfo{Remote: "github", Domain: "github.com", Group: "group", Repository: "repo"},
+	}
+
+	got := c.filterHasProfile(remotes, hostNames)
+	want := []*git.RemoteInfo{
+		&git.RemoteInfo{Remote: "origin", Domain: "code.corp.example", Group: "group", Repository: "repo"},
+		&git.RemoteInfo{Remote: "ssh", Domain: "code.corp.example", Group: "group", Repository: "repo"},
+		&git.RemoteInfo{Remote: "alias", Domain: "code.corp.example", Group: "group", Repository: "repo"},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("RemoteCollecter.filterHasProfile() differs: (-got +want)\n%s", diff)
+	}
+	if remotes[1].Domain != "ssh.code.corp.example" {
+		t.Errorf("filterHasProfile() changed the given remote, got %s", remotes[1].Domain)
+	}
+}
+
+func TestGitLabProjectInfo_ApiUrl(t *testing.T) {
+	tests := []struct {
+		name  string
+		pInfo *GitLabProjectInfo
+		want  string
+	}{
+		{
+			name:  "domain",
+			pInfo: &GitLabProjectInfo{Domain: "gitlab.com"},
+			want:  "https://gitlab.com/api/v4",
+		},
+		{
+			name:  "base url",
+			pInfo: &GitLabProjectInfo{Domain: "code.corp.example", URL: "http://code.corp.example:8080/gitlab/"},
+			want:  "http://code.corp.example:8080/gitlab/api/v4",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.pInfo.ApiUrl(); got != tt.want {
+				t.Errorf("GitLabProjectInfo.ApiUrl() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitLabProjectInfo_setProfile(t *testing.T) {
+	tests := []struct {
+		name   string
+		domain string
+		want   string
+	}{
+		{
+			name:   "same domain",
+			domain: "gitlab.com",
+			want:   "token",
+		},
+		{
+			name:   "other domain",
+			domain: "code.corp.example",
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pInfo := &GitLabProjectInfo{Domain: "gitlab.com", Token: "token"}
+			pInfo.setProfile(tt.domain, &config.Profile{})
+			if pInfo.Token != tt.want {
+				t.Errorf("GitLabProjectInfo.setProfile() token = %v, want %v", pInfo.Token, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package gitutil
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var sshConfigPath = filepath.Join(os.Getenv("HOME"), ".ssh", "config")
+
+// loadSSHHostNames reads the host aliases of the ssh config. A missing
+// config has no alias.
+func loadSSHHostNames(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot open ssh config, %s", err)
+	}
+	defer file.Close()
+	return parseSSHHostNames(file)
+}
+
+// parseSSHHostNames maps each alias of a "Host" section to the "HostName" of
+// the section. Patterns are skipped because they can not be matched against
+// the host of a git remote reliably.
+func parseSSHHostNames(r io.Reader) (map[string]string, error) {
+	hostNames := map[string]string{}
+	var aliases []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(strings.Replace(line, "=", " ", 1))
+		if len(fields) < 2 {
+			continue
+		}
+		switch strings.ToLower(fields[0]) {
+		case "host":
+			aliases = nil
+			for _, alias := range fields[1:] {
+				if !strings.ContainsAny(alias, "*?!") {
+					aliases = append(aliases, alias)
+				}
+			}
+		case "match":
+			aliases = nil
+		case "hostname":
+			// Like ssh, the first obtained value is used
+			for _, alias := range aliases {
+				if _, ok := hostNames[alias]; !ok {
+					hostNames[alias] = fields[1]
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read ssh config, %s", err)
+	}
+	return hostNames, nil
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 )
 
@@ -125,5 +126,12 @@ func (rw *BasicUi) Error(message string) {
 }
 
 func (rw *BasicUi) Machine(t string, args ...string) {
+	rw.l.Lock()
+	defer rw.l.Unlock()
+
 	log.Printf("machine readable: %s %#v", t, args)
+	_, err := fmt.Fprint(rw.Writer, strings.Join(args, "\n")+"\n")
+	if err != nil {
+		log.Printf("[ERR] Failed to write to UI: %s", err)
+	}
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"strings"
 	"sync"
 )
 
@@ -45,5 +46,5 @@ func (u *MockUi) Error(message string) {
 }
 
 func (u *MockUi) Machine(t string, args ...string) {
-	return
+	fmt.Fprint(u.Writer, strings.Join(args, "\n")+"\n")
 }
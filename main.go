@@ -10,13 +10,17 @@ import (
 	"github.com/lighttiger2505/lab/commands"
 	configcmd "github.com/lighttiger2505/lab/commands/config"
 	"github.com/lighttiger2505/lab/commands/issue"
+	"github.com/lighttiger2505/lab/commands/label"
 	"github.com/lighttiger2505/lab/commands/milestone"
 	"github.com/lighttiger2505/lab/commands/mr"
 	"github.com/lighttiger2505/lab/commands/pipeline"
 	"github.com/lighttiger2505/lab/commands/runner"
+	"github.com/lighttiger2505/lab/commands/schedule"
+	"github.com/lighttiger2505/lab/commands/trigger"
 	"github.com/lighttiger2505/lab/git"
 	"github.com/lighttiger2505/lab/internal/api"
 	"github.com/lighttiger2505/lab/internal/browse"
+	"github.com/lighttiger2505/lab/internal/cache"
 	"github.com/lighttiger2505/lab/internal/config"
 	"github.com/lighttiger2505/lab/internal/gitutil"
 	"github.com/lighttiger2505/lab/internal/ui"
@@ -61,6 +65,7 @@ func realMain(writer io.Writer, ver, rev string) int {
 		fmt.Fprintf(os.Stderr, "cannot load config, %s", err)
 	}
 	remoteCollecter := gitutil.NewRemoteCollecter(ui, cfg, git.NewGitClient())
+	cacheStore := cache.NewStore(cfg.CacheDir())
 
 	c.Commands = map[string]cli.CommandFactory{
 		"browse": func() (cli.Command, error) {
@@ -77,20 +82,25 @@ func realMain(writer io.Writer, ver, rev string) int {
 				UI:              ui,
 				RemoteCollecter: remoteCollecter,
 				MethodFactory:   &issue.IssueMethodFactory{},
+				Cache:           cacheStore,
 			}, nil
 		},
 		"merge-request": func() (cli.Command, error) {
 			return &mr.MergeRequestCommand{
 				UI:              ui,
 				RemoteCollecter: remoteCollecter,
+				GitClient:       git.NewGitClient(),
 				ClientFactory:   &api.GitlabClientFactory{},
+				Cache:           cacheStore,
 			}, nil
 		},
 		"mr": func() (cli.Command, error) {
 			return &mr.MergeRequestCommand{
 				UI:              ui,
 				RemoteCollecter: remoteCollecter,
+				GitClient:       git.NewGitClient(),
 				ClientFactory:   &api.GitlabClientFactory{},
+				Cache:           cacheStore,
 			}, nil
 		},
 		"project": func() (cli.Command, error) {
@@ -104,7 +114,8 @@ func realMain(writer io.Writer, ver, rev string) int {
 			return &pipeline.PipelineCommand{
 				UI:              ui,
 				RemoteCollecter: remoteCollecter,
-				MethodFactory:   &pipeline.PipelineMethodFacotry{},
+				MethodFactory:   &pipeline.PipelineMethodFacotry{UI: ui},
+				Cache:           cacheStore,
 			}, nil
 		},
 		"job": func() (cli.Command, error) {
@@ -169,6 +180,35 @@ func realMain(writer io.Writer, ver, rev string) int {
 				ClientFactory:   &api.GitlabClientFactory{},
 			}, nil
 		},
+		"label": func() (cli.Command, error) {
+			return &label.LabelCommand{
+				UI:              ui,
+				RemoteCollecter: remoteCollecter,
+				ClientFactory:   &api.GitlabClientFactory{},
+			}, nil
+		},
+		"pipeline-schedule": func() (cli.Command, error) {
+			return &schedule.PipelineScheduleCommand{
+				UI:              ui,
+				RemoteCollecter: remoteCollecter,
+				ClientFactory:   &api.GitlabClientFactory{},
+			}, nil
+		},
+		"trigger": func() (cli.Command, error) {
+			return &trigger.TriggerCommand{
+				UI:              ui,
+				RemoteCollecter: remoteCollecter,
+				ClientFactory:   &api.GitlabClientFactory{},
+			}, nil
+		},
+		"sync": func() (cli.Command, error) {
+			return &commands.SyncCommand{
+				UI:              ui,
+				RemoteCollecter: remoteCollecter,
+				ClientFactory:   &api.GitlabClientFactory{},
+				Cache:           cacheStore,
+			}, nil
+		},
 	}
 
 	exitStatus, err := c.Run()